@@ -0,0 +1,112 @@
+package pflag
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ArgsOptions controls how ToArgs reconstructs the command line.
+type ArgsOptions struct {
+	// All includes every flag instead of only the flags that were changed
+	All bool
+}
+
+// ToArgs returns a command line that, when parsed by an identical FlagSet,
+// reproduces the current values of the changed flags (or every flag with
+// opts.All), followed by the non-flag arguments. Values are always written
+// in the --flag=value form so they can start with a dash. Deprecated flags
+// with a replacement are left out, as the replacement holds their values.
+// Slice and map flags without items are left out too, unless an empty value
+// is needed to clear a default and the flag reads its values as CSV, as
+// StringSlice does; others can not be emptied on the command line.
+// Secret flags are left out too, so secrets never end up on a command line.
+// A "--" is written only where the parsed arguments had one.
+func (f *FlagSet) ToArgs(opts ArgsOptions) []string {
+	var out []string
+	f.VisitAll(func(flag *Flag) {
+		if !opts.All && !flag.Changed {
+			return
+		}
+		if d := flag.Deprecation; d != nil && d.Replacement != "" {
+			return
+		}
+		if _, ok := flag.Value.(*secretValue); ok {
+			return
+		}
+		out = append(out, flagToArgs(flag)...)
+	})
+
+	dash := f.argsLenAtDash
+	if dash < 0 {
+		return append(out, f.args...)
+	}
+
+	out = append(out, f.args[:dash]...)
+	out = append(out, "--")
+	return append(out, f.args[dash:]...)
+}
+
+// flagToArgs returns the arguments that set flag to its current value.
+func flagToArgs(flag *Flag) []string {
+	long := "--" + flag.Name
+
+	switch v := flag.Value.(type) {
+	case *countValue:
+		n := int(*v)
+		if n > 0 && flag.Default == "0" && flag.Short != "" && flag.ShortDeprecated == "" {
+			return []string{"-" + strings.Repeat(flag.Short, n)}
+		}
+		return []string{long + "=" + strconv.Itoa(n)}
+	case *stringArrayValue, *regexpSliceValue:
+		// neither splits its argument at commas, so each item is a flag of its own
+		items := v.(SliceValue).GetSlice()
+		out := make([]string, len(items))
+		for i, item := range items {
+			out[i] = long + "=" + item
+		}
+		return out
+	case SliceValue:
+		items := v.GetSlice()
+		if len(items) == 0 && (flag.Default == "[]" || !emptyCSV(flag.Value)) {
+			return nil
+		}
+		s, _ := writeAsCSV(items)
+		return []string{long + "=" + s}
+	case MapValue:
+		m := v.GetMap()
+		if len(m) == 0 {
+			return nil
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = k + "=" + m[k]
+		}
+		s, _ := writeAsCSV(pairs)
+		return []string{long + "=" + s}
+	}
+
+	value := flag.Value.String()
+	if flag.NoOptDefVal != "" && value == flag.NoOptDefVal {
+		return []string{long}
+	}
+	return []string{long + "=" + value}
+}
+
+// emptyCSV reports whether v reads its values as CSV, so an empty value sets
+// no items instead of being rejected.
+func emptyCSV(v Value) bool {
+	switch v.(type) {
+	case *stringSliceValue, *boolSliceValue, *ipSliceValue,
+		*extendedDurationSliceValue, *hardwareAddrSliceValue, *hostPortSliceValue,
+		*addrSliceValue, *addrPortSliceValue, *prefixSliceValue, *pathSliceValue,
+		*timeSliceValue, *urlSliceValue:
+		return true
+	}
+	return false
+}
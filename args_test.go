@@ -0,0 +1,144 @@
+package pflag_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpArgsFlagSet() *pflag.FlagSet {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.SortFlags = false
+	f.StringP("name", "n", "", "name")
+	f.BoolP("force", "f", false, "force")
+	f.CountP("verbose", "v", "verbosity")
+	f.Int("workers", 1, "workers")
+	f.Duration("timeout", time.Second, "timeout")
+	f.StringSlice("tags", []string{}, "tags")
+	f.StringArray("exclude", []string{}, "exclude patterns")
+	f.IntSlice("ports", []int{}, "ports")
+	f.StringToString("labels", map[string]string{}, "labels")
+	f.StringToInt("limits", map[string]int{}, "limits")
+	f.String("mode", "auto", "mode")
+	f.Lookup("mode").NoOptDefVal = "on"
+	return f
+}
+
+func TestToArgs(t *testing.T) {
+	f := setUpArgsFlagSet()
+	require.NoError(t, f.Parse([]string{
+		"-vvv", "-f", "--name", "-odd", "--tags=a,b c,\"d,e\"",
+		"--exclude=x,y", "--exclude=z", "--ports=80,443",
+		"--labels=k1=v1,\"k2=v,2\"", "--limits=a=1", "--mode",
+		"pos1", "--", "--pos2",
+	}))
+
+	args := f.ToArgs(pflag.ArgsOptions{})
+	require.Equal(t, []string{
+		"--name=-odd", "--force", "-vvv", "--tags=a,b c,\"d,e\"",
+		"--exclude=x,y", "--exclude=z", "--ports=80,443",
+		"--labels=k1=v1,\"k2=v,2\"", "--limits=a=1", "--mode",
+		"pos1", "--", "--pos2",
+	}, args)
+
+	g := setUpArgsFlagSet()
+	require.NoError(t, g.Parse(args))
+	f.VisitAll(func(flag *pflag.Flag) {
+		other := g.Lookup(flag.Name)
+		require.Equal(t, flag.Changed, other.Changed, flag.Name)
+		if mv, ok := flag.Value.(pflag.MapValue); ok {
+			require.Equal(t, mv.GetMap(), other.Value.(pflag.MapValue).GetMap(), flag.Name)
+			return
+		}
+		require.Equal(t, flag.Value.String(), other.Value.String(), flag.Name)
+	})
+	require.Equal(t, f.Args(), g.Args())
+	require.Equal(t, f.ArgsLenAtDash(), g.ArgsLenAtDash())
+}
+
+func TestToArgs_All(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.SortFlags = false
+	f.Bool("force", false, "force")
+	f.Int("workers", 2, "workers")
+	f.StringSlice("tags", []string{}, "tags")
+	require.NoError(t, f.Parse([]string{"-"}))
+
+	require.Equal(t, []string{"-"}, f.ToArgs(pflag.ArgsOptions{}))
+	require.Equal(t, []string{"--force=false", "--workers=2", "-"}, f.ToArgs(pflag.ArgsOptions{All: true}))
+}
+
+func TestToArgs_DashArgs(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.SetInterspersed(false)
+	f.Int("workers", 2, "workers")
+	require.NoError(t, f.Parse([]string{"--workers=3", "cmd", "--workers=4"}))
+
+	args := f.ToArgs(pflag.ArgsOptions{})
+	require.Equal(t, []string{"--workers=3", "cmd", "--workers=4"}, args)
+
+	g := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	g.SetInterspersed(false)
+	g.Int("workers", 2, "workers")
+	require.NoError(t, g.Parse(args))
+	require.Equal(t, f.Args(), g.Args())
+	require.Equal(t, -1, g.ArgsLenAtDash())
+}
+
+func TestToArgs_Empty(t *testing.T) {
+	setUp := func() *pflag.FlagSet {
+		f := setUpArgsFlagSet()
+		f.StringSlice("names", []string{"a"}, "names")
+		f.IntSlice("sizes", []int{1, 2}, "sizes")
+		f.StringToInt("weights", map[string]int{"a": 1}, "weights")
+		return f
+	}
+
+	f := setUp()
+	require.NoError(t, f.Parse([]string{"--tags=", "--names="}))
+	require.NoError(t, f.Lookup("sizes").Value.(pflag.SliceValue).Replace(nil))
+	require.NoError(t, f.Lookup("weights").Value.(pflag.MapValue).ReplaceMap(map[string]string{}))
+
+	// only the CSV slice with a default can be emptied on the command line
+	args := f.ToArgs(pflag.ArgsOptions{All: true})
+	require.Contains(t, args, "--names=")
+	for _, arg := range args {
+		require.NotRegexp(t, `^--(tags|ports|labels|limits|sizes|weights)=`, arg)
+	}
+
+	g := setUp()
+	require.NoError(t, g.Parse(args))
+	require.Equal(t, "[]", g.Lookup("names").Value.String())
+	require.Equal(t, "[]", g.Lookup("tags").Value.String())
+
+	// the parser still rejects an empty value for other slices
+	require.Error(t, g.Parse([]string{"--sizes="}))
+}
+
+func TestToArgs_Deprecated(t *testing.T) {
+	setUp := func() *pflag.FlagSet {
+		f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		f.SetOutput(io.Discard)
+		f.Int("workers", 1, "workers")
+		f.Int("threads", 1, "threads")
+		f.Bool("legacy", false, "legacy mode")
+		require.NoError(t, f.MarkDeprecatedWith("threads", pflag.Deprecation{Message: "use --workers", Replacement: "workers"}))
+		require.NoError(t, f.MarkDeprecated("legacy", "it has no effect"))
+		return f
+	}
+
+	f := setUp()
+	require.NoError(t, f.Parse([]string{"--threads=4", "--legacy"}))
+	args := f.ToArgs(pflag.ArgsOptions{})
+	require.Equal(t, []string{"--legacy", "--workers=4"}, args)
+
+	g := setUp()
+	require.NoError(t, g.Parse(args))
+	require.False(t, g.Changed("threads"))
+	got, err := g.GetInt("workers")
+	require.NoError(t, err)
+	require.Equal(t, 4, got)
+}
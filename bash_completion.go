@@ -0,0 +1,145 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GenBashCompletion writes a bash completion script for the flags in the
+// FlagSet to w. The program name is the base name of the FlagSet name.
+func (f *FlagSet) GenBashCompletion(w io.Writer) error {
+	program := f.completionProgram()
+	fn := "_" + completionIdentifier(program) + "_completions"
+	flags := f.completionFlags()
+
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, "# bash completion for %s -*- shell-script -*-\n\n", program)
+	_, _ = fmt.Fprintf(buf, "%s()\n{\n", fn)
+	buf.WriteString(`    local cur prev ext
+    COMPREPLY=()
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    # --flag=value is split on '=' by COMP_WORDBREAKS
+    if [[ "$cur" == "=" ]]; then
+        cur=""
+    elif [[ "$prev" == "=" && $COMP_CWORD -gt 1 ]]; then
+        prev="${COMP_WORDS[COMP_CWORD-2]}"
+    fi
+
+    case "$prev" in
+`)
+	for _, cf := range flags {
+		if !cf.takesValue {
+			continue
+		}
+		_, _ = fmt.Fprintf(buf, "        %s)\n", strings.Join(bashFlagNames(cf), "|"))
+		switch {
+		case len(cf.values) > 0:
+			_, _ = fmt.Fprintf(buf, "            COMPREPLY=( $(compgen -W %s -- \"$cur\") )\n", shellQuote(strings.Join(cf.values, " ")))
+		case cf.dirs:
+			buf.WriteString("            COMPREPLY=( $(compgen -d -- \"$cur\") )\n")
+		case len(cf.extensions) > 0:
+			_, _ = fmt.Fprintf(buf, "            for ext in %s; do\n", strings.Join(cf.extensions, " "))
+			buf.WriteString("                COMPREPLY+=( $(compgen -f -X \"!*.$ext\" -- \"$cur\") )\n")
+			buf.WriteString("            done\n")
+			buf.WriteString("            COMPREPLY+=( $(compgen -d -- \"$cur\") )\n")
+		case cf.files:
+			buf.WriteString("            COMPREPLY=( $(compgen -f -- \"$cur\") )\n")
+		}
+		buf.WriteString("            return\n            ;;\n")
+	}
+	buf.WriteString("    esac\n\n")
+
+	var words []string
+	for _, cf := range flags {
+		words = append(words, bashFlagNames(cf)...)
+	}
+	buf.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	_, _ = fmt.Fprintf(buf, "        COMPREPLY=( $(compgen -W %s -- \"$cur\") )\n", shellQuote(strings.Join(words, " ")))
+	buf.WriteString("        return\n    fi\n}\n\n")
+	_, _ = fmt.Fprintf(buf, "complete -o default -F %s %s\n", fn, program)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func bashFlagNames(cf completionFlag) []string {
+	names := []string{"--" + cf.name}
+	if cf.short != "" {
+		names = append(names, "-"+cf.short)
+	}
+	return names
+}
+
+// shellQuote quotes s for a POSIX shell using single quotes.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// GenBashDynamicCompletion writes a bash completion script to w that asks
+// the program itself for candidates through CompleteCommand, so values can
+// come from a CompletionFunc.
+func (f *FlagSet) GenBashDynamicCompletion(w io.Writer) error {
+	program := f.completionProgram()
+	fn := "_" + completionIdentifier(program) + "_complete"
+
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, "# bash completion for %s -*- shell-script -*-\n\n", program)
+	_, _ = fmt.Fprintf(buf, "%s()\n{\n", fn)
+	_, _ = fmt.Fprintf(buf, `    local cur words line directive ext
+    local -a lines candidates
+    COMPREPLY=()
+
+    # Split the line up to the cursor on whitespace only, as the program
+    # expects --flag=value to be a single word.
+    read -r -a words <<< "${COMP_LINE:0:$COMP_POINT}"
+    if [[ "${COMP_LINE:$COMP_POINT-1:1}" == " " ]]; then
+        words+=("")
+    fi
+
+    local IFS=$'\n'
+    lines=($("${words[0]}" %s "${words[@]:1}" 2>/dev/null))
+    (( ${#lines[@]} > 0 )) || return
+    directive=${lines[${#lines[@]}-1]#:}
+    unset 'lines[${#lines[@]}-1]'
+
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    [[ "$cur" == "=" ]] && cur=""
+
+    (( directive & %d )) && return
+    (( directive & %d )) && compopt -o nospace
+
+    if (( directive & %d )); then
+        COMPREPLY=($(compgen -d -- "$cur"))
+        return
+    fi
+    if (( directive & %d )); then
+        for ext in "${lines[@]}"; do
+            COMPREPLY+=($(compgen -f -X "!*.$ext" -- "$cur"))
+        done
+        COMPREPLY+=($(compgen -d -- "$cur"))
+        return
+    fi
+
+    for line in "${lines[@]}"; do
+        line="${line%%%%$'\t'*}"
+        # bash splits --flag=value on '=', so only the value is completed
+        [[ "${words[${#words[@]}-1]}" == -*=* ]] && line="${line#*=}"
+        candidates+=("$line")
+    done
+    COMPREPLY=($(compgen -W "${candidates[*]}" -- "$cur"))
+
+    if (( ${#COMPREPLY[@]} == 0 && !(directive & %d) )); then
+        compopt -o default
+    fi
+}
+
+complete -F %s %s
+`, CompleteCommand, CompletionError, CompletionNoSpace, CompletionFilterDirs, CompletionFilterFileExt, CompletionNoFileComp, fn, program)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
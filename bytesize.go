@@ -0,0 +1,204 @@
+package pflag
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ByteSizeRounding decides how a byte size with a fraction of a byte, such as
+// 1.0001kB, is turned into a whole number of bytes.
+type ByteSizeRounding int
+
+const (
+	// RoundNearest rounds to the nearest byte, halves away from zero
+	RoundNearest ByteSizeRounding = iota
+	// RoundDown drops the fraction
+	RoundDown
+	// RoundUp rounds up to the next whole byte
+	RoundUp
+	// RoundExact rejects sizes that are not a whole number of bytes
+	RoundExact
+)
+
+type byteUnit struct {
+	symbol string
+	size   uint64
+}
+
+// byteUnits are the units byte sizes are formatted with, largest first.
+var byteUnits = []byteUnit{
+	{"EiB", 1 << 60}, {"EB", 1e18},
+	{"PiB", 1 << 50}, {"PB", 1e15},
+	{"TiB", 1 << 40}, {"TB", 1e12},
+	{"GiB", 1 << 30}, {"GB", 1e9},
+	{"MiB", 1 << 20}, {"MB", 1e6},
+	{"KiB", 1 << 10}, {"kB", 1e3},
+}
+
+// byteSizeMultipliers maps the lower case suffixes accepted by ParseByteSize
+// to their size. A bare prefix such as G is an SI unit.
+var byteSizeMultipliers = map[string]uint64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "ki": 1 << 10, "kib": 1 << 10,
+	"m": 1e6, "mb": 1e6, "mi": 1 << 20, "mib": 1 << 20,
+	"g": 1e9, "gb": 1e9, "gi": 1 << 30, "gib": 1 << 30,
+	"t": 1e12, "tb": 1e12, "ti": 1 << 40, "tib": 1 << 40,
+	"p": 1e15, "pb": 1e15, "pi": 1 << 50, "pib": 1 << 50,
+	"e": 1e18, "eb": 1e18, "ei": 1 << 60, "eib": 1 << 60,
+}
+
+// ParseByteSize parses a size such as "512", "10MiB", "1.5G" or "64 kB" into
+// a number of bytes. SI suffixes (kB, MB, G, T, ...) are powers of 1000, IEC
+// suffixes (KiB, MiB, Gi, Ti, ...) powers of 1024, suffixes are case
+// insensitive. Fractions of a byte are rounded as given.
+func ParseByteSize(s string, rounding ByteSizeRounding) (uint64, error) {
+	str := strings.TrimSpace(s)
+	i := strings.IndexFunc(str, func(r rune) bool {
+		return r != '.' && !unicode.IsDigit(r)
+	})
+	if i < 0 {
+		i = len(str)
+	}
+	number, unit := str[:i], strings.ToLower(strings.TrimSpace(str[i:]))
+	if number == "" {
+		return 0, fmt.Errorf("invalid byte size: %q", s)
+	}
+
+	multiplier, ok := byteSizeMultipliers[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q in byte size %q", str[i:], s)
+	}
+	r, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size: %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).SetUint64(multiplier)))
+
+	n, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		switch rounding {
+		case RoundExact:
+			return 0, fmt.Errorf("byte size %q is not a whole number of bytes", s)
+		case RoundUp:
+			n.Add(n, big.NewInt(1))
+		case RoundNearest:
+			if rem.Lsh(rem, 1).Cmp(r.Denom()) >= 0 {
+				n.Add(n, big.NewInt(1))
+			}
+		}
+	}
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("byte size %q overflows uint64", s)
+	}
+	return n.Uint64(), nil
+}
+
+// FormatByteSize formats a number of bytes with the largest SI or IEC unit it
+// is a whole multiple of, such as "64MiB" or "1500B".
+func FormatByteSize(n uint64) string {
+	for _, unit := range byteUnits {
+		if n >= unit.size && n%unit.size == 0 {
+			return strconv.FormatUint(n/unit.size, 10) + unit.symbol
+		}
+	}
+	return strconv.FormatUint(n, 10) + "B"
+}
+
+func byteSizeRounding(rounding []ByteSizeRounding) ByteSizeRounding {
+	if len(rounding) == 0 {
+		return RoundNearest
+	}
+	return rounding[0]
+}
+
+// -- byteSize Value
+type byteSizeValue struct {
+	value    *uint64
+	rounding ByteSizeRounding
+}
+
+func newByteSizeValue(val uint64, p *uint64, rounding []ByteSizeRounding) *byteSizeValue {
+	*p = val
+	return &byteSizeValue{value: p, rounding: byteSizeRounding(rounding)}
+}
+
+func (b *byteSizeValue) Set(s string) error {
+	v, err := ParseByteSize(s, b.rounding)
+	if err != nil {
+		return err
+	}
+	*b.value = v
+	return nil
+}
+
+func (b *byteSizeValue) Type() string {
+	return "byteSize"
+}
+
+func (b *byteSizeValue) String() string { return FormatByteSize(*b.value) }
+
+func byteSizeConv(sval string) (interface{}, error) {
+	return ParseByteSize(sval, RoundExact)
+}
+
+// GetByteSize return the byte size value of a flag with the given name
+func (f *FlagSet) GetByteSize(name string) (uint64, error) {
+	val, err := f.getFlagType(name, "byteSize", byteSizeConv)
+	if err != nil {
+		return 0, err
+	}
+	return val.(uint64), nil
+}
+
+// ByteSizeVar defines a byte size flag with specified name, default value, and usage string.
+// The argument p points to a uint64 variable in which to store the number of bytes.
+// Fractions of a byte are rounded to the nearest byte unless a rounding is given.
+func (f *FlagSet) ByteSizeVar(p *uint64, name string, value uint64, usage string, rounding ...ByteSizeRounding) {
+	f.VarP(newByteSizeValue(value, p, rounding), name, "", usage)
+}
+
+// ByteSizeVarP is like ByteSizeVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) ByteSizeVarP(p *uint64, name, shorthand string, value uint64, usage string, rounding ...ByteSizeRounding) {
+	f.VarP(newByteSizeValue(value, p, rounding), name, shorthand, usage)
+}
+
+// ByteSizeVar defines a byte size flag with specified name, default value, and usage string.
+// The argument p points to a uint64 variable in which to store the number of bytes.
+// Fractions of a byte are rounded to the nearest byte unless a rounding is given.
+func ByteSizeVar(p *uint64, name string, value uint64, usage string, rounding ...ByteSizeRounding) {
+	CommandLine.VarP(newByteSizeValue(value, p, rounding), name, "", usage)
+}
+
+// ByteSizeVarP is like ByteSizeVar, but accepts a shorthand letter that can be used after a single dash.
+func ByteSizeVarP(p *uint64, name, shorthand string, value uint64, usage string, rounding ...ByteSizeRounding) {
+	CommandLine.VarP(newByteSizeValue(value, p, rounding), name, shorthand, usage)
+}
+
+// ByteSize defines a byte size flag with specified name, default value, and usage string.
+// The return value is the address of a uint64 variable that stores the number of bytes.
+func (f *FlagSet) ByteSize(name string, value uint64, usage string, rounding ...ByteSizeRounding) *uint64 {
+	p := new(uint64)
+	f.ByteSizeVarP(p, name, "", value, usage, rounding...)
+	return p
+}
+
+// ByteSizeP is like ByteSize, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) ByteSizeP(name, shorthand string, value uint64, usage string, rounding ...ByteSizeRounding) *uint64 {
+	p := new(uint64)
+	f.ByteSizeVarP(p, name, shorthand, value, usage, rounding...)
+	return p
+}
+
+// ByteSize defines a byte size flag with specified name, default value, and usage string.
+// The return value is the address of a uint64 variable that stores the number of bytes.
+func ByteSize(name string, value uint64, usage string, rounding ...ByteSizeRounding) *uint64 {
+	return CommandLine.ByteSizeP(name, "", value, usage, rounding...)
+}
+
+// ByteSizeP is like ByteSize, but accepts a shorthand letter that can be used after a single dash.
+func ByteSizeP(name, shorthand string, value uint64, usage string, rounding ...ByteSizeRounding) *uint64 {
+	return CommandLine.ByteSizeP(name, shorthand, value, usage, rounding...)
+}
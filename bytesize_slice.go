@@ -0,0 +1,158 @@
+package pflag
+
+import (
+	"strings"
+)
+
+// -- byteSizeSlice Value
+type byteSizeSliceValue struct {
+	value    *[]uint64
+	rounding ByteSizeRounding
+	changed  bool
+}
+
+func newByteSizeSliceValue(val []uint64, p *[]uint64, rounding []ByteSizeRounding) *byteSizeSliceValue {
+	bssv := &byteSizeSliceValue{value: p, rounding: byteSizeRounding(rounding)}
+	*bssv.value = val
+	return bssv
+}
+
+func (s *byteSizeSliceValue) Set(val string) error {
+	ss := strings.Split(val, ",")
+	out := make([]uint64, len(ss))
+	for i, d := range ss {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+	s.changed = true
+	return nil
+}
+
+func (s *byteSizeSliceValue) Type() string {
+	return "byteSizeSlice"
+}
+
+func (s *byteSizeSliceValue) String() string {
+	return "[" + strings.Join(s.GetSlice(), ",") + "]"
+}
+
+func (s *byteSizeSliceValue) fromString(val string) (uint64, error) {
+	return ParseByteSize(val, s.rounding)
+}
+
+func (s *byteSizeSliceValue) toString(val uint64) string {
+	return FormatByteSize(val)
+}
+
+func (s *byteSizeSliceValue) Append(val string) error {
+	i, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, i)
+	return nil
+}
+
+func (s *byteSizeSliceValue) Replace(val []string) error {
+	out := make([]uint64, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *byteSizeSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+func byteSizeSliceConv(val string) (interface{}, error) {
+	val = strings.Trim(val, "[]")
+	// Empty string would cause a slice with one (empty) entry
+	if len(val) == 0 {
+		return []uint64{}, nil
+	}
+	ss := strings.Split(val, ",")
+	out := make([]uint64, len(ss))
+	for i, d := range ss {
+		var err error
+		out[i], err = ParseByteSize(d, RoundExact)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// GetByteSizeSlice returns the []uint64 value of a byteSizeSlice flag with the given name
+func (f *FlagSet) GetByteSizeSlice(name string) ([]uint64, error) {
+	val, err := f.getFlagType(name, "byteSizeSlice", byteSizeSliceConv)
+	if err != nil {
+		return []uint64{}, err
+	}
+	return val.([]uint64), nil
+}
+
+// ByteSizeSliceVar defines a byteSizeSlice flag with specified name, default value, and usage string.
+// The argument p points to a []uint64 variable in which to store the number of bytes of each size.
+func (f *FlagSet) ByteSizeSliceVar(p *[]uint64, name string, value []uint64, usage string, rounding ...ByteSizeRounding) {
+	f.VarP(newByteSizeSliceValue(value, p, rounding), name, "", usage)
+}
+
+// ByteSizeSliceVarP is like ByteSizeSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) ByteSizeSliceVarP(p *[]uint64, name, shorthand string, value []uint64, usage string, rounding ...ByteSizeRounding) {
+	f.VarP(newByteSizeSliceValue(value, p, rounding), name, shorthand, usage)
+}
+
+// ByteSizeSliceVar defines a byteSizeSlice flag with specified name, default value, and usage string.
+// The argument p points to a []uint64 variable in which to store the number of bytes of each size.
+func ByteSizeSliceVar(p *[]uint64, name string, value []uint64, usage string, rounding ...ByteSizeRounding) {
+	CommandLine.VarP(newByteSizeSliceValue(value, p, rounding), name, "", usage)
+}
+
+// ByteSizeSliceVarP is like ByteSizeSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func ByteSizeSliceVarP(p *[]uint64, name, shorthand string, value []uint64, usage string, rounding ...ByteSizeRounding) {
+	CommandLine.VarP(newByteSizeSliceValue(value, p, rounding), name, shorthand, usage)
+}
+
+// ByteSizeSlice defines a byteSizeSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []uint64 variable that stores the number of bytes of each size.
+func (f *FlagSet) ByteSizeSlice(name string, value []uint64, usage string, rounding ...ByteSizeRounding) *[]uint64 {
+	p := []uint64{}
+	f.ByteSizeSliceVarP(&p, name, "", value, usage, rounding...)
+	return &p
+}
+
+// ByteSizeSliceP is like ByteSizeSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) ByteSizeSliceP(name, shorthand string, value []uint64, usage string, rounding ...ByteSizeRounding) *[]uint64 {
+	p := []uint64{}
+	f.ByteSizeSliceVarP(&p, name, shorthand, value, usage, rounding...)
+	return &p
+}
+
+// ByteSizeSlice defines a byteSizeSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []uint64 variable that stores the number of bytes of each size.
+func ByteSizeSlice(name string, value []uint64, usage string, rounding ...ByteSizeRounding) *[]uint64 {
+	return CommandLine.ByteSizeSliceP(name, "", value, usage, rounding...)
+}
+
+// ByteSizeSliceP is like ByteSizeSlice, but accepts a shorthand letter that can be used after a single dash.
+func ByteSizeSliceP(name, shorthand string, value []uint64, usage string, rounding ...ByteSizeRounding) *[]uint64 {
+	return CommandLine.ByteSizeSliceP(name, shorthand, value, usage, rounding...)
+}
@@ -0,0 +1,118 @@
+package pflag_test
+
+import (
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		rounding pflag.ByteSizeRounding
+		expected uint64
+		ok       bool
+	}{
+		{"0", pflag.RoundNearest, 0, true},
+		{"512", pflag.RoundNearest, 512, true},
+		{"512B", pflag.RoundNearest, 512, true},
+		{"10kB", pflag.RoundNearest, 10000, true},
+		{"10KiB", pflag.RoundNearest, 10240, true},
+		{"10MiB", pflag.RoundNearest, 10 << 20, true},
+		{"10mib", pflag.RoundNearest, 10 << 20, true},
+		{"1.5G", pflag.RoundNearest, 1500000000, true},
+		{"1.5Gi", pflag.RoundNearest, 3 << 29, true},
+		{"2T", pflag.RoundNearest, 2e12, true},
+		{" 64 MB ", pflag.RoundNearest, 64e6, true},
+		{"16EiB", pflag.RoundNearest, 0, false},
+		{"18446744073709551615", pflag.RoundNearest, 18446744073709551615, true},
+		{"18446744073709551616", pflag.RoundNearest, 0, false},
+		{"1.0005kB", pflag.RoundNearest, 1001, true},
+		{"1.0004kB", pflag.RoundNearest, 1000, true},
+		{"1.0009kB", pflag.RoundDown, 1000, true},
+		{"1.0001kB", pflag.RoundUp, 1001, true},
+		{"1.0001kB", pflag.RoundExact, 0, false},
+		{"1.5kB", pflag.RoundExact, 1500, true},
+		{"", pflag.RoundNearest, 0, false},
+		{"MB", pflag.RoundNearest, 0, false},
+		{"-1MB", pflag.RoundNearest, 0, false},
+		{"10XB", pflag.RoundNearest, 0, false},
+		{"1.2.3MB", pflag.RoundNearest, 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := pflag.ParseByteSize(tc.input, tc.rounding)
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	require.Equal(t, "0B", pflag.FormatByteSize(0))
+	require.Equal(t, "1500B", pflag.FormatByteSize(1500))
+	require.Equal(t, "64MiB", pflag.FormatByteSize(64<<20))
+	require.Equal(t, "1MB", pflag.FormatByteSize(1e6))
+	require.Equal(t, "1536KiB", pflag.FormatByteSize(1536<<10))
+	require.Equal(t, "2TB", pflag.FormatByteSize(2e12))
+	require.Equal(t, "18446744073709551615B", pflag.FormatByteSize(18446744073709551615))
+}
+
+func TestByteSize(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	buffer := f.ByteSize("buffer", 64<<20, "buffer size")
+	var limit uint64
+	f.ByteSizeVarP(&limit, "limit", "l", 0, "upload limit", pflag.RoundExact)
+
+	require.NoError(t, f.Parse([]string{}))
+	require.Equal(t, uint64(64<<20), *buffer)
+
+	require.NoError(t, f.Parse([]string{"--buffer=1.5G", "-l", "10kB"}))
+	require.Equal(t, uint64(1500000000), *buffer)
+	require.Equal(t, uint64(10000), limit)
+	require.Equal(t, "1500MB", f.Lookup("buffer").Value.String())
+
+	got, err := f.GetByteSize("buffer")
+	require.NoError(t, err)
+	require.Equal(t, *buffer, got)
+
+	require.Error(t, f.Parse([]string{"--limit=1.0001kB"}))
+	require.Error(t, f.Parse([]string{"--buffer=20EB"}))
+}
+
+func TestByteSizeSlice(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	sizes := f.ByteSizeSlice("sizes", []uint64{1 << 10}, "sizes")
+
+	require.NoError(t, f.Parse([]string{"--sizes=1MiB,2kB", "--sizes", "512"}))
+	require.Equal(t, []uint64{1 << 20, 2000, 512}, *sizes)
+	require.Equal(t, "[1MiB,2kB,512B]", f.Lookup("sizes").Value.String())
+
+	got, err := f.GetByteSizeSlice("sizes")
+	require.NoError(t, err)
+	require.Equal(t, *sizes, got)
+
+	sv, ok := f.Lookup("sizes").Value.(pflag.SliceValue)
+	require.True(t, ok)
+	require.NoError(t, sv.Replace([]string{"1G"}))
+	require.Equal(t, []string{"1GB"}, sv.GetSlice())
+	require.Error(t, sv.Append("1Q"))
+}
+
+func TestByteSizeUsage(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.ByteSize("buffer", 64<<20, "buffer size")
+	f.ByteSize("limit", 0, "upload limit")
+	f.ByteSizeSlice("sizes", nil, "sizes")
+
+	usages := f.FlagUsages()
+	require.Contains(t, usages, "buffer size (default 64MiB)\n")
+	require.Contains(t, usages, "upload limit\n")
+	require.Contains(t, usages, "sizes\n")
+}
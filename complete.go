@@ -0,0 +1,271 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rsb/failure"
+)
+
+// CompleteCommand is the hidden first argument with which the dynamic
+// completion scripts ask the program for candidates. The remaining arguments
+// are the words typed so far, the last one being the word under the cursor.
+const CompleteCommand = "__complete"
+
+// CompletionDirective tells the shell what to do with the candidates.
+type CompletionDirective int
+
+const (
+	// CompletionDefault lets the shell fall back to file completion when
+	// there are no candidates
+	CompletionDefault CompletionDirective = 0
+	// CompletionError indicates that completion failed and nothing should be offered
+	CompletionError CompletionDirective = 1 << (iota - 1)
+	// CompletionNoSpace keeps the shell from adding a space after the candidate
+	CompletionNoSpace
+	// CompletionNoFileComp keeps the shell from falling back to file completion
+	CompletionNoFileComp
+	// CompletionFilterFileExt treats the candidates as file extensions to complete
+	CompletionFilterFileExt
+	// CompletionFilterDirs completes directory names only
+	CompletionFilterDirs
+)
+
+// Completion is a candidate for the word under the cursor.
+type Completion struct {
+	Value       string
+	Description string
+}
+
+// CompletionFunc returns the candidates for the value of flag, or for a
+// positional argument when flag is nil, that start with toComplete.
+type CompletionFunc func(flag *Flag, toComplete string) ([]Completion, CompletionDirective)
+
+// RegisterCompletionFunc sets the function that completes the value of the
+// named flag at runtime.
+func (f *FlagSet) RegisterCompletionFunc(name string, fn CompletionFunc) error {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return failure.NotFound("flag (%s), does not exist", name)
+	}
+
+	flag.Completer = fn
+	return nil
+}
+
+// SetArgsCompletionFunc sets the function that completes positional arguments.
+func (f *FlagSet) SetArgsCompletionFunc(fn CompletionFunc) {
+	f.argsCompleter = fn
+}
+
+// Complete answers a completion request from a dynamic completion script.
+// When args starts with CompleteCommand the candidates for the remaining
+// words are written to w, one "value<TAB>description" per line followed by
+// ":<directive>", and true is returned. Otherwise nothing is written and
+// false is returned. It is meant to be called before Parse:
+//
+//	if fs.Complete(os.Stdout, os.Args[1:]) {
+//		os.Exit(0)
+//	}
+func (f *FlagSet) Complete(w io.Writer, args []string) bool {
+	if len(args) == 0 || args[0] != CompleteCommand {
+		return false
+	}
+
+	completions, directive := f.Completions(args[1:])
+
+	buf := new(bytes.Buffer)
+	for _, c := range completions {
+		buf.WriteString(c.Value)
+		if c.Description != "" {
+			buf.WriteString("\t" + c.Description)
+		}
+		buf.WriteString("\n")
+	}
+	_, _ = fmt.Fprintf(buf, ":%d\n", directive)
+
+	_, _ = w.Write(buf.Bytes())
+	return true
+}
+
+// Completions returns the candidates for the last element of args, given
+// the words before it. An empty last element completes a new word.
+func (f *FlagSet) Completions(args []string) ([]Completion, CompletionDirective) {
+	toComplete := ""
+	if len(args) > 0 {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	// Find out whether the cursor is on the value of a flag given as a
+	// separate word, or past the point where flags are parsed.
+	var pending *Flag
+	positional := false
+	for _, arg := range args {
+		if pending != nil {
+			pending = nil
+			continue
+		}
+		if positional {
+			continue
+		}
+
+		switch {
+		case arg == "--":
+			positional = true
+		case strings.HasPrefix(arg, "--"):
+			if strings.Contains(arg, "=") {
+				continue
+			}
+			if flag := f.Lookup(arg[2:]); flag != nil && flag.NoOptDefVal == "" {
+				pending = flag
+			}
+		case len(arg) > 1 && arg[0] == '-':
+			for i := 1; i < len(arg); i++ {
+				flag := f.shorts[arg[i]]
+				if flag == nil {
+					break
+				}
+				if flag.NoOptDefVal == "" {
+					if i == len(arg)-1 {
+						pending = flag
+					}
+					break
+				}
+			}
+		default:
+			if !f.interspersed {
+				positional = true
+			}
+		}
+	}
+
+	switch {
+	case pending != nil:
+		return f.completeValue(pending, "", toComplete)
+	case positional || toComplete == "" || toComplete[0] != '-':
+		return f.completeArgs(toComplete)
+	case strings.HasPrefix(toComplete, "--"):
+		if i := strings.Index(toComplete, "="); i >= 0 {
+			flag := f.Lookup(toComplete[2:i])
+			if flag == nil {
+				return nil, CompletionError
+			}
+			return f.completeValue(flag, toComplete[:i+1], toComplete[i+1:])
+		}
+		return f.completeFlagNames(toComplete)
+	case toComplete == "-":
+		return f.completeFlagNames(toComplete)
+	}
+	return f.completeShorthands(toComplete)
+}
+
+// completeShorthands completes a cluster of shorthands such as "-vx". When
+// one of the flags in the cluster takes a value, the rest of the word is
+// that value.
+func (f *FlagSet) completeShorthands(toComplete string) ([]Completion, CompletionDirective) {
+	for i := 1; i < len(toComplete); i++ {
+		flag := f.shorts[toComplete[i]]
+		if flag == nil {
+			return nil, CompletionError
+		}
+		if flag.NoOptDefVal != "" {
+			continue
+		}
+
+		rest := toComplete[i+1:]
+		switch {
+		case rest == "":
+			return []Completion{{Value: toComplete, Description: completionUsage(flag)}}, CompletionNoFileComp
+		case rest[0] == '=':
+			return f.completeValue(flag, toComplete[:i+2], rest[1:])
+		}
+		return f.completeValue(flag, toComplete[:i+1], rest)
+	}
+
+	var out []Completion
+	if flag := f.shorts[toComplete[len(toComplete)-1]]; flag != nil {
+		out = append(out, Completion{Value: toComplete, Description: completionUsage(flag)})
+	}
+	for _, cf := range f.completionFlags() {
+		if cf.short == "" || strings.Contains(toComplete[1:], cf.short) && !cf.repeatable {
+			continue
+		}
+		out = append(out, Completion{Value: toComplete + cf.short, Description: cf.usage})
+	}
+	return out, CompletionNoFileComp
+}
+
+func (f *FlagSet) completeFlagNames(toComplete string) ([]Completion, CompletionDirective) {
+	var out []Completion
+	for _, cf := range f.completionFlags() {
+		if long := "--" + cf.name; strings.HasPrefix(long, toComplete) {
+			out = append(out, Completion{Value: long, Description: cf.usage})
+		}
+		if toComplete == "-" && cf.short != "" {
+			out = append(out, Completion{Value: "-" + cf.short, Description: cf.usage})
+		}
+	}
+	return out, CompletionNoFileComp
+}
+
+// completeValue completes the value of flag. The prefix is whatever precedes
+// the value in the word under the cursor, such as "--flag=".
+func (f *FlagSet) completeValue(flag *Flag, prefix, toComplete string) ([]Completion, CompletionDirective) {
+	if flag.Completer != nil {
+		completions, directive := flag.Completer(flag, toComplete)
+		if directive&(CompletionFilterFileExt|CompletionFilterDirs) != 0 || prefix == "" {
+			return completions, directive
+		}
+		out := make([]Completion, len(completions))
+		for i, c := range completions {
+			out[i] = Completion{Value: prefix + c.Value, Description: c.Description}
+		}
+		return out, directive
+	}
+
+	if _, ok := flag.Annotations[CompletionDirectory]; ok {
+		return nil, CompletionFilterDirs
+	}
+	if exts, ok := flag.Annotations[CompletionFilenameExt]; ok && len(exts) > 0 {
+		out := make([]Completion, len(exts))
+		for i, ext := range exts {
+			out[i] = Completion{Value: strings.TrimPrefix(ext, ".")}
+		}
+		return out, CompletionFilterFileExt
+	}
+
+	values := flag.Annotations[CompletionValues]
+	if bf, ok := flag.Value.(boolFlag); ok && bf.IsBoolFlag() && len(values) == 0 {
+		values = []string{"true", "false"}
+	}
+	if len(values) == 0 {
+		return nil, CompletionDefault
+	}
+
+	var out []Completion
+	for _, v := range values {
+		if strings.HasPrefix(v, toComplete) {
+			out = append(out, Completion{Value: prefix + v})
+		}
+	}
+	return out, CompletionNoFileComp
+}
+
+func (f *FlagSet) completeArgs(toComplete string) ([]Completion, CompletionDirective) {
+	if f.argsCompleter == nil {
+		return nil, CompletionDefault
+	}
+	return f.argsCompleter(nil, toComplete)
+}
+
+// completionUsage returns the first line of the usage of flag.
+func completionUsage(flag *Flag) string {
+	_, usage := UnquoteUsage(flag)
+	if i := strings.IndexByte(usage, '\n'); i >= 0 {
+		usage = usage[:i]
+	}
+	return usage
+}
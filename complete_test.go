@@ -0,0 +1,81 @@
+package pflag_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpCompleteFlagSet() *pflag.FlagSet {
+	f := setUpCompletionFlagSet()
+	f.StringP("cluster", "k", "", "cluster to use")
+	_ = f.RegisterCompletionFunc("cluster", func(flag *pflag.Flag, toComplete string) ([]pflag.Completion, pflag.CompletionDirective) {
+		var out []pflag.Completion
+		for _, c := range []string{"prod", "staging", "dev"} {
+			if strings.HasPrefix(c, toComplete) {
+				out = append(out, pflag.Completion{Value: c, Description: c + " cluster"})
+			}
+		}
+		return out, pflag.CompletionNoFileComp
+	})
+	f.SetArgsCompletionFunc(func(flag *pflag.Flag, toComplete string) ([]pflag.Completion, pflag.CompletionDirective) {
+		return []pflag.Completion{{Value: "start"}, {Value: "stop"}}, pflag.CompletionNoFileComp
+	})
+	return f
+}
+
+func completionValues(completions []pflag.Completion) []string {
+	out := make([]string, len(completions))
+	for i, c := range completions {
+		out[i] = c.Value
+	}
+	return out
+}
+
+func TestCompletions(t *testing.T) {
+	testCases := []struct {
+		name      string
+		args      []string
+		expected  []string
+		directive pflag.CompletionDirective
+	}{
+		{"flag names", []string{"--c"}, []string{"--cluster", "--color", "--config"}, pflag.CompletionNoFileComp},
+		{"all flags", []string{"-"}, []string{"--cluster", "-k", "--color", "--config", "-c", "--data-dir", "--force", "-f", "--format", "--name", "--tags", "--user", "--verbose", "-v"}, pflag.CompletionNoFileComp},
+		{"value in next word", []string{"--cluster", "st"}, []string{"staging"}, pflag.CompletionNoFileComp},
+		{"value after equals", []string{"--cluster=d"}, []string{"--cluster=dev"}, pflag.CompletionNoFileComp},
+		{"value of shorthand", []string{"-k", ""}, []string{"prod", "staging", "dev"}, pflag.CompletionNoFileComp},
+		{"value attached to shorthand", []string{"-fkp"}, []string{"-fkprod"}, pflag.CompletionNoFileComp},
+		{"value of shorthand after equals", []string{"-k=p"}, []string{"-k=prod"}, pflag.CompletionNoFileComp},
+		{"shorthand cluster", []string{"-fv"}, []string{"-fv", "-fvk", "-fvc", "-fvv"}, pflag.CompletionNoFileComp},
+		{"fixed values", []string{"--format", "y"}, []string{"yaml"}, pflag.CompletionNoFileComp},
+		{"bool value", []string{"--force="}, []string{"--force=true", "--force=false"}, pflag.CompletionNoFileComp},
+		{"directories", []string{"--data-dir", ""}, []string{}, pflag.CompletionFilterDirs},
+		{"extensions", []string{"-c", ""}, []string{"yaml", "json"}, pflag.CompletionFilterFileExt},
+		{"free value", []string{"--name", ""}, []string{}, pflag.CompletionDefault},
+		{"positional", []string{"--force", ""}, []string{"start", "stop"}, pflag.CompletionNoFileComp},
+		{"after dash dash", []string{"--", "--c"}, []string{"start", "stop"}, pflag.CompletionNoFileComp},
+		{"unknown flag", []string{"--nope=x"}, []string{}, pflag.CompletionError},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			completions, directive := setUpCompleteFlagSet().Completions(tt.args)
+			require.Equal(t, tt.expected, completionValues(completions))
+			require.Equal(t, tt.directive, directive)
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	f := setUpCompleteFlagSet()
+
+	var buf bytes.Buffer
+	require.False(t, f.Complete(&buf, []string{"--cluster", "prod"}))
+	require.Empty(t, buf.String())
+
+	require.True(t, f.Complete(&buf, []string{pflag.CompleteCommand, "--cluster", ""}))
+	require.Equal(t, "prod\tprod cluster\nstaging\tstaging cluster\ndev\tdev cluster\n:4\n", buf.String())
+}
@@ -0,0 +1,121 @@
+package pflag
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/rsb/failure"
+)
+
+// Annotation keys understood by the completion generators. They are set with
+// SetAnnotation or the helpers below.
+const (
+	// CompletionFilenameExt completes the value of a flag with file names. When
+	// values are given only files with one of those extensions are offered.
+	CompletionFilenameExt = "pflag_completion_filename_extensions"
+	// CompletionDirectory completes the value of a flag with directory names only.
+	CompletionDirectory = "pflag_completion_directory"
+	// CompletionValues completes the value of a flag from a fixed list.
+	CompletionValues = "pflag_completion_values"
+)
+
+// MarkFilename completes the value of the named flag with file names having
+// one of the given extensions, or any file when no extension is given.
+func (f *FlagSet) MarkFilename(name string, extensions ...string) error {
+	return f.SetAnnotation(name, CompletionFilenameExt, extensions)
+}
+
+// MarkDirname completes the value of the named flag with directory names.
+func (f *FlagSet) MarkDirname(name string) error {
+	return f.SetAnnotation(name, CompletionDirectory, []string{})
+}
+
+// SetCompletionValues completes the value of the named flag with values.
+func (f *FlagSet) SetCompletionValues(name string, values ...string) error {
+	if len(values) == 0 {
+		return failure.InvalidParam("completion values for (%s) must not be empty", name)
+	}
+	return f.SetAnnotation(name, CompletionValues, values)
+}
+
+// completionFlag is the information about a flag shared by the completion
+// generators.
+type completionFlag struct {
+	name        string
+	short       string
+	usage       string
+	takesValue  bool // the value is required, not optional through NoOptDefVal
+	optional    bool // the value is optional and must be attached with '='
+	repeatable  bool
+	values      []string
+	dirs        bool
+	files       bool
+	extensions  []string
+	placeholder string
+}
+
+// completionFlags returns every flag that should be offered for completion:
+// hidden and deprecated flags are left out, as are deprecated shorthands.
+func (f *FlagSet) completionFlags() []completionFlag {
+	var out []completionFlag
+	f.VisitAll(func(flag *Flag) {
+		if flag.Hidden || flag.Deprecated != "" {
+			return
+		}
+
+		varname, _ := UnquoteUsage(flag)
+		cf := completionFlag{
+			name:        flag.Name,
+			usage:       completionUsage(flag),
+			takesValue:  flag.NoOptDefVal == "",
+			placeholder: varname,
+		}
+		if flag.Short != "" && flag.ShortDeprecated == "" {
+			cf.short = flag.Short
+		}
+		if !cf.takesValue {
+			_, isBool := flag.Value.(boolFlag)
+			cf.optional = !isBool && flag.Value.Type() != "count"
+		}
+		switch flag.Value.(type) {
+		case SliceValue, MapValue, *countValue:
+			cf.repeatable = true
+		}
+		if cf.placeholder == "" {
+			cf.placeholder = flag.Value.Type()
+		}
+
+		if values, ok := flag.Annotations[CompletionValues]; ok {
+			cf.values = values
+		}
+		if _, ok := flag.Annotations[CompletionDirectory]; ok {
+			cf.dirs = true
+		}
+		if exts, ok := flag.Annotations[CompletionFilenameExt]; ok {
+			cf.files = true
+			for _, ext := range exts {
+				cf.extensions = append(cf.extensions, strings.TrimPrefix(ext, "."))
+			}
+		}
+
+		out = append(out, cf)
+	})
+	return out
+}
+
+// completionProgram returns the name of the program as typed by the user.
+func (f *FlagSet) completionProgram() string {
+	return filepath.Base(f.name)
+}
+
+// completionIdentifier turns the program name into something that can be
+// used as a shell function name.
+func completionIdentifier(program string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, program)
+}
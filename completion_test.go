@@ -0,0 +1,82 @@
+package pflag_test
+
+import (
+	"bytes"
+	goflag "flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+var update = goflag.Bool("update", false, "update golden files")
+
+func setUpCompletionFlagSet() *pflag.FlagSet {
+	f := pflag.NewFlagSet("/usr/local/bin/my-app", pflag.ContinueOnError)
+	f.StringP("config", "c", "", "config `file` to load")
+	f.String("format", "json", "output format [json|yaml]")
+	f.String("data-dir", "/var/lib/app", "directory for it's data")
+	f.String("name", "", "name of the app")
+	f.BoolP("force", "f", false, "skip confirmation")
+	f.CountP("verbose", "v", "increase verbosity")
+	f.StringSlice("tags", []string{}, "tags to apply")
+	f.String("color", "auto", "colorize output")
+	f.Lookup("color").NoOptDefVal = "always"
+	f.String("secret", "", "hidden flag")
+	f.Bool("old", false, "deprecated flag")
+	f.StringP("user", "u", "", "user to run as")
+
+	_ = f.MarkFilename("config", "yaml", ".json")
+	_ = f.SetCompletionValues("format", "json", "yaml")
+	_ = f.MarkDirname("data-dir")
+	_ = f.MarkHidden("secret")
+	_ = f.MarkDeprecated("old", "use --force")
+	_ = f.MarkShortDeprecated("user", "use --user")
+	return f
+}
+
+func assertGolden(t *testing.T, name string, actual []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+func TestCompletion(t *testing.T) {
+	testCases := []struct {
+		golden string
+		gen    func(f *pflag.FlagSet, w io.Writer) error
+	}{
+		{"completion/bash.golden", (*pflag.FlagSet).GenBashCompletion},
+		{"completion/zsh.golden", (*pflag.FlagSet).GenZshCompletion},
+		{"completion/fish.golden", (*pflag.FlagSet).GenFishCompletion},
+		{"completion/powershell.golden", (*pflag.FlagSet).GenPowerShellCompletion},
+		{"completion/bash_dynamic.golden", (*pflag.FlagSet).GenBashDynamicCompletion},
+		{"completion/zsh_dynamic.golden", (*pflag.FlagSet).GenZshDynamicCompletion},
+		{"completion/fish_dynamic.golden", (*pflag.FlagSet).GenFishDynamicCompletion},
+		{"completion/powershell_dynamic.golden", (*pflag.FlagSet).GenPowerShellDynamicCompletion},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.golden, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, tt.gen(setUpCompletionFlagSet(), &buf))
+			assertGolden(t, tt.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestCompletionAnnotations(t *testing.T) {
+	f := setUpCompletionFlagSet()
+	require.Equal(t, []string{"yaml", ".json"}, f.Lookup("config").Annotations[pflag.CompletionFilenameExt])
+	require.Error(t, f.SetCompletionValues("format"))
+	require.Error(t, f.MarkDirname("missing"))
+}
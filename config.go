@@ -0,0 +1,511 @@
+package pflag
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rsb/failure"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFormat identifies the encoding of a config source.
+type ConfigFormat int
+
+const (
+	// ConfigJSON is a JSON object keyed by flag name
+	ConfigJSON ConfigFormat = iota
+	// ConfigYAML is a YAML mapping keyed by flag name
+	ConfigYAML
+	// ConfigDotenv is a list of KEY=VALUE lines, where FLAG_NAME maps to
+	// --flag-name. Flags whose name has other characters than lower case
+	// letters, digits and dashes can not be exported in it.
+	ConfigDotenv
+	// ConfigTemplate is YAML with the usage of every flag written as a comment
+	ConfigTemplate
+)
+
+func (c ConfigFormat) String() string {
+	switch c {
+	case ConfigJSON:
+		return "json"
+	case ConfigYAML:
+		return "yaml"
+	case ConfigDotenv:
+		return "dotenv"
+	case ConfigTemplate:
+		return "template"
+	}
+	return fmt.Sprintf("ConfigFormat(%d)", int(c))
+}
+
+// ConfigFormatFromPath guesses the format of a config file from its extension.
+func ConfigFormatFromPath(path string) (ConfigFormat, error) {
+	base := filepath.Base(path)
+	switch strings.ToLower(filepath.Ext(base)) {
+	case ".json":
+		return ConfigJSON, nil
+	case ".yaml", ".yml":
+		return ConfigYAML, nil
+	case ".env":
+		return ConfigDotenv, nil
+	}
+	if base == ".env" {
+		return ConfigDotenv, nil
+	}
+	return 0, failure.InvalidParam("unable to determine config format of (%s)", path)
+}
+
+// FlagChange describes a flag whose value was modified by a config source.
+type FlagChange struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// ParseConfig reads flag values from r and applies them through Value.Set.
+// Flags already set on the command line are left untouched, and values from
+// the config do not mark a flag as Changed. Either every value is applied or,
+// when one of them is invalid, none is.
+func (f *FlagSet) ParseConfig(r io.Reader, format ConfigFormat) error {
+	doc, err := f.readConfig(r, format)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.applyConfig(doc, func(flag *Flag) bool { return !flag.Changed })
+	return err
+}
+
+// ParseConfigFile is like ParseConfig, but reads the named file and
+// determines the format from its extension.
+func (f *FlagSet) ParseConfigFile(path string) error {
+	format, err := ConfigFormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return failure.ToConfig(err, "unable to open config file (%s)", path)
+	}
+	defer func() { _ = file.Close() }()
+
+	return f.ParseConfig(file, format)
+}
+
+// applyConfig sets every flag named in doc for which fn returns true. Keys
+// are applied in sorted order, and when any of them fails the values already
+// applied are rolled back before the error is returned.
+func (f *FlagSet) applyConfig(doc map[string]interface{}, fn func(flag *Flag) bool) ([]FlagChange, error) {
+	keys := make([]string, 0, len(doc))
+	for k := range doc {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	flags := make([]*Flag, 0, len(keys))
+	values := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		flag := f.Lookup(k)
+		if flag == nil {
+			if f.ParseErrorsWhitelist.UnknownFlags {
+				continue
+			}
+			return nil, failure.NotFound("unknown flag in config: %s", k)
+		}
+		if !fn(flag) {
+			continue
+		}
+		if flag.Sensitive && doc[k] == Redacted {
+			continue
+		}
+		flags = append(flags, flag)
+		values = append(values, doc[k])
+	}
+
+	// values of deprecated flags are forwarded to their replacements, as by
+	// Set, unless the config sets the replacement itself
+	given := make(map[*Flag]bool, len(flags))
+	for _, flag := range flags {
+		given[flag] = true
+	}
+	for i := 0; i < len(flags); i++ {
+		flag := flags[i]
+		if err := f.checkRemoved(flag); err != nil {
+			return nil, err
+		}
+		d := flag.Deprecation
+		if d == nil || d.Replacement == "" {
+			continue
+		}
+		replacement := f.Lookup(d.Replacement)
+		if replacement == nil || given[replacement] || !fn(replacement) {
+			continue
+		}
+		value, err := f.forwardConfigValue(flag, values[i])
+		if err != nil {
+			return nil, err
+		}
+		given[replacement] = true
+		flags = append(flags, replacement)
+		values = append(values, value)
+	}
+
+	var changes []FlagChange
+	applied := make([]valueSnapshot, 0, len(flags))
+	for i, flag := range flags {
+		applied = append(applied, snapshotValue(flag))
+		old := flag.Value.String()
+		secret, isSecret := flag.Value.(*secretValue)
+		oldSecret := ""
+		if isSecret {
+			oldSecret = *secret.value
+		}
+		if err := setConfigValue(flag, values[i]); err != nil {
+			if rerr := restoreSnapshots(applied); rerr != nil {
+				return nil, failure.InvalidState("invalid config value for %q flag: %v, and unable to roll back: %v", flag.Name, err, rerr)
+			}
+			return nil, failure.InvalidParam("invalid config value for %q flag: %v", flag.Name, err)
+		}
+
+		current := flag.Value.String()
+		changed := current != old
+		if isSecret {
+			// both are Redacted, compare the secrets
+			changed = *secret.value != oldSecret
+		}
+		if changed {
+			changes = append(changes, FlagChange{Name: flag.Name, Old: old, New: current})
+		}
+	}
+
+	for _, flag := range flags {
+		if flag.Deprecated != "" {
+			f.warnDeprecated(DeprecationWarning{
+				Flag:    flag,
+				Message: f.message(MsgFlagDeprecated, flag.Name, flag.Deprecated),
+			})
+		}
+	}
+	return changes, nil
+}
+
+// forwardConfigValue converts a config value of a deprecated flag into a
+// value of its replacement with the Transform of the deprecation.
+func (f *FlagSet) forwardConfigValue(flag *Flag, value interface{}) (interface{}, error) {
+	transform := flag.Deprecation.Transform
+	if transform == nil {
+		return value, nil
+	}
+
+	var items []string
+	switch v := value.(type) {
+	case string:
+		out, err := transform(v)
+		if err != nil {
+			return nil, f.invalidArgument(flag, "--"+flag.Name, v, err)
+		}
+		return out, nil
+	case []string:
+		items = v
+	case map[string]string:
+		for k, item := range v {
+			items = append(items, k+"="+item)
+		}
+		sort.Strings(items)
+	}
+
+	out := make([]string, len(items))
+	for i, item := range items {
+		var err error
+		if out[i], err = transform(item); err != nil {
+			return nil, f.invalidArgument(flag, "--"+flag.Name, item, err)
+		}
+	}
+	return out, nil
+}
+
+// setConfigValue stores a decoded config value, which is either a string,
+// a []string or a map[string]string, into the flag.
+func setConfigValue(flag *Flag, value interface{}) error {
+	switch v := value.(type) {
+	case []string:
+		if sv, ok := flag.Value.(SliceValue); ok {
+			return sv.Replace(v)
+		}
+		if mv, ok := flag.Value.(MapValue); ok {
+			m, err := pairsToMap(v)
+			if err != nil {
+				return err
+			}
+			return mv.ReplaceMap(m)
+		}
+		s, err := writeAsCSV(v)
+		if err != nil {
+			return err
+		}
+		return flag.Value.Set(s)
+	case map[string]string:
+		if mv, ok := flag.Value.(MapValue); ok {
+			return mv.ReplaceMap(v)
+		}
+		return fmt.Errorf("a map is not a valid value for a %s flag", flag.Value.Type())
+	case string:
+		if rv, ok := flag.Value.(*regexpSliceValue); ok {
+			// patterns are not split at commas, as in Set
+			return rv.Replace([]string{v})
+		}
+		if sv, ok := flag.Value.(SliceValue); ok {
+			ss, err := readAsCSV(v)
+			if err != nil {
+				return err
+			}
+			return sv.Replace(ss)
+		}
+		if mv, ok := flag.Value.(MapValue); ok {
+			ss, err := readAsCSV(v)
+			if err != nil {
+				return err
+			}
+			m, err := pairsToMap(ss)
+			if err != nil {
+				return err
+			}
+			return mv.ReplaceMap(m)
+		}
+		return flag.Value.Set(v)
+	}
+	return fmt.Errorf("unsupported config value %v", value)
+}
+
+func pairsToMap(pairs []string) (map[string]string, error) {
+	out := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%s must be formatted as key=value", pair)
+		}
+		out[kv[0]] = kv[1]
+	}
+	return out, nil
+}
+
+// stateValue is implemented by Values that can not be put back by parsing
+// their String again, as their Set checks or opens files or their String
+// hides the value. saveState returns a func that puts the current state back
+// as it is, it may be called more than once.
+type stateValue interface {
+	saveState() func()
+}
+
+// valueSnapshot remembers the value of a flag so it can be put back after a
+// failed update.
+type valueSnapshot struct {
+	flag  *Flag
+	undo  func()
+	str   string
+	slice []string
+	dict  map[string]string
+}
+
+func snapshotValue(flag *Flag) valueSnapshot {
+	snap := valueSnapshot{flag: flag}
+	switch v := flag.Value.(type) {
+	case stateValue:
+		snap.undo = v.saveState()
+	case SliceValue:
+		snap.slice = v.GetSlice()
+	case MapValue:
+		snap.dict = v.GetMap()
+	default:
+		snap.str = flag.Value.String()
+	}
+	return snap
+}
+
+func (s valueSnapshot) restore() error {
+	if s.undo != nil {
+		s.undo()
+		return nil
+	}
+	switch v := s.flag.Value.(type) {
+	case SliceValue:
+		return v.Replace(s.slice)
+	case MapValue:
+		return v.ReplaceMap(s.dict)
+	}
+	return s.flag.Value.Set(s.str)
+}
+
+// restoreSnapshots puts back snaps in reverse order. It returns the first
+// error, but restores the remaining snapshots anyway.
+func restoreSnapshots(snaps []valueSnapshot) error {
+	var first error
+	for i := len(snaps) - 1; i >= 0; i-- {
+		if err := snaps[i].restore(); err != nil && first == nil {
+			first = fmt.Errorf("%q flag: %v", snaps[i].flag.Name, err)
+		}
+	}
+	return first
+}
+
+// readConfig reads a config document like decodeConfig. The dotenv value of
+// a RegexpSlice flag is split as CSV, as Export writes it, where a string in
+// the other formats is a single pattern.
+func (f *FlagSet) readConfig(r io.Reader, format ConfigFormat) (map[string]interface{}, error) {
+	doc, err := decodeConfig(r, format)
+	if err != nil || format != ConfigDotenv {
+		return doc, err
+	}
+	for k, v := range doc {
+		flag := f.Lookup(k)
+		if flag == nil {
+			continue
+		}
+		if _, ok := flag.Value.(*regexpSliceValue); !ok {
+			continue
+		}
+		patterns, err := readAsCSV(v.(string))
+		if err != nil {
+			return nil, failure.ToConfig(err, "invalid value for (%s)", k)
+		}
+		doc[k] = patterns
+	}
+	return doc, nil
+}
+
+// decodeConfig reads a config document and normalizes every value into a
+// string, a []string or a map[string]string.
+func decodeConfig(r io.Reader, format ConfigFormat) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	switch format {
+	case ConfigJSON:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil && err != io.EOF {
+			return nil, failure.ToConfig(err, "unable to decode json config")
+		}
+	case ConfigYAML, ConfigTemplate:
+		if err := yaml.NewDecoder(r).Decode(&doc); err != nil && err != io.EOF {
+			return nil, failure.ToConfig(err, "unable to decode yaml config")
+		}
+	case ConfigDotenv:
+		var err error
+		if doc, err = decodeDotenv(r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, failure.InvalidParam("unsupported config format: %s", format)
+	}
+
+	out := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		value, err := normalizeConfigValue(v)
+		if err != nil {
+			return nil, failure.ToConfig(err, "invalid value for (%s)", k)
+		}
+		out[k] = value
+	}
+	return out, nil
+}
+
+func normalizeConfigValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		out := make([]string, len(v))
+		for i, item := range v {
+			s, err := configScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = s
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]string, len(v))
+		for k, item := range v {
+			s, err := configScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = s
+		}
+		return out, nil
+	}
+	return configScalar(v)
+}
+
+func configScalar(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("nested value %v is not supported", v)
+}
+
+// decodeDotenv parses KEY=VALUE lines. Blank lines, comments and an optional
+// leading "export" are ignored. Keys are converted to flag names by lower
+// casing them and replacing '_' with '-'.
+func decodeDotenv(r io.Reader) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, failure.Config("line %d must be formatted as KEY=VALUE", n)
+		}
+
+		value := strings.TrimSpace(kv[1])
+		if len(value) >= 2 {
+			switch {
+			case value[0] == '"' && value[len(value)-1] == '"':
+				s, err := strconv.Unquote(value)
+				if err != nil {
+					return nil, failure.ToConfig(err, "line %d has an invalid quoted value", n)
+				}
+				value = s
+			case value[0] == '\'' && value[len(value)-1] == '\'':
+				value = value[1 : len(value)-1]
+			}
+		}
+
+		doc[envKeyToFlagName(strings.TrimSpace(kv[0]))] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, failure.ToConfig(err, "unable to read dotenv config")
+	}
+	return doc, nil
+}
+
+func envKeyToFlagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
@@ -0,0 +1,112 @@
+package pflag
+
+import "time"
+
+// GetDate return the date value of a flag with the given name
+func (f *FlagSet) GetDate(name string) (time.Time, error) {
+	return f.getTime(name, "date")
+}
+
+// DateVar defines a date flag with specified name, default value, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+// Values are parsed with the first matching layout, DateLayout when none are given, or as
+// relative expressions such as today or yesterday-48h. Dates are midnight in loc, UTC
+// when loc is nil.
+func (f *FlagSet) DateVar(p *time.Time, name string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeValue(kindDate, value, p, loc, layouts), name, "", usage)
+}
+
+// DateVarP is like DateVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) DateVarP(p *time.Time, name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeValue(kindDate, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// DateVar defines a date flag with specified name, default value, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+// Values are parsed with the first matching layout, DateLayout when none are given, or as
+// relative expressions such as today or yesterday-48h. Dates are midnight in loc, UTC
+// when loc is nil.
+func DateVar(p *time.Time, name string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeValue(kindDate, value, p, loc, layouts), name, "", usage)
+}
+
+// DateVarP is like DateVar, but accepts a shorthand letter that can be used after a single dash.
+func DateVarP(p *time.Time, name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeValue(kindDate, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// Date defines a date flag with specified name, default value, and usage string.
+// The return value is the address of a time.Time variable that stores the value of the flag.
+func (f *FlagSet) Date(name string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	p := new(time.Time)
+	f.DateVarP(p, name, "", value, usage, loc, layouts...)
+	return p
+}
+
+// DateP is like Date, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) DateP(name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	p := new(time.Time)
+	f.DateVarP(p, name, shorthand, value, usage, loc, layouts...)
+	return p
+}
+
+// Date defines a date flag with specified name, default value, and usage string.
+// The return value is the address of a time.Time variable that stores the value of the flag.
+func Date(name string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	return CommandLine.DateP(name, "", value, usage, loc, layouts...)
+}
+
+// DateP is like Date, but accepts a shorthand letter that can be used after a single dash.
+func DateP(name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	return CommandLine.DateP(name, shorthand, value, usage, loc, layouts...)
+}
+
+// DateSliceVar defines a dateSlice flag with specified name, default value, and usage string.
+// The argument p points to a []time.Time variable in which to store the value of the flag.
+// Each time is parsed like the value of a Date flag.
+func (f *FlagSet) DateSliceVar(p *[]time.Time, name string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeSliceValue(kindDate, value, p, loc, layouts), name, "", usage)
+}
+
+// DateSliceVarP is like DateSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) DateSliceVarP(p *[]time.Time, name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeSliceValue(kindDate, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// DateSliceVar defines a dateSlice flag with specified name, default value, and usage string.
+// The argument p points to a []time.Time variable in which to store the value of the flag.
+// Each time is parsed like the value of a Date flag.
+func DateSliceVar(p *[]time.Time, name string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeSliceValue(kindDate, value, p, loc, layouts), name, "", usage)
+}
+
+// DateSliceVarP is like DateSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func DateSliceVarP(p *[]time.Time, name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeSliceValue(kindDate, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// DateSlice defines a dateSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Time variable that stores the value of the flag.
+func (f *FlagSet) DateSlice(name string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	p := []time.Time{}
+	f.DateSliceVarP(&p, name, "", value, usage, loc, layouts...)
+	return &p
+}
+
+// DateSliceP is like DateSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) DateSliceP(name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	p := []time.Time{}
+	f.DateSliceVarP(&p, name, shorthand, value, usage, loc, layouts...)
+	return &p
+}
+
+// DateSlice defines a dateSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Time variable that stores the value of the flag.
+func DateSlice(name string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	return CommandLine.DateSliceP(name, "", value, usage, loc, layouts...)
+}
+
+// DateSliceP is like DateSlice, but accepts a shorthand letter that can be used after a single dash.
+func DateSliceP(name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	return CommandLine.DateSliceP(name, shorthand, value, usage, loc, layouts...)
+}
@@ -0,0 +1,192 @@
+package pflag
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rsb/failure"
+)
+
+// Deprecation describes how a flag is phased out.
+type Deprecation struct {
+	// Message tells the user what to do instead, it must be set
+	Message string
+	// Replacement is the name of the flag that takes over. Values given to
+	// the deprecated flag are forwarded to it, which marks it Changed when
+	// the value was given on the command line. Values from a config or the
+	// environment are not forwarded when they set the replacement too.
+	Replacement string
+	// Transform converts a value of the deprecated flag into a value of the
+	// replacement. Values are forwarded unchanged when it is nil.
+	Transform func(value string) (string, error)
+	// Since is the version of the program the flag was deprecated in
+	Since string
+	// RemovedIn is the version of the program from which using the flag is
+	// an error, see SetVersion
+	RemovedIn string
+}
+
+// DeprecationWarning is reported when a deprecated flag or shorthand is used.
+type DeprecationWarning struct {
+	Flag      *Flag
+	Shorthand bool   // the deprecated shorthand was used rather than the flag
+	Message   string // the warning in the language of the message catalog
+}
+
+// MarkDeprecatedWith marks the named flag deprecated like MarkDeprecated, and
+// can forward its values to a replacement and schedule its removal.
+func (f *FlagSet) MarkDeprecatedWith(name string, d Deprecation) error {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return failure.NotFound("flag (%s), does not exist", name)
+	}
+
+	if d.Message == "" {
+		return failure.InvalidParam("usage is empty, deprecated msg for (%s) must be set", name)
+	}
+
+	if d.Replacement != "" {
+		replacement := f.Lookup(d.Replacement)
+		if replacement == nil {
+			return failure.NotFound("replacement flag (%s), does not exist", d.Replacement)
+		}
+		if replacement == flag {
+			return failure.InvalidParam("flag (%s) can not replace itself", name)
+		}
+		// forwarding follows the replacements of replacements, which must
+		// not lead back to the flag
+		seen := map[*Flag]bool{flag: true}
+		for r := replacement; r.Deprecation != nil && r.Deprecation.Replacement != ""; {
+			seen[r] = true
+			if r = f.Lookup(r.Deprecation.Replacement); r == nil {
+				break
+			}
+			if seen[r] {
+				return failure.InvalidParam("replacing flag (%s) with (%s) creates a cycle", name, d.Replacement)
+			}
+		}
+	}
+
+	flag.Deprecated = d.Message
+	flag.Deprecation = &d
+	flag.Hidden = true
+	return nil
+}
+
+// SetVersion sets the version of the program, which decides whether flags
+// deprecated with a RemovedIn version can still be used.
+func (f *FlagSet) SetVersion(version string) {
+	f.version = version
+}
+
+// SetDeprecationHandler sets the function that is called when a deprecated
+// flag or shorthand is used. Without a handler the warning is written to the
+// output of the FlagSet.
+func (f *FlagSet) SetDeprecationHandler(fn func(DeprecationWarning)) {
+	f.deprecationHandler = fn
+}
+
+// warnDeprecated reports the use of a deprecated flag or shorthand.
+func (f *FlagSet) warnDeprecated(warning DeprecationWarning) {
+	if f.deprecationHandler != nil {
+		f.deprecationHandler(warning)
+		return
+	}
+	_, _ = f.Output().Write([]byte(warning.Message + "\n"))
+}
+
+// checkRemoved returns an error when the flag has been removed in the
+// version of the program.
+func (f *FlagSet) checkRemoved(flag *Flag) error {
+	d := flag.Deprecation
+	if d == nil || d.RemovedIn == "" || f.version == "" {
+		return nil
+	}
+	if compareVersions(f.version, d.RemovedIn) < 0 {
+		return nil
+	}
+	return failure.InvalidParam("%s", f.message(MsgFlagRemoved, flag.Name, d.RemovedIn, d.Message))
+}
+
+// forward sets the replacement of a deprecated flag to value.
+func (f *FlagSet) forward(flag *Flag, value string) error {
+	d := flag.Deprecation
+	if d == nil || d.Replacement == "" {
+		return nil
+	}
+
+	if d.Transform != nil {
+		transformed, err := d.Transform(value)
+		if err != nil {
+			return f.invalidArgument(flag, "--"+flag.Name, value, err)
+		}
+		value = transformed
+	}
+	return f.Set(d.Replacement, value)
+}
+
+// compareVersions compares two versions such as "v1.2.3" and "1.3.0-rc.1" by
+// their dot separated parts, numerically where possible. A pre-release sorts
+// before the release it precedes, build metadata is ignored.
+func compareVersions(a, b string) int {
+	parse := func(v string) (core []string, pre string) {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+		if i := strings.IndexByte(v, '+'); i >= 0 {
+			v = v[:i]
+		}
+		if i := strings.IndexByte(v, '-'); i >= 0 {
+			v, pre = v[:i], v[i+1:]
+		}
+		return strings.Split(v, "."), pre
+	}
+	comparePart := func(x, y string) int {
+		nx, errx := strconv.ParseUint(x, 10, 64)
+		ny, erry := strconv.ParseUint(y, 10, 64)
+		switch {
+		case errx == nil && erry == nil && nx < ny:
+			return -1
+		case errx == nil && erry == nil && nx > ny:
+			return 1
+		case errx == nil && erry == nil:
+			return 0
+		}
+		return strings.Compare(x, y)
+	}
+
+	coreA, preA := parse(a)
+	coreB, preB := parse(b)
+	for i := 0; i < len(coreA) || i < len(coreB); i++ {
+		x, y := "0", "0"
+		if i < len(coreA) {
+			x = coreA[i]
+		}
+		if i < len(coreB) {
+			y = coreB[i]
+		}
+		if c := comparePart(x, y); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+	partsA, partsB := strings.Split(preA, "."), strings.Split(preB, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		if c := comparePart(partsA[i], partsB[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(partsA) < len(partsB):
+		return -1
+	case len(partsA) > len(partsB):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,159 @@
+package pflag_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpDeprecationFlagSet() (*pflag.FlagSet, *[]pflag.DeprecationWarning) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Int("timeout-ms", 0, "timeout in milliseconds")
+	f.Duration("timeout", 0, "timeout")
+	f.StringP("host", "H", "", "host")
+	f.String("server", "", "server")
+	f.Bool("legacy", false, "legacy mode")
+
+	_ = f.MarkDeprecatedWith("timeout-ms", pflag.Deprecation{
+		Message:     "use --timeout",
+		Replacement: "timeout",
+		Transform:   func(v string) (string, error) { return v + "ms", nil },
+		Since:       "1.4",
+		RemovedIn:   "2.0",
+	})
+	_ = f.MarkDeprecatedWith("server", pflag.Deprecation{Message: "use --host", Replacement: "host"})
+	_ = f.MarkDeprecatedWith("legacy", pflag.Deprecation{Message: "it is the default", RemovedIn: "v1.5.0"})
+	_ = f.MarkShortDeprecated("host", "use --host")
+
+	var warnings []pflag.DeprecationWarning
+	f.SetDeprecationHandler(func(w pflag.DeprecationWarning) {
+		warnings = append(warnings, w)
+	})
+	return f, &warnings
+}
+
+func TestDeprecationForwarding(t *testing.T) {
+	f, warnings := setUpDeprecationFlagSet()
+	f.SetVersion("1.4.2")
+
+	require.NoError(t, f.Parse([]string{"--timeout-ms=250", "--server", "example.com", "-H", "other.com"}))
+
+	timeout, err := f.GetDuration("timeout")
+	require.NoError(t, err)
+	require.Equal(t, "250ms", timeout.String())
+	require.True(t, f.Changed("timeout"))
+	require.True(t, f.Changed("timeout-ms"))
+
+	host, err := f.GetString("host")
+	require.NoError(t, err)
+	require.Equal(t, "other.com", host)
+
+	require.Len(t, *warnings, 3)
+	require.Equal(t, "Flag --timeout-ms has been deprecated, use --timeout", (*warnings)[0].Message)
+	require.Equal(t, "timeout-ms", (*warnings)[0].Flag.Name)
+	require.False(t, (*warnings)[0].Shorthand)
+	require.True(t, (*warnings)[2].Shorthand)
+}
+
+func TestDeprecationRemoved(t *testing.T) {
+	testCases := []struct {
+		version string
+		removed bool
+	}{
+		{"", false},
+		{"1.4.9", false},
+		{"v1.5.0-rc.1", false},
+		{"1.5", true},
+		{"v1.10.0", true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.version, func(t *testing.T) {
+			f, _ := setUpDeprecationFlagSet()
+			f.SetVersion(tt.version)
+
+			err := f.Parse([]string{"--legacy"})
+			if !tt.removed {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "flag --legacy has been removed in v1.5.0, it is the default")
+		})
+	}
+}
+
+func TestDeprecationTransformError(t *testing.T) {
+	f, _ := setUpDeprecationFlagSet()
+	_ = f.MarkDeprecatedWith("server", pflag.Deprecation{
+		Message:     "use --host",
+		Replacement: "host",
+		Transform: func(v string) (string, error) {
+			if strings.Contains(v, "/") {
+				return "", pflag.ErrHelp
+			}
+			return v, nil
+		},
+	})
+
+	require.Error(t, f.Parse([]string{"--server=http://example.com"}))
+}
+
+func TestDeprecationWarningOutput(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Bool("old", false, "old flag")
+	_ = f.MarkDeprecated("old", "use --new")
+
+	var buf bytes.Buffer
+	f.SetOutput(&buf)
+	require.NoError(t, f.Parse([]string{"--old"}))
+	require.Equal(t, "Flag --old has been deprecated, use --new\n", buf.String())
+}
+
+func TestMarkDeprecatedWithErrors(t *testing.T) {
+	f, _ := setUpDeprecationFlagSet()
+	require.Error(t, f.MarkDeprecatedWith("missing", pflag.Deprecation{Message: "gone"}))
+	require.Error(t, f.MarkDeprecatedWith("host", pflag.Deprecation{}))
+	require.Error(t, f.MarkDeprecatedWith("host", pflag.Deprecation{Message: "gone", Replacement: "missing"}))
+	require.Error(t, f.MarkDeprecatedWith("host", pflag.Deprecation{Message: "gone", Replacement: "host"}))
+
+	// server is replaced by host already
+	err := f.MarkDeprecatedWith("host", pflag.Deprecation{Message: "gone", Replacement: "server"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "creates a cycle")
+
+	f.String("endpoint", "", "endpoint")
+	require.NoError(t, f.MarkDeprecatedWith("endpoint", pflag.Deprecation{Message: "use --server", Replacement: "server"}))
+	require.Error(t, f.MarkDeprecatedWith("host", pflag.Deprecation{Message: "gone", Replacement: "endpoint"}))
+}
+
+func TestDeprecationForwardingFromConfig(t *testing.T) {
+	f, warnings := setUpDeprecationFlagSet()
+	require.NoError(t, f.ParseConfig(strings.NewReader(`{"timeout-ms": "250", "server": "example.com"}`), pflag.ConfigJSON))
+
+	timeout, err := f.GetDuration("timeout")
+	require.NoError(t, err)
+	require.Equal(t, "250ms", timeout.String())
+	require.False(t, f.Changed("timeout"))
+
+	host, err := f.GetString("host")
+	require.NoError(t, err)
+	require.Equal(t, "example.com", host)
+	require.Len(t, *warnings, 2)
+
+	// the replacement wins when the config sets both
+	require.NoError(t, f.ParseConfig(strings.NewReader(`{"server": "old.com", "host": "new.com"}`), pflag.ConfigJSON))
+	host, err = f.GetString("host")
+	require.NoError(t, err)
+	require.Equal(t, "new.com", host)
+
+	t.Setenv("APP_TIMEOUT_MS", "500")
+	require.NoError(t, f.BindEnv("timeout-ms", "APP_TIMEOUT_MS"))
+	require.NoError(t, f.ParseEnv())
+	timeout, err = f.GetDuration("timeout")
+	require.NoError(t, err)
+	require.Equal(t, "500ms", timeout.String())
+}
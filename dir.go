@@ -0,0 +1,112 @@
+package pflag
+
+// GetDir return the directory path value of a flag with the given name
+func (f *FlagSet) GetDir(name string) (string, error) {
+	val, err := f.getFlagType(name, "dir", stringConv)
+	if err != nil {
+		return "", err
+	}
+	return val.(string), nil
+}
+
+// DirVar defines a directory path flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// Values are expanded, resolved and validated as the options request, the default value is
+// used as given. Values must be directories when they exist, and are completed with directory names.
+func (f *FlagSet) DirVar(p *string, name string, value string, usage string, opts ...PathOption) {
+	f.varPath(kindDir, p, name, "", value, usage, opts)
+}
+
+// DirVarP is like DirVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) DirVarP(p *string, name, shorthand string, value string, usage string, opts ...PathOption) {
+	f.varPath(kindDir, p, name, shorthand, value, usage, opts)
+}
+
+// DirVar defines a directory path flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// Values are expanded, resolved and validated as the options request, the default value is
+// used as given. Values must be directories when they exist, and are completed with directory names.
+func DirVar(p *string, name string, value string, usage string, opts ...PathOption) {
+	CommandLine.varPath(kindDir, p, name, "", value, usage, opts)
+}
+
+// DirVarP is like DirVar, but accepts a shorthand letter that can be used after a single dash.
+func DirVarP(p *string, name, shorthand string, value string, usage string, opts ...PathOption) {
+	CommandLine.varPath(kindDir, p, name, shorthand, value, usage, opts)
+}
+
+// Dir defines a directory path flag with specified name, default value, and usage string.
+// The return value is the address of a string variable that stores the value of the flag.
+func (f *FlagSet) Dir(name string, value string, usage string, opts ...PathOption) *string {
+	p := new(string)
+	f.DirVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// DirP is like Dir, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) DirP(name, shorthand string, value string, usage string, opts ...PathOption) *string {
+	p := new(string)
+	f.DirVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// Dir defines a directory path flag with specified name, default value, and usage string.
+// The return value is the address of a string variable that stores the value of the flag.
+func Dir(name string, value string, usage string, opts ...PathOption) *string {
+	return CommandLine.DirP(name, "", value, usage, opts...)
+}
+
+// DirP is like Dir, but accepts a shorthand letter that can be used after a single dash.
+func DirP(name, shorthand string, value string, usage string, opts ...PathOption) *string {
+	return CommandLine.DirP(name, shorthand, value, usage, opts...)
+}
+
+// DirSliceVar defines a dirSlice flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag.
+// Each path is handled like the value of a Dir flag, patterns are expanded with the PathGlob option.
+func (f *FlagSet) DirSliceVar(p *[]string, name string, value []string, usage string, opts ...PathOption) {
+	f.varPathSlice(kindDir, p, name, "", value, usage, opts)
+}
+
+// DirSliceVarP is like DirSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) DirSliceVarP(p *[]string, name, shorthand string, value []string, usage string, opts ...PathOption) {
+	f.varPathSlice(kindDir, p, name, shorthand, value, usage, opts)
+}
+
+// DirSliceVar defines a dirSlice flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag.
+// Each path is handled like the value of a Dir flag, patterns are expanded with the PathGlob option.
+func DirSliceVar(p *[]string, name string, value []string, usage string, opts ...PathOption) {
+	CommandLine.varPathSlice(kindDir, p, name, "", value, usage, opts)
+}
+
+// DirSliceVarP is like DirSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func DirSliceVarP(p *[]string, name, shorthand string, value []string, usage string, opts ...PathOption) {
+	CommandLine.varPathSlice(kindDir, p, name, shorthand, value, usage, opts)
+}
+
+// DirSlice defines a dirSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []string variable that stores the value of the flag.
+func (f *FlagSet) DirSlice(name string, value []string, usage string, opts ...PathOption) *[]string {
+	p := []string{}
+	f.DirSliceVarP(&p, name, "", value, usage, opts...)
+	return &p
+}
+
+// DirSliceP is like DirSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) DirSliceP(name, shorthand string, value []string, usage string, opts ...PathOption) *[]string {
+	p := []string{}
+	f.DirSliceVarP(&p, name, shorthand, value, usage, opts...)
+	return &p
+}
+
+// DirSlice defines a dirSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []string variable that stores the value of the flag.
+func DirSlice(name string, value []string, usage string, opts ...PathOption) *[]string {
+	return CommandLine.DirSliceP(name, "", value, usage, opts...)
+}
+
+// DirSliceP is like DirSlice, but accepts a shorthand letter that can be used after a single dash.
+func DirSliceP(name, shorthand string, value []string, usage string, opts ...PathOption) *[]string {
+	return CommandLine.DirSliceP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,235 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rsb/failure"
+)
+
+// docFlag is the information about a flag shared by the reference doc
+// generators.
+type docFlag struct {
+	anchor          string
+	name            string
+	short           string
+	shortDeprecated string
+	typ             string
+	optional        string // value used when the flag is given without one
+	usage           string
+	defaultValue    string
+	deprecated      string
+	annotations     []string // "key: v1, v2" or "key", sorted by key
+}
+
+// docFlags returns every flag that belongs in the reference docs. Hidden
+// flags are left out, unless they are hidden because they are deprecated.
+func (f *FlagSet) docFlags() []docFlag {
+	program := f.completionProgram()
+
+	var out []docFlag
+	f.VisitAll(func(flag *Flag) {
+		if flag.Hidden && flag.Deprecated == "" {
+			return
+		}
+
+		u := f.newFlagUsage(flag)
+		df := docFlag{
+			anchor:          strings.ToLower(program + "-flag-" + flag.Name),
+			name:            flag.Name,
+			short:           flag.Short,
+			shortDeprecated: flag.ShortDeprecated,
+			typ:             u.Varname,
+			usage:           u.Usage,
+			defaultValue:    u.Default,
+			deprecated:      flag.Deprecated,
+		}
+		if df.typ == "" {
+			df.typ = flag.Value.Type()
+		}
+		if u.NoOptDefVal != "" {
+			df.optional = flag.NoOptDefVal
+		}
+
+		keys := make([]string, 0, len(flag.Annotations))
+		for k := range flag.Annotations {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			annotation := k
+			if values := flag.Annotations[k]; len(values) > 0 {
+				annotation += ": " + strings.Join(values, ", ")
+			}
+			df.annotations = append(df.annotations, annotation)
+		}
+
+		out = append(out, df)
+	})
+	return out
+}
+
+// GenMarkdown writes a Markdown table describing the flags in the FlagSet to
+// w. Every row has an anchor named <program>-flag-<name>, so the docs of a
+// single flag can be linked to. Hidden flags are left out, deprecated flags
+// are listed with their deprecation message.
+func (f *FlagSet) GenMarkdown(w io.Writer) error {
+	buf := new(bytes.Buffer)
+	buf.WriteString("| Flag | Type | Default | Description |\n")
+	buf.WriteString("| ---- | ---- | ------- | ----------- |\n")
+	for _, df := range f.docFlags() {
+		_, _ = fmt.Fprintf(buf, `| <a id="%s"></a>`, df.anchor)
+		if df.short != "" {
+			_, _ = fmt.Fprintf(buf, "`-%s`, ", df.short)
+		}
+		_, _ = fmt.Fprintf(buf, "`--%s` | `%s` | ", df.name, markdownCode(df.typ))
+		if df.defaultValue != "" {
+			_, _ = fmt.Fprintf(buf, "`%s`", markdownCode(df.defaultValue))
+		}
+
+		notes := []string{markdownEscape(df.usage)}
+		if df.optional != "" {
+			notes = append(notes, fmt.Sprintf("Without a value: `%s`", markdownCode(df.optional)))
+		}
+		if df.deprecated != "" {
+			notes = append(notes, "**Deprecated:** "+markdownEscape(df.deprecated))
+		}
+		if df.shortDeprecated != "" {
+			notes = append(notes, fmt.Sprintf("**Deprecated shorthand `-%s`:** %s", df.short, markdownEscape(df.shortDeprecated)))
+		}
+		for _, a := range df.annotations {
+			notes = append(notes, fmt.Sprintf("`%s`", markdownCode(a)))
+		}
+		_, _ = fmt.Fprintf(buf, " | %s |\n", strings.Join(notes, "<br>"))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// markdownEscape escapes text for a Markdown table cell.
+func markdownEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		"|", `\|`,
+		"*", `\*`,
+		"_", `\_`,
+		"`", "\\`",
+		"[", `\[`,
+		"]", `\]`,
+		"<", "&lt;",
+		">", "&gt;",
+		"\n", "<br>",
+	).Replace(s)
+}
+
+// markdownCode escapes text for a code span in a Markdown table cell, where
+// only the column separator needs care.
+func markdownCode(s string) string {
+	return strings.NewReplacer("|", `\|`, "`", "'", "\n", " ").Replace(s)
+}
+
+// GenReST writes a reStructuredText option list describing the flags in the
+// FlagSet to w. Every option is preceded by a target named
+// <program>-flag-<name>, so the docs of a single flag can be linked to.
+// Hidden flags are left out, deprecated flags are listed with their
+// deprecation message.
+func (f *FlagSet) GenReST(w io.Writer) error {
+	buf := new(bytes.Buffer)
+	for i, df := range f.docFlags() {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		_, _ = fmt.Fprintf(buf, ".. _%s:\n\n", df.anchor)
+
+		arg := ""
+		if df.typ != "bool" && df.typ != "count" {
+			arg = "<" + df.typ + ">"
+		}
+		if df.short != "" {
+			buf.WriteString("-" + df.short)
+			if arg != "" {
+				buf.WriteString(" " + arg)
+			}
+			buf.WriteString(", ")
+		}
+		buf.WriteString("--" + df.name)
+		if arg != "" {
+			buf.WriteString("=" + arg)
+		}
+		buf.WriteString("\n")
+
+		for _, line := range strings.Split(rstEscape(df.usage), "\n") {
+			buf.WriteString(strings.TrimRight("   "+line, " ") + "\n")
+		}
+
+		var fields []string
+		fields = append(fields, fmt.Sprintf(":Type: ``%s``", df.typ))
+		if df.defaultValue != "" {
+			fields = append(fields, fmt.Sprintf(":Default: ``%s``", df.defaultValue))
+		}
+		if df.optional != "" {
+			fields = append(fields, fmt.Sprintf(":Without a value: ``%s``", df.optional))
+		}
+		if df.deprecated != "" {
+			fields = append(fields, ":Deprecated: "+rstEscape(df.deprecated))
+		}
+		if df.shortDeprecated != "" {
+			fields = append(fields, fmt.Sprintf(":Deprecated shorthand: ``-%s``, %s", df.short, rstEscape(df.shortDeprecated)))
+		}
+		for _, a := range df.annotations {
+			fields = append(fields, fmt.Sprintf(":Annotation: ``%s``", a))
+		}
+		buf.WriteString("\n")
+		for _, field := range fields {
+			buf.WriteString("   " + field + "\n")
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// rstEscape escapes the characters that start inline markup in
+// reStructuredText.
+func rstEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		"*", `\*`,
+		"`", "\\`",
+		"|", `\|`,
+		"_", `\_`,
+	).Replace(s)
+}
+
+// CheckDoc returns an error unless the file at path holds exactly what gen
+// writes. It is meant for tests and CI jobs that make sure committed docs, man
+// pages and completion scripts are not stale:
+//
+//	err := pflag.CheckDoc("docs/flags.md", fs.GenMarkdown)
+func CheckDoc(path string, gen func(w io.Writer) error) error {
+	expected := new(bytes.Buffer)
+	if err := gen(expected); err != nil {
+		return err
+	}
+
+	actual, err := os.ReadFile(path)
+	if err != nil {
+		return failure.ToNotFound(err, "unable to read doc (%s)", path)
+	}
+	if bytes.Equal(actual, expected.Bytes()) {
+		return nil
+	}
+
+	expectedLines := strings.Split(expected.String(), "\n")
+	actualLines := strings.Split(string(actual), "\n")
+	line := 1
+	for line <= len(expectedLines) && line <= len(actualLines) && expectedLines[line-1] == actualLines[line-1] {
+		line++
+	}
+	return failure.InvalidState("doc (%s) is out of date from line %d, regenerate it", path, line)
+}
@@ -0,0 +1,51 @@
+package pflag_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenDocs(t *testing.T) {
+	testCases := []struct {
+		golden string
+		gen    func(f *pflag.FlagSet, w io.Writer) error
+	}{
+		{"docs/my-app.md.golden", (*pflag.FlagSet).GenMarkdown},
+		{"docs/my-app.rst.golden", (*pflag.FlagSet).GenReST},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.golden, func(t *testing.T) {
+			f := setUpCompletionFlagSet()
+			f.String("token", "s3cr3t", "api token | *never* logged")
+			_ = f.MarkSensitive("token")
+
+			var buf bytes.Buffer
+			require.NoError(t, tt.gen(f, &buf))
+			assertGolden(t, tt.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestCheckDoc(t *testing.T) {
+	f := setUpCompletionFlagSet()
+	path := filepath.Join(t.TempDir(), "flags.md")
+
+	require.Error(t, pflag.CheckDoc(path, f.GenMarkdown))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.GenMarkdown(&buf))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	require.NoError(t, pflag.CheckDoc(path, f.GenMarkdown))
+
+	f.Int("retries", 3, "number of retries")
+	err := pflag.CheckDoc(path, f.GenMarkdown)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "from line 10")
+}
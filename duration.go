@@ -30,7 +30,13 @@ func durationConv(sval string) (interface{}, error) {
 
 // GetDuration return the duration value of a flag with the given name
 func (f *FlagSet) GetDuration(name string) (time.Duration, error) {
-	val, err := f.getFlagType(name, "duration", durationConv)
+	conv := durationConv
+	if flag := f.Lookup(name); flag != nil {
+		if _, ok := flag.Value.(*extendedDurationValue); ok {
+			conv = extendedDurationConv
+		}
+	}
+	val, err := f.getFlagType(name, "duration", conv)
 	if err != nil {
 		return 0, err
 	}
@@ -110,7 +110,13 @@ func durationSliceConv(val string) (interface{}, error) {
 
 // GetDurationSlice returns the []time.Duration value of a flag with the given name
 func (f *FlagSet) GetDurationSlice(name string) ([]time.Duration, error) {
-	val, err := f.getFlagType(name, "durationSlice", durationSliceConv)
+	conv := durationSliceConv
+	if flag := f.Lookup(name); flag != nil {
+		if _, ok := flag.Value.(*extendedDurationSliceValue); ok {
+			conv = extendedDurationSliceConv
+		}
+	}
+	val, err := f.getFlagType(name, "durationSlice", conv)
 	if err != nil {
 		return []time.Duration{}, err
 	}
@@ -0,0 +1,188 @@
+package pflag
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// EndpointOption validates or completes the values of URL and HostPort
+// flags.
+type EndpointOption func(*endpointOptions)
+
+type endpointOptions struct {
+	schemes     []string
+	requireHost bool
+	requirePort bool
+	defaultPort int
+	base        *url.URL
+}
+
+func newEndpointOptions(opts []EndpointOption) endpointOptions {
+	var o endpointOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// EndpointAllowSchemes rejects URLs with another scheme than the given ones.
+// It has no effect on HostPort flags.
+func EndpointAllowSchemes(schemes ...string) EndpointOption {
+	return func(o *endpointOptions) {
+		for _, scheme := range schemes {
+			o.schemes = append(o.schemes, strings.ToLower(scheme))
+		}
+	}
+}
+
+// EndpointRequireHost rejects values without a host.
+func EndpointRequireHost() EndpointOption {
+	return func(o *endpointOptions) {
+		o.requireHost = true
+	}
+}
+
+// EndpointRequirePort rejects values without a port, unless
+// EndpointDefaultPort is given.
+func EndpointRequirePort() EndpointOption {
+	return func(o *endpointOptions) {
+		o.requirePort = true
+	}
+}
+
+// EndpointDefaultPort adds port to values that have a host but no port.
+func EndpointDefaultPort(port int) EndpointOption {
+	return func(o *endpointOptions) {
+		o.defaultPort = port
+	}
+}
+
+// EndpointResolveAgainst resolves URLs as references relative to base, so a
+// value such as "/v2/" becomes "https://api.example.com/v2/". It has no effect
+// on HostPort flags.
+func EndpointResolveAgainst(base *url.URL) EndpointOption {
+	return func(o *endpointOptions) {
+		o.base = base
+	}
+}
+
+// parsePort parses a port number between 1 and 65535.
+func parsePort(port string) (int, error) {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return 0, fmt.Errorf("invalid port %q, must be a number between 1 and 65535", port)
+	}
+	return n, nil
+}
+
+// parseURL parses s as a URL and validates it against the options.
+func parseURL(s string, o endpointOptions) (*url.URL, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return &url.URL{}, nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("invalid URL %q: %v", s, err)
+	}
+	if o.base != nil {
+		u = o.base.ResolveReference(u)
+	}
+
+	if len(o.schemes) > 0 && !containsString(o.schemes, u.Scheme) {
+		if u.Scheme == "" {
+			return nil, fmt.Errorf("URL %q has no scheme, must be one of %s", s, strings.Join(o.schemes, ", "))
+		}
+		return nil, fmt.Errorf("URL %q has scheme %q, must be one of %s", s, u.Scheme, strings.Join(o.schemes, ", "))
+	}
+	if o.requireHost && u.Hostname() == "" {
+		return nil, fmt.Errorf("URL %q has no host", s)
+	}
+
+	if port := u.Port(); port != "" {
+		if _, err := parsePort(port); err != nil {
+			return nil, fmt.Errorf("URL %q: %v", s, err)
+		}
+	} else if o.defaultPort != 0 && u.Host != "" {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(o.defaultPort))
+	} else if o.requirePort {
+		return nil, fmt.Errorf("URL %q has no port", s)
+	}
+	return u, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Endpoint is a network endpoint such as example.com:443 or [::1]:8080,
+// the value of HostPort flags.
+type Endpoint struct {
+	Host string
+	Port int // 0 when there is no port
+}
+
+// String returns the endpoint in host:port form, IPv6 hosts in brackets.
+func (hp Endpoint) String() string {
+	if hp.Port == 0 {
+		if strings.Contains(hp.Host, ":") {
+			return "[" + hp.Host + "]"
+		}
+		return hp.Host
+	}
+	return net.JoinHostPort(hp.Host, strconv.Itoa(hp.Port))
+}
+
+// parseHostPort parses s as host:port, host or :port and validates it
+// against the options.
+func parseHostPort(s string, o endpointOptions) (Endpoint, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Endpoint{}, nil
+	}
+
+	var hp Endpoint
+	host, port, err := net.SplitHostPort(s)
+	switch {
+	case err == nil:
+		hp.Host = host
+		if hp.Port, err = parsePort(port); err != nil {
+			return Endpoint{}, fmt.Errorf("endpoint %q: %v", s, err)
+		}
+	case strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]"):
+		hp.Host = s[1 : len(s)-1]
+	case strings.Count(s, ":") > 1 && net.ParseIP(s) != nil:
+		// an IPv6 address without brackets and port
+		hp.Host = s
+	case !strings.Contains(s, ":"):
+		hp.Host = s
+	default:
+		return Endpoint{}, fmt.Errorf("invalid endpoint %q, must be host:port", s)
+	}
+	if strings.ContainsAny(hp.Host, "/[] ") {
+		return Endpoint{}, fmt.Errorf("invalid host %q in endpoint %q", hp.Host, s)
+	}
+
+	if o.requireHost && hp.Host == "" {
+		return Endpoint{}, fmt.Errorf("endpoint %q has no host", s)
+	}
+	if hp.Port == 0 && o.defaultPort != 0 {
+		hp.Port = o.defaultPort
+	}
+	if hp.Port == 0 && o.requirePort {
+		return Endpoint{}, fmt.Errorf("endpoint %q has no port", s)
+	}
+	return hp, nil
+}
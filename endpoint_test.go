@@ -0,0 +1,165 @@
+package pflag_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURL(t *testing.T) {
+	base, err := url.Parse("https://api.example.com/v1/")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     []pflag.EndpointOption
+		expected string
+		err      string
+	}{
+		{"plain", "https://example.com/path?q=1", nil, "https://example.com/path?q=1", ""},
+		{"empty", "", nil, "", ""},
+		{"allowed scheme", "HTTPS://example.com", []pflag.EndpointOption{pflag.EndpointAllowSchemes("http", "https")}, "https://example.com", ""},
+		{"scheme not allowed", "ftp://example.com", []pflag.EndpointOption{pflag.EndpointAllowSchemes("http", "https")}, "", `has scheme "ftp", must be one of http, https`},
+		{"no scheme", "example.com", []pflag.EndpointOption{pflag.EndpointAllowSchemes("https")}, "", "has no scheme"},
+		{"no host", "file:///etc/hosts", []pflag.EndpointOption{pflag.EndpointRequireHost()}, "", "has no host"},
+		{"no port", "http://example.com", []pflag.EndpointOption{pflag.EndpointRequirePort()}, "", "has no port"},
+		{"default port", "http://example.com/x", []pflag.EndpointOption{pflag.EndpointRequirePort(), pflag.EndpointDefaultPort(8080)}, "http://example.com:8080/x", ""},
+		{"default port ipv6", "http://[::1]/x", []pflag.EndpointOption{pflag.EndpointDefaultPort(8080)}, "http://[::1]:8080/x", ""},
+		{"explicit port kept", "http://example.com:81", []pflag.EndpointOption{pflag.EndpointDefaultPort(8080)}, "http://example.com:81", ""},
+		{"invalid port", "http://example.com:99999", nil, "", `invalid port "99999"`},
+		{"parse error", "http://exa mple.com", nil, "", "invalid URL"},
+		{"relative", "users?page=2", []pflag.EndpointOption{pflag.EndpointResolveAgainst(base)}, "https://api.example.com/v1/users?page=2", ""},
+		{"absolute path", "/v2/", []pflag.EndpointOption{pflag.EndpointResolveAgainst(base)}, "https://api.example.com/v2/", ""},
+		{"absolute", "http://other.example.com", []pflag.EndpointOption{pflag.EndpointResolveAgainst(base)}, "http://other.example.com", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			endpoint := f.URL("endpoint", nil, "endpoint", tc.opts...)
+
+			err := f.Parse([]string{"--endpoint", tc.input})
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, endpoint.String())
+
+			got, err := f.GetURL("endpoint")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got.String())
+		})
+	}
+}
+
+func TestURLDefault(t *testing.T) {
+	def, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+
+	var proxy url.URL
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.URLVarP(&proxy, "proxy", "x", def, "proxy URL")
+	f.URL("endpoint", nil, "endpoint")
+
+	usages := f.FlagUsages()
+	require.Contains(t, usages, "proxy URL (default http://localhost:8080)\n")
+	require.Contains(t, usages, "endpoint\n")
+
+	require.NoError(t, f.Parse([]string{"-x", "http://proxy:3128"}))
+	require.Equal(t, "proxy:3128", proxy.Host)
+	require.Equal(t, "localhost:8080", def.Host)
+}
+
+func TestHostPort(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []pflag.EndpointOption
+		expected pflag.Endpoint
+		err      string
+	}{
+		{"example.com:443", nil, pflag.Endpoint{Host: "example.com", Port: 443}, ""},
+		{"[::1]:8080", nil, pflag.Endpoint{Host: "::1", Port: 8080}, ""},
+		{"::1", nil, pflag.Endpoint{Host: "::1"}, ""},
+		{"[::1]", []pflag.EndpointOption{pflag.EndpointDefaultPort(80)}, pflag.Endpoint{Host: "::1", Port: 80}, ""},
+		{":8080", nil, pflag.Endpoint{Port: 8080}, ""},
+		{"example.com", nil, pflag.Endpoint{Host: "example.com"}, ""},
+		{"example.com", []pflag.EndpointOption{pflag.EndpointDefaultPort(443)}, pflag.Endpoint{Host: "example.com", Port: 443}, ""},
+		{"example.com", []pflag.EndpointOption{pflag.EndpointRequirePort()}, pflag.Endpoint{}, "has no port"},
+		{":8080", []pflag.EndpointOption{pflag.EndpointRequireHost()}, pflag.Endpoint{}, "has no host"},
+		{"example.com:http", nil, pflag.Endpoint{}, `invalid port "http"`},
+		{"example.com:0", nil, pflag.Endpoint{}, `invalid port "0"`},
+		{"a:b:c", nil, pflag.Endpoint{}, "must be host:port"},
+		{"http://example.com", nil, pflag.Endpoint{}, "invalid"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			hp := f.HostPort("addr", pflag.Endpoint{}, "address", tc.opts...)
+
+			err := f.Parse([]string{"--addr=" + tc.input})
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, *hp)
+
+			got, err := f.GetHostPort("addr")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestEndpointString(t *testing.T) {
+	require.Equal(t, "example.com:443", pflag.Endpoint{Host: "example.com", Port: 443}.String())
+	require.Equal(t, "[::1]:80", pflag.Endpoint{Host: "::1", Port: 80}.String())
+	require.Equal(t, "[::1]", pflag.Endpoint{Host: "::1"}.String())
+	require.Equal(t, ":8080", pflag.Endpoint{Port: 8080}.String())
+	require.Equal(t, "", pflag.Endpoint{}.String())
+}
+
+func TestEndpointSlices(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	peers := f.HostPortSlice("peers", nil, "peers", pflag.EndpointDefaultPort(7946))
+	hooks := f.URLSlice("hooks", nil, "web hooks", pflag.EndpointAllowSchemes("https"))
+
+	err := f.Parse([]string{
+		"--peers=10.0.0.1,[::1]:7000", "--peers", "node-3",
+		`--hooks="https://example.com/a?x=1,2",https://example.com/b`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []pflag.Endpoint{
+		{Host: "10.0.0.1", Port: 7946},
+		{Host: "::1", Port: 7000},
+		{Host: "node-3", Port: 7946},
+	}, *peers)
+	require.Len(t, *hooks, 2)
+	require.Equal(t, "x=1,2", (*hooks)[0].RawQuery)
+
+	gotPeers, err := f.GetHostPortSlice("peers")
+	require.NoError(t, err)
+	require.Equal(t, *peers, gotPeers)
+
+	gotHooks, err := f.GetURLSlice("hooks")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/a?x=1,2", "https://example.com/b"}, []string{gotHooks[0].String(), gotHooks[1].String()})
+
+	require.Error(t, f.Parse([]string{"--hooks=http://example.com"}))
+	require.Error(t, f.Parse([]string{"--peers=a,,b"}))
+
+	sv, ok := f.Lookup("peers").Value.(pflag.SliceValue)
+	require.True(t, ok)
+	require.NoError(t, sv.Replace([]string{"node-1:1"}))
+	require.Equal(t, []string{"node-1:1"}, sv.GetSlice())
+	require.Error(t, sv.Append("node-2:x"))
+
+	require.Contains(t, f.FlagUsages(), "web hooks\n")
+}
@@ -0,0 +1,50 @@
+package pflag
+
+import (
+	"os"
+
+	"github.com/rsb/failure"
+)
+
+// BindEnv binds the named flag to the environment variable key, which is
+// read by ParseEnv.
+func (f *FlagSet) BindEnv(name, key string) error {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return failure.NotFound("flag (%s), does not exist", name)
+	}
+
+	if key == "" {
+		return failure.InvalidParam("environment variable for (%s) must be set", name)
+	}
+
+	flag.EnvVar = key
+	return nil
+}
+
+// ParseEnv applies the values of the environment variables bound with
+// BindEnv. Like ParseConfig it leaves flags set on the command line alone,
+// does not mark flags as Changed, and applies either every value or none.
+// Secret flags whose variable is not set are read from the file named by
+// the variable with a _FILE suffix, such as PASSWORD_FILE for PASSWORD.
+func (f *FlagSet) ParseEnv() error {
+	doc := map[string]interface{}{}
+	f.VisitAll(func(flag *Flag) {
+		if flag.EnvVar == "" {
+			return
+		}
+		if value, ok := os.LookupEnv(flag.EnvVar); ok {
+			doc[flag.Name] = value
+			return
+		}
+		if _, ok := flag.Value.(*secretValue); !ok {
+			return
+		}
+		if path, ok := os.LookupEnv(flag.EnvVar + "_FILE"); ok {
+			doc[flag.Name] = "@" + path
+		}
+	})
+
+	_, err := f.applyConfig(doc, func(flag *Flag) bool { return !flag.Changed })
+	return err
+}
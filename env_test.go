@@ -0,0 +1,50 @@
+package pflag_test
+
+import (
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnv(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	name := f.String("name", "", "name")
+	port := f.Int("port", 80, "port")
+	tags := f.StringSlice("tags", []string{}, "tags")
+	require.NoError(t, f.BindEnv("name", "APP_NAME"))
+	require.NoError(t, f.BindEnv("port", "APP_PORT"))
+	require.NoError(t, f.BindEnv("tags", "APP_TAGS"))
+
+	t.Setenv("APP_NAME", "env")
+	t.Setenv("APP_PORT", "8080")
+	t.Setenv("APP_TAGS", "a,b")
+
+	require.NoError(t, f.Parse([]string{"--name=cli"}))
+	require.NoError(t, f.ParseEnv())
+	require.Equal(t, "cli", *name)
+	require.Equal(t, 8080, *port)
+	require.Equal(t, []string{"a", "b"}, *tags)
+	require.False(t, f.Changed("port"))
+}
+
+func TestParseEnvInvalid(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	name := f.String("name", "", "name")
+	f.Int("port", 80, "port")
+	require.NoError(t, f.BindEnv("name", "APP_NAME"))
+	require.NoError(t, f.BindEnv("port", "APP_PORT"))
+
+	t.Setenv("APP_NAME", "env")
+	t.Setenv("APP_PORT", "eighty")
+
+	require.Error(t, f.ParseEnv())
+	require.Equal(t, "", *name)
+}
+
+func TestBindEnvErrors(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.String("name", "", "name")
+	require.Error(t, f.BindEnv("missing", "APP_MISSING"))
+	require.Error(t, f.BindEnv("name", ""))
+}
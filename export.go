@@ -0,0 +1,327 @@
+package pflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rsb/failure"
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted is written in place of the value of a sensitive flag. Config
+// sources that contain it for a sensitive flag leave that flag untouched, so
+// exported output can be loaded again.
+const Redacted = "<redacted>"
+
+// ExportFilter selects which flags are written by ExportFiltered.
+type ExportFilter int
+
+const (
+	// ExportAll writes every flag
+	ExportAll ExportFilter = iota
+	// ExportChanged writes only flags set on the command line
+	ExportChanged
+	// ExportNonDefault writes only flags whose value differs from the default
+	ExportNonDefault
+)
+
+// exportedFlag is a flag and its value converted into native strings,
+// numbers, bools, lists and maps.
+type exportedFlag struct {
+	flag  *Flag
+	value interface{}
+}
+
+// Export writes the current value of every flag to w in the given format.
+func (f *FlagSet) Export(w io.Writer, format ConfigFormat) error {
+	return f.ExportFiltered(w, format, ExportAll)
+}
+
+// ExportFiltered is like Export, but only writes the flags selected by filter.
+// Deprecated flags are never written, and the values of sensitive flags are
+// replaced with Redacted.
+func (f *FlagSet) ExportFiltered(w io.Writer, format ConfigFormat, filter ExportFilter) error {
+	var flags []exportedFlag
+	f.VisitAll(func(flag *Flag) {
+		if flag.Deprecated != "" {
+			return
+		}
+		switch filter {
+		case ExportChanged:
+			if !flag.Changed {
+				return
+			}
+		case ExportNonDefault:
+			if flag.Value.String() == flag.Default {
+				return
+			}
+		}
+		flags = append(flags, exportedFlag{flag: flag, value: exportValue(flag)})
+	})
+
+	if format == ConfigDotenv {
+		for _, ef := range flags {
+			if _, ok := flagNameToEnvKey(ef.flag.Name); !ok {
+				return failure.InvalidParam("flag (%s) has no dotenv key, its name must only contain lower case letters, digits and dashes", ef.flag.Name)
+			}
+		}
+	}
+
+	var err error
+	switch format {
+	case ConfigJSON:
+		err = exportJSON(w, flags)
+	case ConfigYAML:
+		err = exportYAML(w, flags, false)
+	case ConfigTemplate:
+		err = exportYAML(w, flags, true)
+	case ConfigDotenv:
+		err = exportDotenv(w, flags)
+	default:
+		return failure.InvalidParam("unsupported export format: %s", format)
+	}
+	if err != nil {
+		return failure.ToSystem(err, "unable to export flags as %s", format)
+	}
+	return nil
+}
+
+// exportValue converts the value of a flag into a string, json.Number or
+// bool, or a list or map of those.
+func exportValue(flag *Flag) interface{} {
+	if flag.Sensitive {
+		return Redacted
+	}
+
+	typ := flag.Value.Type()
+	switch v := flag.Value.(type) {
+	case SliceValue:
+		elem := strings.TrimSuffix(typ, "Slice")
+		items := v.GetSlice()
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			out[i] = exportScalar(elem, item)
+		}
+		return out
+	case MapValue:
+		elem := strings.ToLower(strings.TrimPrefix(typ, "stringTo"))
+		m := v.GetMap()
+		out := make(map[string]interface{}, len(m))
+		for k, item := range m {
+			out[k] = exportScalar(elem, item)
+		}
+		return out
+	}
+	return exportScalar(typ, flag.Value.String())
+}
+
+// exportScalar returns s as a native value of typ. Unset optional values are
+// returned as nil.
+func exportScalar(typ string, s string) interface{} {
+	switch typ {
+	case "optionalBool", "optionalInt", "optionalDuration":
+		if s == "" || (typ == "optionalBool" && s == "auto") {
+			return nil
+		}
+	}
+	switch typ {
+	case "bool", "optionalBool":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "count",
+		"float32", "float64", "optionalInt":
+		if n, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(n, 0) && !math.IsNaN(n) {
+			return json.Number(s)
+		}
+	}
+	return s
+}
+
+func exportJSON(w io.Writer, flags []exportedFlag) error {
+	buf := new(bytes.Buffer)
+	buf.WriteString("{")
+	for i, ef := range flags {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := marshalJSON(ef.flag.Name)
+		if err != nil {
+			return err
+		}
+		value, err := marshalJSON(ef.value)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(buf, "\n  %s: %s", key, value)
+	}
+	if len(flags) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// marshalJSON is like json.Marshal, but does not escape HTML characters
+// such as the brackets in Redacted.
+func marshalJSON(v interface{}) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func exportYAML(w io.Writer, flags []exportedFlag, comments bool) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, ef := range flags {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ef.flag.Name}
+		if comments {
+			key.HeadComment = templateComment(ef.flag)
+		}
+		doc.Content = append(doc.Content, key, yamlNode(ef.value))
+	}
+
+	if len(flags) == 0 {
+		_, err := io.WriteString(w, "{}\n")
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func yamlNode(v interface{}) *yaml.Node {
+	switch v := v.(type) {
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			node.Content = append(node.Content, yamlNode(item))
+		}
+		return node
+	case map[string]interface{}:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			node.Content = append(node.Content, yamlNode(k), yamlNode(v[k]))
+		}
+		return node
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	case json.Number:
+		tag := "!!int"
+		if _, err := v.Int64(); err != nil {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(v)}
+}
+
+// templateComment returns the usage text of a flag, its type and its
+// default, as used above every entry of a ConfigTemplate.
+func templateComment(flag *Flag) string {
+	varname, usage := UnquoteUsage(flag)
+	if varname == "" {
+		varname = flag.Value.Type()
+	}
+
+	comment := usage
+	if comment != "" {
+		comment += "\n"
+	}
+	comment += fmt.Sprintf("type: %s", varname)
+	if !flag.Sensitive {
+		comment += fmt.Sprintf(", default: %s", flag.Default)
+	}
+	return comment
+}
+
+func exportDotenv(w io.Writer, flags []exportedFlag) error {
+	buf := new(bytes.Buffer)
+	for _, ef := range flags {
+		var value string
+		switch v := ef.value.(type) {
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			s, err := writeAsCSV(items)
+			if err != nil {
+				return err
+			}
+			value = s
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			pairs := make([]string, len(keys))
+			for i, k := range keys {
+				pairs[i] = k + "=" + fmt.Sprint(v[k])
+			}
+			s, err := writeAsCSV(pairs)
+			if err != nil {
+				return err
+			}
+			value = s
+		case nil:
+			value = ""
+		default:
+			value = fmt.Sprint(v)
+		}
+		key, _ := flagNameToEnvKey(ef.flag.Name)
+		_, _ = fmt.Fprintf(buf, "%s=%s\n", key, dotenvQuote(value))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// dotenvQuote double quotes a value unless it only contains characters that
+// need no escaping.
+func dotenvQuote(s string) string {
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("-_.,:/=+@", c):
+		default:
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// flagNameToEnvKey returns the dotenv key of a flag, which envKeyToFlagName
+// turns back into its name. Names with other characters than lower case
+// letters, digits and dashes, such as "." or "_", have no such key.
+func flagNameToEnvKey(name string) (string, bool) {
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return "", false
+		}
+	}
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_")), true
+}
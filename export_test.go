@@ -0,0 +1,196 @@
+package pflag_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpExportFlagSet() *pflag.FlagSet {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.SortFlags = false
+	f.String("name", "app", "the `name` of the app")
+	f.Bool("verbose", false, "verbose output")
+	f.Int("workers", 4, "number of workers")
+	f.Float64("ratio", 0.5, "sample ratio")
+	f.StringSlice("tags", []string{}, "tags to apply")
+	f.IntSlice("ports", []int{80}, "ports to open")
+	f.StringToInt("limits", map[string]int{}, "limits per tenant")
+	f.String("password", "", "database password")
+	_ = f.MarkSensitive("password")
+	return f
+}
+
+func TestExport_JSON(t *testing.T) {
+	f := setUpExportFlagSet()
+	require.NoError(t, f.Parse([]string{
+		"--tags=a,b", "--limits=x=1", "--verbose", "--password=hunter2",
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Export(&buf, pflag.ConfigJSON))
+	require.Equal(t, `{
+  "name": "app",
+  "verbose": true,
+  "workers": 4,
+  "ratio": 0.5,
+  "tags": ["a","b"],
+  "ports": [80],
+  "limits": {"x":1},
+  "password": "<redacted>"
+}
+`, buf.String())
+}
+
+func TestExport_Filtered(t *testing.T) {
+	f := setUpExportFlagSet()
+	require.NoError(t, f.Parse([]string{"--workers=8", "--verbose=false"}))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.ExportFiltered(&buf, pflag.ConfigYAML, pflag.ExportChanged))
+	require.Equal(t, "verbose: false\nworkers: 8\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, f.ExportFiltered(&buf, pflag.ConfigDotenv, pflag.ExportNonDefault))
+	require.Equal(t, "WORKERS=8\n", buf.String())
+}
+
+func TestExport_DotenvNames(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Int("rate-limit", 10, "rate limit")
+	f.String("log2-level", "info", "log level")
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Export(&buf, pflag.ConfigDotenv))
+	require.Equal(t, "LOG2_LEVEL=info\nRATE_LIMIT=10\n", buf.String())
+
+	g := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	rate := g.Int("rate-limit", 1, "rate limit")
+	level := g.String("log2-level", "", "log level")
+	require.NoError(t, g.ParseConfig(&buf, pflag.ConfigDotenv))
+	require.Equal(t, 10, *rate)
+	require.Equal(t, "info", *level)
+
+	for _, name := range []string{"db.host", "db_host", "dbHost"} {
+		f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		f.String(name, "localhost", "database host")
+
+		buf.Reset()
+		err := f.Export(&buf, pflag.ConfigDotenv)
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), "flag ("+name+") has no dotenv key")
+		require.Empty(t, buf.String())
+		require.NoError(t, f.Export(&buf, pflag.ConfigYAML))
+	}
+}
+
+func TestExport_Template(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.String("name", "app", "the `name` of the app")
+	f.StringSlice("tags", []string{"a"}, "tags to apply")
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Export(&buf, pflag.ConfigTemplate))
+	require.Equal(t, `# the name of the app
+# type: name, default: app
+name: app
+# tags to apply
+# type: strings, default: [a]
+tags:
+- a
+`, buf.String())
+}
+
+func TestExport_RoundTrip(t *testing.T) {
+	formats := []pflag.ConfigFormat{
+		pflag.ConfigJSON, pflag.ConfigYAML, pflag.ConfigDotenv, pflag.ConfigTemplate,
+	}
+	args := []string{
+		"--name=my app", "--verbose", "--workers=16", "--ratio=0.25",
+		"--tags=x,y z", "--ports=1,2", "--limits=a=1,b=2", "--password=hunter2",
+	}
+
+	for _, format := range formats {
+		t.Run(format.String(), func(t *testing.T) {
+			src := setUpExportFlagSet()
+			require.NoError(t, src.Parse(args))
+
+			var buf bytes.Buffer
+			require.NoError(t, src.Export(&buf, format))
+
+			dst := setUpExportFlagSet()
+			require.NoError(t, dst.ParseConfig(&buf, format))
+
+			src.VisitAll(func(flag *pflag.Flag) {
+				if flag.Sensitive {
+					require.Equal(t, "", dst.Lookup(flag.Name).Value.String())
+					return
+				}
+				if mv, ok := flag.Value.(pflag.MapValue); ok {
+					require.Equal(t, mv.GetMap(), dst.Lookup(flag.Name).Value.(pflag.MapValue).GetMap())
+					return
+				}
+				require.Equal(t, flag.Value.String(), dst.Lookup(flag.Name).Value.String(), flag.Name)
+			})
+		})
+	}
+}
+
+func TestExport_Optionals(t *testing.T) {
+	setUp := func() *pflag.FlagSet {
+		f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		f.OptionalBool("color", "colored output")
+		f.OptionalInt("retries", "number of retries")
+		f.OptionalDuration("timeout", "request timeout")
+		return f
+	}
+
+	var schemaBuf bytes.Buffer
+	require.NoError(t, setUp().GenJSONSchema(&schemaBuf))
+	var schema struct {
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(schemaBuf.Bytes(), &schema))
+	jsonType := func(v interface{}) string {
+		switch v.(type) {
+		case nil:
+			return "null"
+		case bool:
+			return "boolean"
+		case float64:
+			return "integer"
+		}
+		return "string"
+	}
+
+	for _, args := range [][]string{nil, {"--color=false", "--retries=3", "--timeout=1m30s"}} {
+		src := setUp()
+		require.NoError(t, src.Parse(args))
+
+		var buf bytes.Buffer
+		require.NoError(t, src.Export(&buf, pflag.ConfigJSON))
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		for name, value := range doc {
+			property := schema.Properties[name]
+			require.NotContains(t, property, "default", name)
+			require.Contains(t, property["type"], jsonType(value), name)
+		}
+		if args == nil {
+			require.Equal(t, map[string]interface{}{"color": nil, "retries": nil, "timeout": nil}, doc)
+		}
+
+		for _, format := range []pflag.ConfigFormat{pflag.ConfigJSON, pflag.ConfigYAML, pflag.ConfigDotenv} {
+			buf.Reset()
+			require.NoError(t, src.Export(&buf, format))
+			dst := setUp()
+			require.NoError(t, dst.ParseConfig(&buf, format), format.String())
+			src.VisitAll(func(flag *pflag.Flag) {
+				require.Equal(t, flag.Value.String(), dst.Lookup(flag.Name).Value.String(), format.String()+" "+flag.Name)
+			})
+		}
+	}
+}
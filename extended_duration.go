@@ -0,0 +1,256 @@
+package pflag
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	durationDay  = 24 * time.Hour
+	durationWeek = 7 * durationDay
+)
+
+var isoDurationRE = regexp.MustCompile(`^P(?:([0-9.,]+)W)?(?:([0-9.,]+)D)?(?:T(?:([0-9.,]+)H)?(?:([0-9.,]+)M)?(?:([0-9.,]+)S)?)?$`)
+
+// ParseExtendedDuration parses a duration like time.ParseDuration, but also
+// accepts the units d (24h) and w (7d), as in "30d" or "1w2d12h", and ISO
+// 8601 durations such as "P1W" or "P1DT2H30M". Years and months are
+// rejected, since their length varies.
+func ParseExtendedDuration(s string) (time.Duration, error) {
+	// anything time.ParseDuration accepts is read by it, which also covers
+	// the most negative duration
+	if d, err := time.ParseDuration(strings.TrimSpace(s)); err == nil {
+		return d, nil
+	}
+
+	str := strings.TrimSpace(s)
+	neg := false
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		neg, str = str[0] == '-', str[1:]
+	}
+
+	if strings.HasPrefix(str, "P") || strings.HasPrefix(str, "p") {
+		units, err := isoDurationUnits(strings.ToUpper(str))
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: %v", s, err)
+		}
+		str = units
+	}
+
+	d, err := parseDurationUnits(str, neg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// isoDurationUnits rewrites an ISO 8601 duration, without sign, as a
+// duration with units, such as "1w2d3h".
+func isoDurationUnits(s string) (string, error) {
+	m := isoDurationRE.FindStringSubmatch(s)
+	if m == nil {
+		if strings.ContainsAny(strings.SplitN(s, "T", 2)[0], "YM") {
+			return "", fmt.Errorf("years and months have no fixed length")
+		}
+		return "", fmt.Errorf("expected PnWnDTnHnMnS")
+	}
+	if s == "P" || strings.HasSuffix(s, "T") {
+		return "", fmt.Errorf("no components")
+	}
+
+	var b strings.Builder
+	for i, unit := range []string{"w", "d", "h", "m", "s"} {
+		if m[i+1] != "" {
+			b.WriteString(strings.Replace(m[i+1], ",", ".", 1) + unit)
+		}
+	}
+	return b.String(), nil
+}
+
+// parseDurationUnits parses a duration without sign, such as "1w2d3h4m",
+// and negates it when neg is set. The magnitude of a negative duration can
+// be one more than that of a positive one.
+func parseDurationUnits(s string, neg bool) (time.Duration, error) {
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
+	var total uint64
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return r != '.' && (r < '0' || r > '9') })
+		if i <= 0 {
+			return 0, fmt.Errorf("missing unit")
+		}
+		j := strings.IndexFunc(s[i:], func(r rune) bool { return r == '.' || (r >= '0' && r <= '9') })
+		if j < 0 {
+			j = len(s) - i
+		}
+		number, unit := s[:i], s[i:i+j]
+		s = s[i+j:]
+
+		var d time.Duration
+		var err error
+		switch unit {
+		case "d":
+			d, err = scaleDuration(number, durationDay)
+		case "w":
+			d, err = scaleDuration(number, durationWeek)
+		default:
+			d, err = time.ParseDuration(number + unit)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if uint64(d) > limit-total {
+			return 0, fmt.Errorf("duration overflows")
+		}
+		total += uint64(d)
+	}
+
+	d := time.Duration(total)
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// scaleDuration returns number times unit, which is a multiple of an hour.
+func scaleDuration(number string, unit time.Duration) (time.Duration, error) {
+	hours, err := time.ParseDuration(number + "h")
+	if err != nil {
+		return 0, err
+	}
+	n := int64(unit / time.Hour)
+	if int64(hours) > math.MaxInt64/n {
+		return 0, fmt.Errorf("duration overflows")
+	}
+	return hours * time.Duration(n), nil
+}
+
+// FormatExtendedDuration formats a duration with the units of
+// ParseExtendedDuration, such as "30d", "2w" or "1d12h".
+func FormatExtendedDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	sign := ""
+	u := uint64(d)
+	if d < 0 {
+		sign = "-"
+		u = -u
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	// whole weeks are written as weeks, 30d stays 30d
+	if n := u / uint64(durationDay); n > 0 && n%7 == 0 {
+		fmt.Fprintf(&b, "%dw", n/7)
+		u %= uint64(durationDay)
+	} else if n > 0 {
+		fmt.Fprintf(&b, "%dd", n)
+		u %= uint64(durationDay)
+	}
+	if u > 0 {
+		rest := time.Duration(u).String()
+		if strings.HasSuffix(rest, "m0s") {
+			rest = strings.TrimSuffix(rest, "0s")
+		}
+		if strings.HasSuffix(rest, "h0m") {
+			rest = strings.TrimSuffix(rest, "0m")
+		}
+		b.WriteString(rest)
+	}
+	return b.String()
+}
+
+// -- extended time.Duration Value
+type extendedDurationValue time.Duration
+
+func newExtendedDurationValue(val time.Duration, p *time.Duration) *extendedDurationValue {
+	*p = val
+	return (*extendedDurationValue)(p)
+}
+
+func (d *extendedDurationValue) Set(s string) error {
+	v, err := ParseExtendedDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = extendedDurationValue(v)
+	return nil
+}
+
+// Type is the type of Duration flags, so GetDuration reads both.
+func (d *extendedDurationValue) Type() string {
+	return "duration"
+}
+
+func (d *extendedDurationValue) String() string {
+	return FormatExtendedDuration(time.Duration(*d))
+}
+
+func extendedDurationConv(sval string) (interface{}, error) {
+	return ParseExtendedDuration(sval)
+}
+
+// ExtendedDurationVar defines a time.Duration flag with specified name, default value, and usage string.
+// The argument p points to a time.Duration variable in which to store the value of the flag.
+// Values are parsed with ParseExtendedDuration, so they can be given in days, weeks or as
+// ISO 8601 durations. GetDuration returns the value of the flag.
+func (f *FlagSet) ExtendedDurationVar(p *time.Duration, name string, value time.Duration, usage string) {
+	f.VarP(newExtendedDurationValue(value, p), name, "", usage)
+}
+
+// ExtendedDurationVarP is like ExtendedDurationVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) ExtendedDurationVarP(p *time.Duration, name, shorthand string, value time.Duration, usage string) {
+	f.VarP(newExtendedDurationValue(value, p), name, shorthand, usage)
+}
+
+// ExtendedDurationVar defines a time.Duration flag with specified name, default value, and usage string.
+// The argument p points to a time.Duration variable in which to store the value of the flag.
+// Values are parsed with ParseExtendedDuration, so they can be given in days, weeks or as
+// ISO 8601 durations. GetDuration returns the value of the flag.
+func ExtendedDurationVar(p *time.Duration, name string, value time.Duration, usage string) {
+	CommandLine.VarP(newExtendedDurationValue(value, p), name, "", usage)
+}
+
+// ExtendedDurationVarP is like ExtendedDurationVar, but accepts a shorthand letter that can be used after a single dash.
+func ExtendedDurationVarP(p *time.Duration, name, shorthand string, value time.Duration, usage string) {
+	CommandLine.VarP(newExtendedDurationValue(value, p), name, shorthand, usage)
+}
+
+// ExtendedDuration defines a time.Duration flag with specified name, default value, and usage string.
+// The return value is the address of a time.Duration variable that stores the value of the flag.
+func (f *FlagSet) ExtendedDuration(name string, value time.Duration, usage string) *time.Duration {
+	p := new(time.Duration)
+	f.ExtendedDurationVarP(p, name, "", value, usage)
+	return p
+}
+
+// ExtendedDurationP is like ExtendedDuration, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) ExtendedDurationP(name, shorthand string, value time.Duration, usage string) *time.Duration {
+	p := new(time.Duration)
+	f.ExtendedDurationVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// ExtendedDuration defines a time.Duration flag with specified name, default value, and usage string.
+// The return value is the address of a time.Duration variable that stores the value of the flag.
+func ExtendedDuration(name string, value time.Duration, usage string) *time.Duration {
+	return CommandLine.ExtendedDurationP(name, "", value, usage)
+}
+
+// ExtendedDurationP is like ExtendedDuration, but accepts a shorthand letter that can be used after a single dash.
+func ExtendedDurationP(name, shorthand string, value time.Duration, usage string) *time.Duration {
+	return CommandLine.ExtendedDurationP(name, shorthand, value, usage)
+}
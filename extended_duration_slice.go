@@ -0,0 +1,155 @@
+package pflag
+
+import (
+	"strings"
+	"time"
+)
+
+// -- extended durationSlice Value
+type extendedDurationSliceValue struct {
+	value   *[]time.Duration
+	changed bool
+}
+
+func newExtendedDurationSliceValue(val []time.Duration, p *[]time.Duration) *extendedDurationSliceValue {
+	edsv := new(extendedDurationSliceValue)
+	edsv.value = p
+	*edsv.value = val
+	return edsv
+}
+
+// Set parses the comma-separated durations in val. ISO 8601 durations with a
+// decimal comma, such as P1,5D, have to be quoted as in "P1,5D",2d.
+func (s *extendedDurationSliceValue) Set(val string) error {
+	ss, err := readAsCSV(val)
+	if err != nil {
+		return err
+	}
+	out := make([]time.Duration, len(ss))
+	for i, d := range ss {
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+	s.changed = true
+	return nil
+}
+
+// Type is the type of DurationSlice flags, so GetDurationSlice reads both.
+func (s *extendedDurationSliceValue) Type() string {
+	return "durationSlice"
+}
+
+func (s *extendedDurationSliceValue) String() string {
+	return "[" + strings.Join(s.GetSlice(), ",") + "]"
+}
+
+func extendedDurationSliceConv(val string) (interface{}, error) {
+	val = strings.Trim(val, "[]")
+	ss, err := readAsCSV(val)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]time.Duration, len(ss))
+	for i, d := range ss {
+		out[i], err = ParseExtendedDuration(d)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (s *extendedDurationSliceValue) fromString(val string) (time.Duration, error) {
+	return ParseExtendedDuration(val)
+}
+
+func (s *extendedDurationSliceValue) toString(val time.Duration) string {
+	return FormatExtendedDuration(val)
+}
+
+func (s *extendedDurationSliceValue) Append(val string) error {
+	i, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, i)
+	return nil
+}
+
+func (s *extendedDurationSliceValue) Replace(val []string) error {
+	out := make([]time.Duration, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *extendedDurationSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+// ExtendedDurationSliceVar defines a time.Duration slice flag with specified name, default value, and usage string.
+// The argument p points to a []time.Duration variable in which to store the value of the flag.
+// Values are parsed with ParseExtendedDuration. GetDurationSlice returns the value of the flag.
+func (f *FlagSet) ExtendedDurationSliceVar(p *[]time.Duration, name string, value []time.Duration, usage string) {
+	f.VarP(newExtendedDurationSliceValue(value, p), name, "", usage)
+}
+
+// ExtendedDurationSliceVarP is like ExtendedDurationSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) ExtendedDurationSliceVarP(p *[]time.Duration, name, shorthand string, value []time.Duration, usage string) {
+	f.VarP(newExtendedDurationSliceValue(value, p), name, shorthand, usage)
+}
+
+// ExtendedDurationSliceVar defines a time.Duration slice flag with specified name, default value, and usage string.
+// The argument p points to a []time.Duration variable in which to store the value of the flag.
+// Values are parsed with ParseExtendedDuration. GetDurationSlice returns the value of the flag.
+func ExtendedDurationSliceVar(p *[]time.Duration, name string, value []time.Duration, usage string) {
+	CommandLine.VarP(newExtendedDurationSliceValue(value, p), name, "", usage)
+}
+
+// ExtendedDurationSliceVarP is like ExtendedDurationSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func ExtendedDurationSliceVarP(p *[]time.Duration, name, shorthand string, value []time.Duration, usage string) {
+	CommandLine.VarP(newExtendedDurationSliceValue(value, p), name, shorthand, usage)
+}
+
+// ExtendedDurationSlice defines a []time.Duration flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Duration variable that stores the value of the flag.
+func (f *FlagSet) ExtendedDurationSlice(name string, value []time.Duration, usage string) *[]time.Duration {
+	p := []time.Duration{}
+	f.ExtendedDurationSliceVarP(&p, name, "", value, usage)
+	return &p
+}
+
+// ExtendedDurationSliceP is like ExtendedDurationSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) ExtendedDurationSliceP(name, shorthand string, value []time.Duration, usage string) *[]time.Duration {
+	p := []time.Duration{}
+	f.ExtendedDurationSliceVarP(&p, name, shorthand, value, usage)
+	return &p
+}
+
+// ExtendedDurationSlice defines a []time.Duration flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Duration variable that stores the value of the flag.
+func ExtendedDurationSlice(name string, value []time.Duration, usage string) *[]time.Duration {
+	return CommandLine.ExtendedDurationSliceP(name, "", value, usage)
+}
+
+// ExtendedDurationSliceP is like ExtendedDurationSlice, but accepts a shorthand letter that can be used after a single dash.
+func ExtendedDurationSliceP(name, shorthand string, value []time.Duration, usage string) *[]time.Duration {
+	return CommandLine.ExtendedDurationSliceP(name, shorthand, value, usage)
+}
@@ -0,0 +1,156 @@
+package pflag_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+const day = 24 * time.Hour
+
+func TestParseExtendedDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"0", 0, true},
+		{"90s", 90 * time.Second, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"30d", 30 * day, true},
+		{"1.5d", 36 * time.Hour, true},
+		{"2w", 14 * day, true},
+		{"1w2d12h", 9*day + 12*time.Hour, true},
+		{"-1d", -day, true},
+		{" 7d ", 7 * day, true},
+		{"P1W", 7 * day, true},
+		{"P30D", 30 * day, true},
+		{"P1DT2H30M", day + 150*time.Minute, true},
+		{"PT0,5S", 500 * time.Millisecond, true},
+		{"-PT15M", -15 * time.Minute, true},
+		{"p1d", day, true},
+		{"", 0, false},
+		{"d", 0, false},
+		{"10", 0, false},
+		{"10x", 0, false},
+		{"P", 0, false},
+		{"P1DT", 0, false},
+		{"P1Y", 0, false},
+		{"P1M", 0, false},
+		{"PT1D", 0, false},
+		{"100000000w", 0, false},
+		{"-2562047h47m16.854775808s", math.MinInt64, true},
+		{"-106751d23h47m16.854775808s", math.MinInt64, true},
+		{"106751d23h47m16.854775807s", math.MaxInt64, true},
+		{"106751d23h47m16.854775808s", 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := pflag.ParseExtendedDuration(tc.input)
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestParseExtendedDurationYearsAndMonths(t *testing.T) {
+	_, err := pflag.ParseExtendedDuration("P1Y2M")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no fixed length")
+}
+
+func TestFormatExtendedDuration(t *testing.T) {
+	require.Equal(t, "0s", pflag.FormatExtendedDuration(0))
+	require.Equal(t, "30d", pflag.FormatExtendedDuration(30*day))
+	require.Equal(t, "2w", pflag.FormatExtendedDuration(14*day))
+	require.Equal(t, "9d12h", pflag.FormatExtendedDuration(9*day+12*time.Hour))
+	require.Equal(t, "2w3h", pflag.FormatExtendedDuration(14*day+3*time.Hour))
+	require.Equal(t, "1h30m", pflag.FormatExtendedDuration(90*time.Minute))
+	require.Equal(t, "1m", pflag.FormatExtendedDuration(time.Minute))
+	require.Equal(t, "10s", pflag.FormatExtendedDuration(10*time.Second))
+	require.Equal(t, "-1d1h", pflag.FormatExtendedDuration(-25*time.Hour))
+	require.Equal(t, "1.5s", pflag.FormatExtendedDuration(1500*time.Millisecond))
+
+	for _, d := range []time.Duration{30 * day, 9*day + 12*time.Hour + 5*time.Second, -25 * time.Hour, 1500 * time.Microsecond} {
+		got, err := pflag.ParseExtendedDuration(pflag.FormatExtendedDuration(d))
+		require.NoError(t, err)
+		require.Equal(t, d, got)
+	}
+}
+
+func TestExtendedDuration(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	retention := f.ExtendedDuration("retention", 30*day, "how long to keep backups")
+	var ttl time.Duration
+	f.ExtendedDurationVarP(&ttl, "ttl", "t", 0, "time to live")
+
+	require.Contains(t, f.FlagUsages(), "how long to keep backups (default 30d)")
+	require.Contains(t, f.FlagUsages(), "time to live\n")
+
+	require.NoError(t, f.Parse([]string{"--retention=P1W", "-t", "1d12h"}))
+	require.Equal(t, 7*day, *retention)
+	require.Equal(t, 36*time.Hour, ttl)
+
+	got, err := f.GetDuration("retention")
+	require.NoError(t, err)
+	require.Equal(t, 7*day, got)
+
+	require.Error(t, f.Parse([]string{"--ttl=P1M"}))
+}
+
+func TestDurationExtremes(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Duration("timeout", 0, "timeout")
+	f.DurationSlice("timeouts", nil, "timeouts")
+
+	min := time.Duration(math.MinInt64).String()
+	require.NoError(t, f.Parse([]string{"--timeout", min, "--timeouts", min}))
+
+	got, err := f.GetDuration("timeout")
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(math.MinInt64), got)
+
+	gotSlice, err := f.GetDurationSlice("timeouts")
+	require.NoError(t, err)
+	require.Equal(t, []time.Duration{math.MinInt64}, gotSlice)
+}
+
+func TestExtendedDurationSlice(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	windows := f.ExtendedDurationSlice("windows", []time.Duration{day}, "cron windows")
+
+	require.Contains(t, f.FlagUsages(), "cron windows (default [1d])")
+
+	require.NoError(t, f.Parse([]string{"--windows=1w,PT6H", "--windows", "90m"}))
+	require.Equal(t, []time.Duration{7 * day, 6 * time.Hour, 90 * time.Minute}, *windows)
+	require.Equal(t, "[1w,6h,1h30m]", f.Lookup("windows").Value.String())
+
+	got, err := f.GetDurationSlice("windows")
+	require.NoError(t, err)
+	require.Equal(t, *windows, got)
+
+	sv, ok := f.Lookup("windows").Value.(pflag.SliceValue)
+	require.True(t, ok)
+	require.NoError(t, sv.Replace([]string{"2d"}))
+	require.Equal(t, []string{"2d"}, sv.GetSlice())
+	require.Error(t, sv.Append("P1Y"))
+
+	// an ISO 8601 decimal comma has to be quoted
+	f = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	windows = f.ExtendedDurationSlice("windows", nil, "cron windows")
+	require.NoError(t, f.Parse([]string{`--windows="P1,5D",2d`}))
+	require.Equal(t, []time.Duration{36 * time.Hour, 2 * day}, *windows)
+	require.Error(t, f.Parse([]string{"--windows=P1,5D"}))
+
+	got, err = f.GetDurationSlice("windows")
+	require.NoError(t, err)
+	require.Equal(t, *windows, got)
+}
@@ -0,0 +1,250 @@
+package pflag
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rsb/failure"
+)
+
+// FeatureStage is the maturity of a feature gate.
+type FeatureStage string
+
+const (
+	// FeatureAlpha features are off by default and may change or go away
+	FeatureAlpha FeatureStage = "ALPHA"
+	// FeatureBeta features are well tested, but may still change
+	FeatureBeta FeatureStage = "BETA"
+	// FeatureGA features are stable
+	FeatureGA FeatureStage = "GA"
+	// FeatureDeprecated features will be removed, enabling one writes a
+	// deprecation warning
+	FeatureDeprecated FeatureStage = "DEPRECATED"
+)
+
+// FeatureSpec describes a feature gate.
+type FeatureSpec struct {
+	Default bool
+	Stage   FeatureStage
+	// LockToDefault rejects every attempt to set the gate to another value
+	// than its default
+	LockToDefault bool
+}
+
+// -- featureGates Value
+
+// FeatureGates is a registry of known feature gates and the value of a flag
+// such as --feature-gates=Foo=true,Bar=false that turns them on or off.
+// Gates that are not registered are rejected.
+type FeatureGates struct {
+	known   map[string]FeatureSpec
+	enabled map[string]bool   // gates set explicitly
+	warn    func(gate string) // warns of an enabled deprecated gate, set by FeatureGatesVar
+}
+
+// NewFeatureGates returns a registry of the given feature gates.
+func NewFeatureGates(known map[string]FeatureSpec) *FeatureGates {
+	g := &FeatureGates{
+		known:   make(map[string]FeatureSpec, len(known)),
+		enabled: map[string]bool{},
+	}
+	for name, spec := range known {
+		g.known[name] = spec
+	}
+	return g
+}
+
+// Add registers a feature gate.
+func (g *FeatureGates) Add(name string, spec FeatureSpec) error {
+	if name == "" {
+		return failure.InvalidParam("feature gate name must be set")
+	}
+	if _, ok := g.known[name]; ok {
+		return failure.AlreadyExists("feature gate (%s), already exists", name)
+	}
+
+	g.known[name] = spec
+	return nil
+}
+
+// Enabled returns whether the named gate is on, either because it was set or
+// by default. Unknown gates are off.
+func (g *FeatureGates) Enabled(name string) bool {
+	if enabled, ok := g.enabled[name]; ok {
+		return enabled
+	}
+	return g.known[name].Default
+}
+
+// KnownFeatures returns a sorted description of every registered gate, such
+// as "Foo=true|false (ALPHA - default=false)".
+func (g *FeatureGates) KnownFeatures() []string {
+	names := make([]string, 0, len(g.known))
+	for name := range g.known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]string, len(names))
+	for i, name := range names {
+		spec := g.known[name]
+		stage := spec.Stage
+		if stage == "" {
+			stage = FeatureGA
+		}
+		locked := ""
+		if spec.LockToDefault {
+			locked = ", locked"
+		}
+		out[i] = fmt.Sprintf("%s=true|false (%s - default=%t%s)", name, stage, spec.Default, locked)
+	}
+	return out
+}
+
+// Format: Foo=true,Bar=false
+func (g *FeatureGates) Set(val string) error {
+	out := map[string]string{}
+	for _, pair := range strings.Split(val, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("%s must be formatted as feature=true|false", pair)
+		}
+		out[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	parsed, err := g.parse(out)
+	if err != nil {
+		return err
+	}
+	g.warnDeprecated(parsed)
+	for name, enabled := range parsed {
+		g.enabled[name] = enabled
+	}
+	return nil
+}
+
+// warnDeprecated warns of the deprecated gates that parsed enables and were
+// not enabled already, sorted by name.
+func (g *FeatureGates) warnDeprecated(parsed map[string]bool) {
+	if g.warn == nil {
+		return
+	}
+	var names []string
+	for name, enabled := range parsed {
+		if enabled && !g.enabled[name] && g.known[name].Stage == FeatureDeprecated {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.warn(name)
+	}
+}
+
+// parse checks every gate in m against the registry.
+func (g *FeatureGates) parse(m map[string]string) (map[string]bool, error) {
+	out := make(map[string]bool, len(m))
+	for name, value := range m {
+		spec, ok := g.known[name]
+		if !ok {
+			return nil, fmt.Errorf("unrecognized feature gate: %s", name)
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s=%s, must be true or false", name, value)
+		}
+		if spec.LockToDefault && enabled != spec.Default {
+			return nil, fmt.Errorf("cannot set feature gate %s to %t, feature is locked to %t", name, enabled, spec.Default)
+		}
+		out[name] = enabled
+	}
+	return out, nil
+}
+
+func (g *FeatureGates) Type() string {
+	return "stringToBool"
+}
+
+// String returns the gates that were set, sorted by name.
+func (g *FeatureGates) String() string {
+	names := make([]string, 0, len(g.enabled))
+	for name := range g.enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + strconv.FormatBool(g.enabled[name])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (g *FeatureGates) ReplaceMap(val map[string]string) error {
+	parsed, err := g.parse(val)
+	if err != nil {
+		return err
+	}
+	g.warnDeprecated(parsed)
+	g.enabled = parsed
+	return nil
+}
+
+func (g *FeatureGates) GetMap() map[string]string {
+	out := make(map[string]string, len(g.enabled))
+	for name, enabled := range g.enabled {
+		out[name] = strconv.FormatBool(enabled)
+	}
+	return out
+}
+
+// GetFeatureGates returns the FeatureGates of a flag with the given name
+func (f *FlagSet) GetFeatureGates(name string) (*FeatureGates, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return nil, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	g, ok := flag.Value.(*FeatureGates)
+	if !ok {
+		return nil, failure.InvalidState("trying to get featureGates value of flag of type %s", flag.Value.Type())
+	}
+	return g, nil
+}
+
+// FeatureGatesVar defines a flag with specified name and usage string that
+// turns the gates registered in g on or off. The help output lists the gates.
+// Enabling a deprecated gate writes a deprecation warning.
+func (f *FlagSet) FeatureGatesVar(g *FeatureGates, name string, usage string) {
+	f.FeatureGatesVarP(g, name, "", usage)
+}
+
+// FeatureGatesVarP is like FeatureGatesVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) FeatureGatesVarP(g *FeatureGates, name, shorthand string, usage string) {
+	f.VarP(g, name, shorthand, usage)
+	flag := f.Lookup(name)
+	g.warn = func(gate string) {
+		f.warnDeprecated(DeprecationWarning{
+			Flag:    flag,
+			Message: f.message(MsgFeatureGateDeprecated, gate, flag.Name),
+		})
+	}
+}
+
+// FeatureGatesVar defines a flag with specified name and usage string that
+// turns the gates registered in g on or off. The help output lists the gates.
+// Enabling a deprecated gate writes a deprecation warning.
+func FeatureGatesVar(g *FeatureGates, name string, usage string) {
+	CommandLine.FeatureGatesVarP(g, name, "", usage)
+}
+
+// FeatureGatesVarP is like FeatureGatesVar, but accepts a shorthand letter that can be used after a single dash.
+func FeatureGatesVarP(g *FeatureGates, name, shorthand string, usage string) {
+	CommandLine.FeatureGatesVarP(g, name, shorthand, usage)
+}
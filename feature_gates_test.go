@@ -0,0 +1,102 @@
+package pflag_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpFeatureGates() (*pflag.FlagSet, *pflag.FeatureGates) {
+	g := pflag.NewFeatureGates(map[string]pflag.FeatureSpec{
+		"Foo":    {Default: false, Stage: pflag.FeatureAlpha},
+		"Bar":    {Default: true, Stage: pflag.FeatureBeta},
+		"Stable": {Default: true, Stage: pflag.FeatureGA, LockToDefault: true},
+	})
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.FeatureGatesVar(g, "feature-gates", "a set of key=value pairs that describe feature gates")
+	return f, g
+}
+
+func TestFeatureGates(t *testing.T) {
+	f, g := setUpFeatureGates()
+	require.False(t, g.Enabled("Foo"))
+	require.True(t, g.Enabled("Bar"))
+	require.False(t, g.Enabled("Missing"))
+
+	require.NoError(t, f.Parse([]string{"--feature-gates=Foo=true,Bar=false", "--feature-gates", "Stable=true"}))
+	require.True(t, g.Enabled("Foo"))
+	require.False(t, g.Enabled("Bar"))
+	require.True(t, g.Enabled("Stable"))
+	require.Equal(t, "Bar=false,Foo=true,Stable=true", f.Lookup("feature-gates").Value.String())
+
+	gates, err := f.GetFeatureGates("feature-gates")
+	require.NoError(t, err)
+	require.Same(t, g, gates)
+}
+
+func TestFeatureGatesInvalid(t *testing.T) {
+	testCases := []string{
+		"Missing=true",
+		"Foo=maybe",
+		"Foo",
+		"Stable=false",
+		"Foo=true,Missing=true",
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt, func(t *testing.T) {
+			f, g := setUpFeatureGates()
+			require.Error(t, f.Parse([]string{"--feature-gates=" + tt}))
+			require.False(t, g.Enabled("Foo"))
+		})
+	}
+}
+
+func TestFeatureGatesUsage(t *testing.T) {
+	f, g := setUpFeatureGates()
+	require.NoError(t, g.Add("Old", pflag.FeatureSpec{Stage: pflag.FeatureDeprecated}))
+	require.Error(t, g.Add("Old", pflag.FeatureSpec{}))
+
+	require.Equal(t, `      --feature-gates stringToBool   a set of key=value pairs that describe feature gates
+                                     Bar=true|false (BETA - default=true)
+                                     Foo=true|false (ALPHA - default=false)
+                                     Old=true|false (DEPRECATED - default=false)
+                                     Stable=true|false (GA - default=true, locked)
+`, f.FlagUsages())
+}
+
+func TestFeatureGatesConfig(t *testing.T) {
+	f, g := setUpFeatureGates()
+	require.NoError(t, f.ParseConfig(strings.NewReader(`{"feature-gates": {"Foo": true}}`), pflag.ConfigJSON))
+	require.True(t, g.Enabled("Foo"))
+
+	require.Error(t, f.ParseConfig(strings.NewReader(`{"feature-gates": {"Missing": true}}`), pflag.ConfigJSON))
+	require.True(t, g.Enabled("Foo"))
+}
+
+func TestFeatureGatesDeprecated(t *testing.T) {
+	f, g := setUpFeatureGates()
+	require.NoError(t, g.Add("Old", pflag.FeatureSpec{Stage: pflag.FeatureDeprecated}))
+	var warnings []pflag.DeprecationWarning
+	f.SetDeprecationHandler(func(w pflag.DeprecationWarning) {
+		warnings = append(warnings, w)
+	})
+
+	require.NoError(t, f.Parse([]string{"--feature-gates=Old=false,Foo=true"}))
+	require.Empty(t, warnings)
+
+	require.NoError(t, f.Parse([]string{"--feature-gates=Old=true", "--feature-gates=Old=true"}))
+	require.Len(t, warnings, 1)
+	require.Equal(t, "feature-gates", warnings[0].Flag.Name)
+	require.Equal(t, "Feature gate Old of --feature-gates has been deprecated and will be removed", warnings[0].Message)
+
+	g = pflag.NewFeatureGates(map[string]pflag.FeatureSpec{"Old": {Stage: pflag.FeatureDeprecated}})
+	f = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	buf := new(strings.Builder)
+	f.SetOutput(buf)
+	f.FeatureGatesVar(g, "feature-gates", "feature gates")
+	require.NoError(t, f.ParseConfig(strings.NewReader(`{"feature-gates": {"Old": true}}`), pflag.ConfigJSON))
+	require.Equal(t, "Feature gate Old of --feature-gates has been deprecated and will be removed\n", buf.String())
+}
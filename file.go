@@ -0,0 +1,112 @@
+package pflag
+
+// GetFile return the file path value of a flag with the given name
+func (f *FlagSet) GetFile(name string) (string, error) {
+	val, err := f.getFlagType(name, "file", stringConv)
+	if err != nil {
+		return "", err
+	}
+	return val.(string), nil
+}
+
+// FileVar defines a file path flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// Values are expanded, resolved and validated as the options request, the default value is
+// used as given. Values must be regular files when they exist, and are completed with file names.
+func (f *FlagSet) FileVar(p *string, name string, value string, usage string, opts ...PathOption) {
+	f.varPath(kindFile, p, name, "", value, usage, opts)
+}
+
+// FileVarP is like FileVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) FileVarP(p *string, name, shorthand string, value string, usage string, opts ...PathOption) {
+	f.varPath(kindFile, p, name, shorthand, value, usage, opts)
+}
+
+// FileVar defines a file path flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// Values are expanded, resolved and validated as the options request, the default value is
+// used as given. Values must be regular files when they exist, and are completed with file names.
+func FileVar(p *string, name string, value string, usage string, opts ...PathOption) {
+	CommandLine.varPath(kindFile, p, name, "", value, usage, opts)
+}
+
+// FileVarP is like FileVar, but accepts a shorthand letter that can be used after a single dash.
+func FileVarP(p *string, name, shorthand string, value string, usage string, opts ...PathOption) {
+	CommandLine.varPath(kindFile, p, name, shorthand, value, usage, opts)
+}
+
+// File defines a file path flag with specified name, default value, and usage string.
+// The return value is the address of a string variable that stores the value of the flag.
+func (f *FlagSet) File(name string, value string, usage string, opts ...PathOption) *string {
+	p := new(string)
+	f.FileVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// FileP is like File, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) FileP(name, shorthand string, value string, usage string, opts ...PathOption) *string {
+	p := new(string)
+	f.FileVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// File defines a file path flag with specified name, default value, and usage string.
+// The return value is the address of a string variable that stores the value of the flag.
+func File(name string, value string, usage string, opts ...PathOption) *string {
+	return CommandLine.FileP(name, "", value, usage, opts...)
+}
+
+// FileP is like File, but accepts a shorthand letter that can be used after a single dash.
+func FileP(name, shorthand string, value string, usage string, opts ...PathOption) *string {
+	return CommandLine.FileP(name, shorthand, value, usage, opts...)
+}
+
+// FileSliceVar defines a fileSlice flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag.
+// Each path is handled like the value of a File flag, patterns are expanded with the PathGlob option.
+func (f *FlagSet) FileSliceVar(p *[]string, name string, value []string, usage string, opts ...PathOption) {
+	f.varPathSlice(kindFile, p, name, "", value, usage, opts)
+}
+
+// FileSliceVarP is like FileSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) FileSliceVarP(p *[]string, name, shorthand string, value []string, usage string, opts ...PathOption) {
+	f.varPathSlice(kindFile, p, name, shorthand, value, usage, opts)
+}
+
+// FileSliceVar defines a fileSlice flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag.
+// Each path is handled like the value of a File flag, patterns are expanded with the PathGlob option.
+func FileSliceVar(p *[]string, name string, value []string, usage string, opts ...PathOption) {
+	CommandLine.varPathSlice(kindFile, p, name, "", value, usage, opts)
+}
+
+// FileSliceVarP is like FileSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func FileSliceVarP(p *[]string, name, shorthand string, value []string, usage string, opts ...PathOption) {
+	CommandLine.varPathSlice(kindFile, p, name, shorthand, value, usage, opts)
+}
+
+// FileSlice defines a fileSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []string variable that stores the value of the flag.
+func (f *FlagSet) FileSlice(name string, value []string, usage string, opts ...PathOption) *[]string {
+	p := []string{}
+	f.FileSliceVarP(&p, name, "", value, usage, opts...)
+	return &p
+}
+
+// FileSliceP is like FileSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) FileSliceP(name, shorthand string, value []string, usage string, opts ...PathOption) *[]string {
+	p := []string{}
+	f.FileSliceVarP(&p, name, shorthand, value, usage, opts...)
+	return &p
+}
+
+// FileSlice defines a fileSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []string variable that stores the value of the flag.
+func FileSlice(name string, value []string, usage string, opts ...PathOption) *[]string {
+	return CommandLine.FileSliceP(name, "", value, usage, opts...)
+}
+
+// FileSliceP is like FileSlice, but accepts a shorthand letter that can be used after a single dash.
+func FileSliceP(name, shorthand string, value []string, usage string, opts ...PathOption) *[]string {
+	return CommandLine.FileSliceP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,103 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GenFishCompletion writes a fish completion script for the flags in the
+// FlagSet to w.
+func (f *FlagSet) GenFishCompletion(w io.Writer) error {
+	program := f.completionProgram()
+
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, "# fish completion for %s\n\n", program)
+	_, _ = fmt.Fprintf(buf, "complete -c %s -e\n", fishQuote(program))
+	for _, cf := range f.completionFlags() {
+		line := fmt.Sprintf("complete -c %s -l %s", fishQuote(program), fishQuote(cf.name))
+		if cf.short != "" {
+			line += " -s " + fishQuote(cf.short)
+		}
+		if cf.takesValue {
+			switch {
+			case len(cf.values) > 0:
+				line += " -x -a " + fishQuote(strings.Join(cf.values, " "))
+			case cf.dirs:
+				line += " -x -a '(__fish_complete_directories (commandline -ct))'"
+			case len(cf.extensions) > 0:
+				suffixes := make([]string, len(cf.extensions))
+				for i, ext := range cf.extensions {
+					suffixes[i] = "__fish_complete_suffix " + fishQuote("."+ext)
+				}
+				line += " -x -a " + fishQuote("("+strings.Join(suffixes, "; ")+")")
+			case cf.files:
+				line += " -r -F"
+			default:
+				line += " -r"
+			}
+		}
+		if cf.usage != "" {
+			line += " -d " + fishQuote(cf.usage)
+		}
+		buf.WriteString(line + "\n")
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// fishQuote quotes s for fish, which only treats \\ and \' as escapes inside
+// single quotes.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// GenFishDynamicCompletion writes a fish completion script to w that asks
+// the program itself for candidates through CompleteCommand.
+func (f *FlagSet) GenFishDynamicCompletion(w io.Writer) error {
+	program := f.completionProgram()
+	fn := "__" + completionIdentifier(program) + "_complete"
+
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, "# fish completion for %s\n\n", program)
+	_, _ = fmt.Fprintf(buf, `function %s
+    set -l args (commandline -opc)
+    set -l current (commandline -ct)
+    set -l out ($args[1] %s $args[2..-1] "$current" 2>/dev/null)
+    or return
+    set -l directive (string replace -r '^:' '' -- $out[-1])
+    set -e out[-1]
+
+    if test (math "bitand($directive, %d)") -ne 0
+        return
+    end
+    set -l token (string replace -r '^-[^=]*=' '' -- "$current")
+    if test (math "bitand($directive, %d)") -ne 0
+        __fish_complete_directories $token
+        return
+    end
+    if test (math "bitand($directive, %d)") -ne 0
+        for ext in $out
+            __fish_complete_suffix $token .$ext
+        end
+        return
+    end
+
+    if test (count $out) -eq 0
+        if test (math "bitand($directive, %d)") -eq 0
+            __fish_complete_path $token
+        end
+        return
+    end
+    printf '%%s\n' $out
+end
+
+complete -c %s -e
+complete -c %s -f -a '(%s)'
+`, fn, CompleteCommand, CompletionError, CompletionFilterDirs, CompletionFilterFileExt, CompletionNoFileComp, fishQuote(program), fishQuote(program), fn)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
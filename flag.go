@@ -7,6 +7,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // CommandLine is the default set of command-line flags, parsed from os.Args.
@@ -15,16 +16,22 @@ var CommandLine = NewFlagSet(os.Args[0], ExitOnError)
 // ErrHelp is the error returned if the flag -help is invoked but no such flag is defined.
 var ErrHelp = errors.New("pflag: help requested")
 
+// OtherFlagsGroup is the English title of the section that lists the flags
+// without a group in help output, when other flags have one. See
+// MsgOtherFlags.
+const OtherFlagsGroup = "Other Flags"
+
 // NOTE: Usage is not just defaultUsage(CommandLine)
 // because it serves (via godoc flag Usage) as the example
 // for how to write your own usage function.
 
 // Usage prints to standard error a usage message documenting all defined command-line flags.
 // The function is a variable that may be changed to point to a custom function.
-// By default, it prints a simple header and calls PrintDefaults; for details about the
-// format of the output and how to control it, see the documentation for PrintDefaults.
+// By default, it prints a simple header, MsgUsage in the message catalog of CommandLine,
+// and calls PrintDefaults; for details about the format of the output and how to control
+// it, see the documentation for PrintDefaults.
 var Usage = func() {
-	_, _ = fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+	_, _ = fmt.Fprintln(os.Stderr, CommandLine.message(MsgUsage, os.Args[0]))
 	PrintDefaults()
 }
 
@@ -68,24 +75,44 @@ type Flag struct {
 	Hidden          bool                // allow flags to be hidden from help/usage text
 	ShortDeprecated string              // if the shorthand of this flag is deprecated, this string is the new or now thing to use
 	Annotations     map[string][]string // used for bash autocomplete code
+	Reloadable      bool                // allow a Reloader to re-apply the value from a config source
+	Sensitive       bool                // redact the value when the flags are exported
+	Completer       CompletionFunc      // returns candidates for the value during shell completion
+	EnvVar          string              // environment variable the value is read from by ParseEnv
+	Group           string              // section the flag is listed under in help output
+	UsageKey        string              // key of the usage in the message catalog
+	Deprecation     *Deprecation        // how a deprecated flag is phased out, see MarkDeprecatedWith
+
+	reloadDefault *valueSnapshot // value put back by a Reloader when the key is removed from the config
 }
 
 // defaultIsZeroValue returns true if the default value for this flag represents
 // a zero value.
 func (f *Flag) defaultIsZeroValue() bool {
 	switch f.Value.(type) {
+	case *optionalBoolValue:
+		return f.Default == OptionalBoolAuto
 	case boolFlag:
 		return f.Default == "false"
-	case *durationValue:
+	case *durationValue, *extendedDurationValue:
 		// Beginning in Go 1.7, duration zero values are "0s"
 		return f.Default == "0" || f.Default == "0s"
-	case *intValue, *int8Value, *int32Value, *int64Value, *uintValue, *uint8Value, *uint16Value, *uint32Value, *uint64Value, *countValue, *float32Value, *float64Value:
+	case *intValue, *int8Value, *int32Value, *int64Value, *uintValue, *uint8Value, *uint16Value, *uint32Value, *uint64Value, *countValue, *float32Value, *float64Value,
+		*intRangeValue:
 		return f.Default == "0"
-	case *stringValue:
+	case *stringValue, *pathValue, *secretValue, *optionalIntValue, *optionalDurationValue:
 		return f.Default == ""
 	case *ipValue, *ipMaskValue, *ipNetValue:
 		return f.Default == "<nil>"
-	case *intSliceValue, *stringSliceValue, *stringArrayValue:
+	case *byteSizeValue:
+		return f.Default == "0B"
+	case *addrValue, *prefixValue, *addrPortValue, *timeValue, *locationValue, *inputFileValue, *outputFileValue,
+		*urlValue, *hostPortValue, *regexpValue, *hardwareAddrValue, *portRangeValue:
+		return f.Default == ""
+	case *intSliceValue, *stringSliceValue, *stringArrayValue, *addrSliceValue, *prefixSliceValue, *addrPortSliceValue, *byteSizeSliceValue,
+		*timeSliceValue, *extendedDurationSliceValue, *urlSliceValue, *hostPortSliceValue,
+		*regexpSliceValue, *pathSliceValue, *hardwareAddrSliceValue,
+		*intRangeSliceValue, *portRangeSliceValue:
 		return f.Default == "[]"
 	default:
 		switch f.Value.String() {
@@ -122,6 +149,16 @@ type SliceValue interface {
 	GetSlice() []string
 }
 
+// MapValue is a secondary interface to all flags which hold a map of
+// key=value pairs. Like SliceValue it allows the whole map to be read and
+// replaced without going through the comma separated Set format.
+type MapValue interface {
+	// ReplaceMap will fully overwrite any data currently in the flag value map.
+	ReplaceMap(map[string]string) error
+	// GetMap returns the flag value map with every value as a string.
+	GetMap() map[string]string
+}
+
 // sortFlags returns the flags as a slice in lexicographical sorted order.
 func sortFlags(flags map[NormalizedName]*Flag) []*Flag {
 	list := make(sort.StringSlice, len(flags))
@@ -161,8 +198,12 @@ func UnquoteUsage(flag *Flag) (name string, usage string) {
 
 	name = flag.Value.Type()
 	switch name {
-	case "bool":
+	case "bool", "optionalBool":
 		name = ""
+	case "optionalInt":
+		name = "int"
+	case "optionalDuration":
+		name = "duration"
 	case "float64":
 		name = "float"
 	case "int64":
@@ -182,6 +223,72 @@ func UnquoteUsage(flag *Flag) (name string, usage string) {
 	return
 }
 
+// UsageFlag holds the pieces that describe a flag in help output.
+type UsageFlag struct {
+	Flag        *Flag
+	Short       string // shorthand, unless it is deprecated
+	Name        string
+	Varname     string // placeholder for the value, empty for booleans
+	NoOptDefVal string // formatted as [=value], empty when not shown
+	Usage       string
+	Default     string // formatted default, empty when it is the zero value, Redacted for sensitive flags
+	Deprecated  string
+}
+
+// newFlagUsage describes flag for help output, with its usage taken from the
+// message catalog when it has a usage key.
+func (f *FlagSet) newFlagUsage(flag *Flag) UsageFlag {
+	u := UsageFlag{
+		Flag:       flag,
+		Name:       flag.Name,
+		Deprecated: flag.Deprecated,
+	}
+	if flag.Short != "" && flag.ShortDeprecated == "" {
+		u.Short = flag.Short
+	}
+	described := flag
+	if flag.UsageKey != "" {
+		if usage, ok := f.messageCatalog().Usage(flag.UsageKey); ok {
+			localized := *flag
+			localized.Usage = usage
+			described = &localized
+		}
+	}
+	u.Varname, u.Usage = UnquoteUsage(described)
+	if g, ok := flag.Value.(*FeatureGates); ok {
+		u.Usage += "\n" + strings.Join(g.KnownFeatures(), "\n")
+	}
+
+	if flag.NoOptDefVal != "" {
+		switch flag.Value.Type() {
+		case "string":
+			u.NoOptDefVal = fmt.Sprintf("[=\"%s\"]", flag.NoOptDefVal)
+		case "bool", "optionalBool":
+			if flag.NoOptDefVal != "true" {
+				u.NoOptDefVal = fmt.Sprintf("[=%s]", flag.NoOptDefVal)
+			}
+		case "count":
+			if flag.NoOptDefVal != "+1" {
+				u.NoOptDefVal = fmt.Sprintf("[=%s]", flag.NoOptDefVal)
+			}
+		default:
+			u.NoOptDefVal = fmt.Sprintf("[=%s]", flag.NoOptDefVal)
+		}
+	}
+
+	if !flag.defaultIsZeroValue() {
+		switch {
+		case flag.Sensitive:
+			u.Default = Redacted
+		case flag.Value.Type() == "string":
+			u.Default = fmt.Sprintf("%q", flag.Default)
+		default:
+			u.Default = flag.Default
+		}
+	}
+	return u
+}
+
 // Wraps the string `s` to a maximum width `w` with leading indent
 // `i`. The first line is not indented (this is assumed to be done by
 // caller). Pass `w` == 0 to do no wrapping
@@ -232,19 +339,27 @@ func wrap(i, w int, s string) string {
 }
 
 // Splits the string `s` on whitespace into an initial substring up to
-// `i` runes in length and the remainder. Will go `slop` over `i` if
+// `i` columns in width and the remainder. Will go `slop` over `i` if
 // that encompasses the entire string (which allows the caller to
 // avoid short orphan words on the final line).
 func wrapN(i, slop int, s string) (string, string) {
-	if i+slop > len(s) {
+	if i+slop > displayWidth(s) {
 		return s, ""
 	}
 
-	w := strings.LastIndexAny(s[:i], " \t\n")
+	cut := widthIndex(s, i)
+	w := strings.LastIndexAny(s[:cut], " \t\n")
 	if w <= 0 {
+		// Text without spaces, such as Chinese or Japanese, may be broken
+		// between wide characters.
+		last, _ := utf8.DecodeLastRuneInString(s[:cut])
+		next, _ := utf8.DecodeRuneInString(s[cut:])
+		if cut > 0 && (runeWidth(last) == 2 || runeWidth(next) == 2) {
+			return s[:cut], s[cut:]
+		}
 		return s, ""
 	}
-	nlPos := strings.LastIndex(s[:i], "\n")
+	nlPos := strings.LastIndex(s[:cut], "\n")
 	if nlPos > 0 && nlPos < w {
 		return s[:nlPos], s[nlPos+1:]
 	}
@@ -276,8 +391,11 @@ func stripUnknownFlagValue(args []string) []string {
 
 // defaultUsage is the default function to print a usage message.
 func defaultUsage(f *FlagSet) {
-	_, _ = fmt.Fprintf(f.Output(), "Usage of %s:\n", f.name)
-	f.PrintDefaults()
+	r := f.usageRenderer
+	if r == nil {
+		r = defaultUsageRenderer
+	}
+	_ = r.RenderUsage(f.Output(), f)
 }
 
 // GetCommandLine returns the default FlagSet.
@@ -2,6 +2,7 @@ package pflag
 
 import (
 	"bytes"
+	"errors"
 	goflag "flag"
 	"fmt"
 	"io"
@@ -42,6 +43,15 @@ type FlagSet struct {
 	output            io.Writer // nil means stderr; use Output() accessor
 	interspersed      bool      // allow interspersed option/non-option args
 	normalizeNameFunc func(f *FlagSet, name string) NormalizedName
+	argsCompleter     CompletionFunc // completes positional arguments
+	jsonHelp          bool           // answer --help=json with the Schema
+	groupOrder        []string       // order of the group sections in help output
+	usageRenderer     UsageRenderer  // renders the default usage message, nil for DefaultUsageTemplate
+	color             ColorMode      // when help output is styled
+	catalog           MessageCatalog // messages and usages, nil for English
+	version           string         // version of the program, see SetVersion
+
+	deprecationHandler func(DeprecationWarning)
 
 	addedGoFlagSets []*goflag.FlagSet
 }
@@ -238,18 +248,7 @@ func (f *FlagSet) ArgsLenAtDash() int {
 // continue to function but will not show up in help or usage messages. Using
 // this flag will also print the given usage.
 func (f *FlagSet) MarkDeprecated(name, usage string) error {
-	flag := f.Lookup(name)
-	if flag == nil {
-		return failure.NotFound("flag (%s), does not exist", name)
-	}
-
-	if usage == "" {
-		return failure.InvalidParam("usage is empty, deprecated msg for (%s) must be set", name)
-	}
-
-	flag.Deprecated = usage
-	flag.Hidden = true
-	return nil
+	return f.MarkDeprecatedWith(name, Deprecation{Message: usage})
 }
 
 // MarkShortDeprecated will mark the shorthand of a flag deprecated in your
@@ -281,6 +280,57 @@ func (f *FlagSet) MarkHidden(name string) error {
 	return nil
 }
 
+// SetGroup lists the flag under a section titled group in help output.
+func (f *FlagSet) SetGroup(name, group string) error {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return failure.NotFound("flag (%s), does not exist", name)
+	}
+
+	if group == "" {
+		return failure.InvalidParam("group is empty, group for (%s) must be set", name)
+	}
+
+	flag.Group = group
+	return nil
+}
+
+// SetGroupOrder sets the order of the group sections in help output. Groups
+// that are not listed follow in the order their first flag is visited, and
+// flags without a group come last.
+func (f *FlagSet) SetGroupOrder(groups ...string) {
+	f.groupOrder = groups
+}
+
+// MarkReloadable allows a Reloader to re-apply the value of the flag from
+// its config source while the program is running. When the key of the flag
+// is removed from the config, the Reloader puts back the value the flag has
+// when it is marked.
+func (f *FlagSet) MarkReloadable(name string) error {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return failure.NotFound("flag (%s), does not exist", name)
+	}
+
+	flag.Reloadable = true
+	snap := snapshotValue(flag)
+	flag.reloadDefault = &snap
+	return nil
+}
+
+// MarkSensitive indicates that the value of the flag must not be disclosed.
+// Exported configs and error messages will contain a placeholder instead of
+// the value, and errors of its Value are replaced with a generic message.
+func (f *FlagSet) MarkSensitive(name string) error {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return failure.NotFound("flag (%s), does not exist", name)
+	}
+
+	flag.Sensitive = true
+	return nil
+}
+
 // Set sets the value of the named flag
 func (f *FlagSet) Set(name, value string) error {
 	normalName := f.normalizeFlagName(name)
@@ -289,6 +339,10 @@ func (f *FlagSet) Set(name, value string) error {
 		return failure.NotFound("flag (%s), does not exist", name)
 	}
 
+	if err := f.checkRemoved(flag); err != nil {
+		return err
+	}
+
 	if err := flag.Value.Set(value); err != nil {
 		var flagName string
 		if flag.Short != "" && flag.ShortDeprecated == "" {
@@ -296,7 +350,7 @@ func (f *FlagSet) Set(name, value string) error {
 		} else {
 			flagName = fmt.Sprintf("--%s", flag.Name)
 		}
-		return failure.InvalidParam("invalid argument %q for %q flag: %v", value, flagName, err)
+		return f.invalidArgument(flag, flagName, value, err)
 	}
 
 	if !flag.Changed {
@@ -309,13 +363,23 @@ func (f *FlagSet) Set(name, value string) error {
 	}
 
 	if flag.Deprecated != "" {
-		_, _ = fmt.Fprintf(
-			f.Output(),
-			"Flag --%s has been deprecated, %s\n", flag.Name, flag.Deprecated,
-		)
+		f.warnDeprecated(DeprecationWarning{
+			Flag:    flag,
+			Message: f.message(MsgFlagDeprecated, flag.Name, flag.Deprecated),
+		})
 	}
 
-	return nil
+	return f.forward(flag, value)
+}
+
+// invalidArgument returns the error for a value the flag rejected. For a
+// sensitive flag the value is replaced with Redacted and err is left out, as
+// it may quote the value in any form.
+func (f *FlagSet) invalidArgument(flag *Flag, flagName, value string, err error) error {
+	if flag.Sensitive {
+		return failure.InvalidParam("%s", f.message(MsgInvalidSensitiveArgument, Redacted, flagName))
+	}
+	return failure.InvalidParam("%s", f.message(MsgInvalidArgument, value, flagName, err))
 }
 
 // SetAnnotation allows one to set arbitrary annotations on a flag in
@@ -350,82 +414,23 @@ func (f *FlagSet) Changed(name string) bool {
 }
 
 // PrintDefaults prints, to standard error unless configured otherwise,
-// the values of all defined flags in a set.
+// the values of all defined flags in a set. When the output is a terminal
+// the text is wrapped to its width and styled, see SetColor.
 func (f *FlagSet) PrintDefaults() {
-	usages := f.FlagUsages()
-	_, _ = fmt.Fprintf(f.Output(), usages)
+	out := f.Output()
+	width, _ := terminalWidth(out)
+	buf := new(bytes.Buffer)
+	writeUsageSections(buf, f.UsageSections(), width, f.colorEnabled(out), f.messageCatalog())
+	_, _ = out.Write(buf.Bytes())
 }
 
 // FlagUsagesWrapped returns a string containing the usage information
-// for all flags in the FlagSet. Wrapped to `cols` columns (0 for no wrapping)
+// for all flags in the FlagSet. Wrapped to `cols` columns (0 for no wrapping).
+// When flags are assigned to groups every group gets a titled section, in the
+// order set with SetGroupOrder, followed by the flags without a group.
 func (f *FlagSet) FlagUsagesWrapped(cols int) string {
 	buf := new(bytes.Buffer)
-
-	lines := make([]string, 0, len(f.formal))
-
-	maxlen := 0
-	f.VisitAll(func(flag *Flag) {
-		if flag.Hidden {
-			return
-		}
-
-		line := ""
-		if flag.Short != "" && flag.ShortDeprecated == "" {
-			line = fmt.Sprintf("  -%s, --%s", flag.Short, flag.Name)
-		} else {
-			line = fmt.Sprintf("      --%s", flag.Name)
-		}
-
-		varname, usage := UnquoteUsage(flag)
-		if varname != "" {
-			line += " " + varname
-		}
-		if flag.NoOptDefVal != "" {
-			switch flag.Value.Type() {
-			case "string":
-				line += fmt.Sprintf("[=\"%s\"]", flag.NoOptDefVal)
-			case "bool":
-				if flag.NoOptDefVal != "true" {
-					line += fmt.Sprintf("[=%s]", flag.NoOptDefVal)
-				}
-			case "count":
-				if flag.NoOptDefVal != "+1" {
-					line += fmt.Sprintf("[=%s]", flag.NoOptDefVal)
-				}
-			default:
-				line += fmt.Sprintf("[=%s]", flag.NoOptDefVal)
-			}
-		}
-
-		// This special character will be replaced with spacing once the
-		// correct alignment is calculated
-		line += "\x00"
-		if len(line) > maxlen {
-			maxlen = len(line)
-		}
-
-		line += usage
-		if !flag.defaultIsZeroValue() {
-			if flag.Value.Type() == "string" {
-				line += fmt.Sprintf(" (default %q)", flag.Default)
-			} else {
-				line += fmt.Sprintf(" (default %s)", flag.Default)
-			}
-		}
-		if len(flag.Deprecated) != 0 {
-			line += fmt.Sprintf(" (DEPRECATED: %s)", flag.Deprecated)
-		}
-
-		lines = append(lines, line)
-	})
-
-	for _, line := range lines {
-		sidx := strings.Index(line, "\x00")
-		spacing := strings.Repeat(" ", maxlen-sidx)
-		// maxlen + 2 comes from + 1 for the \x00 and + 1 for the (deliberate) off-by-one in maxlen-sidx
-		_, _ = fmt.Fprintln(buf, line[:sidx], spacing, wrap(maxlen+2, cols, line[sidx+1:]))
-	}
-
+	writeUsageSections(buf, f.UsageSections(), cols, false, f.messageCatalog())
 	return buf.String()
 }
 
@@ -631,10 +636,14 @@ func (f *FlagSet) getFlagType(name string, ftype string, convFunc func(sval stri
 	return result, nil
 }
 
-// failf prints to standard error a formatted error and usage message and
-// returns the error.
-func (f *FlagSet) failf(format string, a ...interface{}) error {
-	err := fmt.Errorf(format, a...)
+// failf prints to standard error the message id formatted with a and the
+// usage message, and returns the message as an error.
+func (f *FlagSet) failf(id MessageID, a ...interface{}) error {
+	return f.fail(errors.New(f.message(id, a...)))
+}
+
+// fail prints to standard error err and the usage message, and returns err.
+func (f *FlagSet) fail(err error) error {
 	if f.errorHandling != ContinueOnError {
 		_, _ = fmt.Fprintln(f.Output(), err)
 		f.usage()
@@ -658,7 +667,7 @@ func (f *FlagSet) parseLongArg(s string, args []string, fn parseFunc) (a []strin
 	a = args
 	name := s[2:]
 	if len(name) == 0 || name[0] == '-' || name[0] == '=' {
-		err = f.failf("bad flag syntax: %s", s)
+		err = f.failf(MsgBadFlagSyntax, s)
 		return
 	}
 
@@ -668,6 +677,8 @@ func (f *FlagSet) parseLongArg(s string, args []string, fn parseFunc) (a []strin
 
 	if !exists {
 		switch {
+		case name == "help" && f.jsonHelp && len(split) == 2 && split[1] == "json":
+			return a, f.helpJSON()
 		case name == "help":
 			f.usage()
 			return a, ErrHelp
@@ -680,7 +691,7 @@ func (f *FlagSet) parseLongArg(s string, args []string, fn parseFunc) (a []strin
 
 			return stripUnknownFlagValue(a), nil
 		default:
-			err = f.failf("unknown flag: --%s", name)
+			err = f.failf(MsgUnknownFlag, name)
 			return
 		}
 	}
@@ -698,13 +709,13 @@ func (f *FlagSet) parseLongArg(s string, args []string, fn parseFunc) (a []strin
 		a = a[1:]
 	} else {
 		// '--flag' (arg was required)
-		err = f.failf("flag needs an argument: %s", s)
+		err = f.failf(MsgFlagNeedsArgument, s)
 		return
 	}
 
 	err = fn(flag, value)
 	if err != nil {
-		_ = f.failf(err.Error())
+		_ = f.fail(err)
 	}
 	return
 }
@@ -737,7 +748,7 @@ func (f *FlagSet) parseSingleShortArg(shorthands string, args []string, fn parse
 			outArgs = stripUnknownFlagValue(outArgs)
 			return
 		default:
-			err = f.failf("unknown shorthand flag: %q in -%s", c, shorthands)
+			err = f.failf(MsgUnknownShorthand, c, shorthands)
 			return
 		}
 	}
@@ -760,17 +771,21 @@ func (f *FlagSet) parseSingleShortArg(shorthands string, args []string, fn parse
 		outArgs = args[1:]
 	} else {
 		// '-f' (arg was required)
-		err = f.failf("flag needs an argument: %q in -%s", c, shorthands)
+		err = f.failf(MsgShorthandNeedsArgument, c, shorthands)
 		return
 	}
 
 	if flag.ShortDeprecated != "" {
-		_, _ = fmt.Fprintf(f.Output(), "Flag shorthand -%s has been deprecated, %s\n", flag.Short, flag.ShortDeprecated)
+		f.warnDeprecated(DeprecationWarning{
+			Flag:      flag,
+			Shorthand: true,
+			Message:   f.message(MsgShorthandDeprecated, flag.Short, flag.ShortDeprecated),
+		})
 	}
 
 	err = fn(flag, value)
 	if err != nil {
-		_ = f.failf(err.Error())
+		_ = f.fail(err)
 	}
 	return
 }
@@ -0,0 +1,176 @@
+package pflag
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// MACFormat selects the notation the values of HardwareAddr flags are
+// written in by String, in help output and exports.
+type MACFormat int
+
+const (
+	// MACAsGiven keeps the notation the value was given in, defaults are
+	// written as MACColon
+	MACAsGiven MACFormat = iota
+	// MACColon writes lower-case octets separated by colons, as in
+	// 00:00:5e:00:53:01
+	MACColon
+	// MACHyphen writes upper-case octets separated by hyphens, the IEEE 802
+	// notation, as in 00-00-5E-00-53-01
+	MACHyphen
+	// MACDot writes lower-case groups of four digits separated by dots, as
+	// in 0000.5e00.5301
+	MACDot
+)
+
+func combineMACFormat(format []MACFormat) MACFormat {
+	if len(format) == 0 {
+		return MACAsGiven
+	}
+	return format[len(format)-1]
+}
+
+// parseMAC parses an EUI-48 or EUI-64 address in colon, hyphen or dot
+// notation. An empty string is the nil address.
+func parseMAC(s string) (net.HardwareAddr, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	return parseEUI(s)
+}
+
+// parseEUI parses an EUI-48 or EUI-64 address. The 20-octet IP over
+// InfiniBand addresses net.ParseMAC also accepts are rejected.
+func parseEUI(s string) (net.HardwareAddr, error) {
+	addr, err := net.ParseMAC(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(addr) != 6 && len(addr) != 8 {
+		return nil, fmt.Errorf("%s is not an EUI-48 or EUI-64 address", s)
+	}
+	return addr, nil
+}
+
+// formatMAC writes addr in format, given is the notation it was parsed from.
+func formatMAC(addr net.HardwareAddr, given string, format MACFormat) string {
+	if len(addr) == 0 {
+		return ""
+	}
+	switch format {
+	case MACAsGiven:
+		if given != "" {
+			return given
+		}
+	case MACHyphen:
+		return strings.ToUpper(strings.ReplaceAll(addr.String(), ":", "-"))
+	case MACDot:
+		digits := hex.EncodeToString(addr)
+		groups := make([]string, 0, len(digits)/4)
+		for i := 0; i < len(digits); i += 4 {
+			groups = append(groups, digits[i:i+4])
+		}
+		return strings.Join(groups, ".")
+	}
+	return addr.String()
+}
+
+// -- net.HardwareAddr value
+type hardwareAddrValue struct {
+	value  *net.HardwareAddr
+	given  string
+	format MACFormat
+}
+
+func newHardwareAddrValue(val net.HardwareAddr, p *net.HardwareAddr, format []MACFormat) *hardwareAddrValue {
+	*p = val
+	return &hardwareAddrValue{value: p, format: combineMACFormat(format)}
+}
+
+func (h *hardwareAddrValue) String() string {
+	return formatMAC(*h.value, h.given, h.format)
+}
+
+// Set parses s as a MAC address, an empty string sets the nil address.
+func (h *hardwareAddrValue) Set(s string) error {
+	addr, err := parseMAC(s)
+	if err != nil {
+		return err
+	}
+	*h.value = addr
+	h.given = strings.TrimSpace(s)
+	return nil
+}
+
+func (h *hardwareAddrValue) Type() string {
+	return "hardwareAddr"
+}
+
+func hardwareAddrConv(sval string) (interface{}, error) {
+	return parseMAC(sval)
+}
+
+// GetHardwareAddr return the net.HardwareAddr value of a flag with the given name
+func (f *FlagSet) GetHardwareAddr(name string) (net.HardwareAddr, error) {
+	val, err := f.getFlagType(name, "hardwareAddr", hardwareAddrConv)
+	if err != nil {
+		return nil, err
+	}
+	return val.(net.HardwareAddr), nil
+}
+
+// HardwareAddrVar defines a net.HardwareAddr flag with specified name, default value, and usage string.
+// The argument p points to a net.HardwareAddr variable in which to store the value of the flag.
+// EUI-48 and EUI-64 addresses are accepted in colon, hyphen and dot notation, format selects the
+// notation the value is shown in.
+func (f *FlagSet) HardwareAddrVar(p *net.HardwareAddr, name string, value net.HardwareAddr, usage string, format ...MACFormat) {
+	f.VarP(newHardwareAddrValue(value, p, format), name, "", usage)
+}
+
+// HardwareAddrVarP is like HardwareAddrVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) HardwareAddrVarP(p *net.HardwareAddr, name, shorthand string, value net.HardwareAddr, usage string, format ...MACFormat) {
+	f.VarP(newHardwareAddrValue(value, p, format), name, shorthand, usage)
+}
+
+// HardwareAddrVar defines a net.HardwareAddr flag with specified name, default value, and usage string.
+// The argument p points to a net.HardwareAddr variable in which to store the value of the flag.
+// EUI-48 and EUI-64 addresses are accepted in colon, hyphen and dot notation, format selects the
+// notation the value is shown in.
+func HardwareAddrVar(p *net.HardwareAddr, name string, value net.HardwareAddr, usage string, format ...MACFormat) {
+	CommandLine.VarP(newHardwareAddrValue(value, p, format), name, "", usage)
+}
+
+// HardwareAddrVarP is like HardwareAddrVar, but accepts a shorthand letter that can be used after a single dash.
+func HardwareAddrVarP(p *net.HardwareAddr, name, shorthand string, value net.HardwareAddr, usage string, format ...MACFormat) {
+	CommandLine.VarP(newHardwareAddrValue(value, p, format), name, shorthand, usage)
+}
+
+// HardwareAddr defines a net.HardwareAddr flag with specified name, default value, and usage string.
+// The return value is the address of a net.HardwareAddr variable that stores the value of the flag.
+func (f *FlagSet) HardwareAddr(name string, value net.HardwareAddr, usage string, format ...MACFormat) *net.HardwareAddr {
+	p := new(net.HardwareAddr)
+	f.HardwareAddrVarP(p, name, "", value, usage, format...)
+	return p
+}
+
+// HardwareAddrP is like HardwareAddr, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) HardwareAddrP(name, shorthand string, value net.HardwareAddr, usage string, format ...MACFormat) *net.HardwareAddr {
+	p := new(net.HardwareAddr)
+	f.HardwareAddrVarP(p, name, shorthand, value, usage, format...)
+	return p
+}
+
+// HardwareAddr defines a net.HardwareAddr flag with specified name, default value, and usage string.
+// The return value is the address of a net.HardwareAddr variable that stores the value of the flag.
+func HardwareAddr(name string, value net.HardwareAddr, usage string, format ...MACFormat) *net.HardwareAddr {
+	return CommandLine.HardwareAddrP(name, "", value, usage, format...)
+}
+
+// HardwareAddrP is like HardwareAddr, but accepts a shorthand letter that can be used after a single dash.
+func HardwareAddrP(name, shorthand string, value net.HardwareAddr, usage string, format ...MACFormat) *net.HardwareAddr {
+	return CommandLine.HardwareAddrP(name, shorthand, value, usage, format...)
+}
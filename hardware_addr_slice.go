@@ -0,0 +1,188 @@
+package pflag
+
+import (
+	"io"
+	"net"
+	"strings"
+)
+
+// -- hardwareAddrSlice Value
+type hardwareAddrSliceValue struct {
+	value   *[]net.HardwareAddr
+	given   []string
+	format  MACFormat
+	changed bool
+}
+
+func newHardwareAddrSliceValue(val []net.HardwareAddr, p *[]net.HardwareAddr, format []MACFormat) *hardwareAddrSliceValue {
+	hsv := &hardwareAddrSliceValue{value: p, format: combineMACFormat(format)}
+	*hsv.value = val
+	hsv.given = make([]string, len(val))
+	return hsv
+}
+
+// Set converts, and assigns, the comma-separated MAC address argument string representation as the []net.HardwareAddr value of this flag.
+// If Set is called on a flag that already has a []net.HardwareAddr assigned, the newly converted values will be appended.
+func (s *hardwareAddrSliceValue) Set(val string) error {
+	ss, err := readAsCSV(val)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	out, given, err := s.parse(ss)
+	if err != nil {
+		return err
+	}
+
+	if !s.changed {
+		*s.value, s.given = out, given
+	} else {
+		*s.value = append(*s.value, out...)
+		s.given = append(s.given, given...)
+	}
+	s.changed = true
+	return nil
+}
+
+// parse converts the addresses in ss, keeping the notation they were given
+// in.
+func (s *hardwareAddrSliceValue) parse(ss []string) ([]net.HardwareAddr, []string, error) {
+	out := make([]net.HardwareAddr, len(ss))
+	given := make([]string, len(ss))
+	for i, sval := range ss {
+		addr, err := s.fromString(sval)
+		if err != nil {
+			return nil, nil, err
+		}
+		out[i] = addr
+		given[i] = strings.TrimSpace(sval)
+	}
+	return out, given, nil
+}
+
+// Type returns a string that uniquely represents this flag's type.
+func (s *hardwareAddrSliceValue) Type() string {
+	return "hardwareAddrSlice"
+}
+
+// String defines a "native" format for this net.HardwareAddr slice flag value.
+func (s *hardwareAddrSliceValue) String() string {
+	out, _ := writeAsCSV(s.GetSlice())
+	return "[" + out + "]"
+}
+
+func (s *hardwareAddrSliceValue) fromString(val string) (net.HardwareAddr, error) {
+	return parseEUI(strings.TrimSpace(val))
+}
+
+func (s *hardwareAddrSliceValue) toString(i int) string {
+	var given string
+	if len(s.given) == len(*s.value) {
+		given = s.given[i]
+	}
+	return formatMAC((*s.value)[i], given, s.format)
+}
+
+func (s *hardwareAddrSliceValue) Append(val string) error {
+	out, given, err := s.parse([]string{val})
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, out...)
+	s.given = append(s.given, given...)
+	return nil
+}
+
+func (s *hardwareAddrSliceValue) Replace(val []string) error {
+	out, given, err := s.parse(val)
+	if err != nil {
+		return err
+	}
+	*s.value, s.given = out, given
+	return nil
+}
+
+func (s *hardwareAddrSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i := range *s.value {
+		out[i] = s.toString(i)
+	}
+	return out
+}
+
+func hardwareAddrSliceConv(val string) (interface{}, error) {
+	val = strings.Trim(val, "[]")
+	// Empty string would cause a slice with one (empty) entry
+	if len(val) == 0 {
+		return []net.HardwareAddr{}, nil
+	}
+	ss := strings.Split(val, ",")
+	out := make([]net.HardwareAddr, len(ss))
+	for i, sval := range ss {
+		addr, err := parseEUI(strings.TrimSpace(sval))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = addr
+	}
+	return out, nil
+}
+
+// GetHardwareAddrSlice returns the []net.HardwareAddr value of a flag with the given name
+func (f *FlagSet) GetHardwareAddrSlice(name string) ([]net.HardwareAddr, error) {
+	val, err := f.getFlagType(name, "hardwareAddrSlice", hardwareAddrSliceConv)
+	if err != nil {
+		return []net.HardwareAddr{}, err
+	}
+	return val.([]net.HardwareAddr), nil
+}
+
+// HardwareAddrSliceVar defines a hardwareAddrSlice flag with specified name, default value, and usage string.
+// The argument p points to a []net.HardwareAddr variable in which to store the value of the flag.
+// format selects the notation the values are shown in.
+func (f *FlagSet) HardwareAddrSliceVar(p *[]net.HardwareAddr, name string, value []net.HardwareAddr, usage string, format ...MACFormat) {
+	f.VarP(newHardwareAddrSliceValue(value, p, format), name, "", usage)
+}
+
+// HardwareAddrSliceVarP is like HardwareAddrSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) HardwareAddrSliceVarP(p *[]net.HardwareAddr, name, shorthand string, value []net.HardwareAddr, usage string, format ...MACFormat) {
+	f.VarP(newHardwareAddrSliceValue(value, p, format), name, shorthand, usage)
+}
+
+// HardwareAddrSliceVar defines a []net.HardwareAddr flag with specified name, default value, and usage string.
+// The argument p points to a []net.HardwareAddr variable in which to store the value of the flag.
+// format selects the notation the values are shown in.
+func HardwareAddrSliceVar(p *[]net.HardwareAddr, name string, value []net.HardwareAddr, usage string, format ...MACFormat) {
+	CommandLine.VarP(newHardwareAddrSliceValue(value, p, format), name, "", usage)
+}
+
+// HardwareAddrSliceVarP is like HardwareAddrSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func HardwareAddrSliceVarP(p *[]net.HardwareAddr, name, shorthand string, value []net.HardwareAddr, usage string, format ...MACFormat) {
+	CommandLine.VarP(newHardwareAddrSliceValue(value, p, format), name, shorthand, usage)
+}
+
+// HardwareAddrSlice defines a []net.HardwareAddr flag with specified name, default value, and usage string.
+// The return value is the address of a []net.HardwareAddr variable that stores the value of that flag.
+func (f *FlagSet) HardwareAddrSlice(name string, value []net.HardwareAddr, usage string, format ...MACFormat) *[]net.HardwareAddr {
+	p := []net.HardwareAddr{}
+	f.HardwareAddrSliceVarP(&p, name, "", value, usage, format...)
+	return &p
+}
+
+// HardwareAddrSliceP is like HardwareAddrSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) HardwareAddrSliceP(name, shorthand string, value []net.HardwareAddr, usage string, format ...MACFormat) *[]net.HardwareAddr {
+	p := []net.HardwareAddr{}
+	f.HardwareAddrSliceVarP(&p, name, shorthand, value, usage, format...)
+	return &p
+}
+
+// HardwareAddrSlice defines a []net.HardwareAddr flag with specified name, default value, and usage string.
+// The return value is the address of a []net.HardwareAddr variable that stores the value of the flag.
+func HardwareAddrSlice(name string, value []net.HardwareAddr, usage string, format ...MACFormat) *[]net.HardwareAddr {
+	return CommandLine.HardwareAddrSliceP(name, "", value, usage, format...)
+}
+
+// HardwareAddrSliceP is like HardwareAddrSlice, but accepts a shorthand letter that can be used after a single dash.
+func HardwareAddrSliceP(name, shorthand string, value []net.HardwareAddr, usage string, format ...MACFormat) *[]net.HardwareAddr {
+	return CommandLine.HardwareAddrSliceP(name, shorthand, value, usage, format...)
+}
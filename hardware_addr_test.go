@@ -0,0 +1,117 @@
+package pflag_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHardwareAddr(t *testing.T) {
+	expected := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}
+	eui64 := net.HardwareAddr{0x02, 0x00, 0x5e, 0x10, 0x00, 0x00, 0x00, 0x01}
+
+	tests := []struct {
+		value    string
+		expected net.HardwareAddr
+		err      bool
+	}{
+		{"00:00:5e:00:53:01", expected, false},
+		{"00-00-5E-00-53-01", expected, false},
+		{"0000.5e00.5301", expected, false},
+		{" 00:00:5e:00:53:01 ", expected, false},
+		{"02:00:5e:10:00:00:00:01", eui64, false},
+		{"0200.5e10.0000.0001", eui64, false},
+		{"", nil, false},
+		{"00:00:5e:00:53", nil, true},
+		{"zz:00:5e:00:53:01", nil, true},
+		{"00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01", nil, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			mac := f.HardwareAddrP("mac", "m", nil, "interface address")
+
+			err := f.Parse([]string{"--mac", tc.value})
+			if tc.err {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "--mac")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, *mac)
+
+			got, err := f.GetHardwareAddr("mac")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestHardwareAddrFormat(t *testing.T) {
+	tests := []struct {
+		format   pflag.MACFormat
+		expected string
+	}{
+		{pflag.MACAsGiven, "0000.5E00.5301"},
+		{pflag.MACColon, "00:00:5e:00:53:01"},
+		{pflag.MACHyphen, "00-00-5E-00-53-01"},
+		{pflag.MACDot, "0000.5e00.5301"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expected, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			f.HardwareAddr("mac", nil, "interface address", tc.format)
+			f.HardwareAddrSlice("macs", nil, "interface addresses", tc.format)
+
+			require.NoError(t, f.Parse([]string{"--mac", "0000.5E00.5301", "--macs", "0000.5E00.5301"}))
+			require.Equal(t, tc.expected, f.Lookup("mac").Value.String())
+			require.Equal(t, "["+tc.expected+"]", f.Lookup("macs").Value.String())
+		})
+	}
+}
+
+func TestHardwareAddrDefault(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.HardwareAddr("mac", net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}, "interface address")
+	f.HardwareAddr("gateway", nil, "gateway address", pflag.MACHyphen)
+	f.HardwareAddrSlice("peers", nil, "peer addresses")
+
+	usages := f.FlagUsages()
+	require.Contains(t, usages, "interface address (default 00:00:5e:00:53:01)\n")
+	require.Contains(t, usages, "gateway address\n")
+	require.Contains(t, usages, "peer addresses\n")
+}
+
+func TestHardwareAddrSlice(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	macs := f.HardwareAddrSliceP("mac", "m", []net.HardwareAddr{{0x00, 0x00, 0x5e, 0x00, 0x53, 0xff}}, "interface addresses", pflag.MACColon)
+
+	require.Equal(t, "[00:00:5e:00:53:ff]", f.Lookup("mac").Default)
+	require.NoError(t, f.Parse([]string{"--mac", "00:00:5e:00:53:01,00-00-5E-00-53-02", "-m", "0000.5e00.5303"}))
+	require.Equal(t, []net.HardwareAddr{
+		{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01},
+		{0x00, 0x00, 0x5e, 0x00, 0x53, 0x02},
+		{0x00, 0x00, 0x5e, 0x00, 0x53, 0x03},
+	}, *macs)
+	require.Equal(t, "[00:00:5e:00:53:01,00:00:5e:00:53:02,00:00:5e:00:53:03]", f.Lookup("mac").Value.String())
+
+	got, err := f.GetHardwareAddrSlice("mac")
+	require.NoError(t, err)
+	require.Equal(t, *macs, got)
+
+	err = f.Parse([]string{"--mac", "00:00:5e:00:53"})
+	require.Error(t, err)
+
+	sv, ok := f.Lookup("mac").Value.(pflag.SliceValue)
+	require.True(t, ok)
+	require.NoError(t, sv.Replace([]string{"00-00-5E-00-53-0A"}))
+	require.NoError(t, sv.Append("0000.5e00.530b"))
+	require.Equal(t, []string{"00:00:5e:00:53:0a", "00:00:5e:00:53:0b"}, sv.GetSlice())
+	require.Error(t, sv.Append("nope"))
+	require.Error(t, sv.Append("00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"))
+	require.Error(t, sv.Replace([]string{"00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"}))
+}
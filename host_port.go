@@ -0,0 +1,92 @@
+package pflag
+
+// -- Endpoint Value
+type hostPortValue struct {
+	value *Endpoint
+	opts  endpointOptions
+}
+
+func newHostPortValue(val Endpoint, p *Endpoint, opts []EndpointOption) *hostPortValue {
+	*p = val
+	return &hostPortValue{value: p, opts: newEndpointOptions(opts)}
+}
+
+// Set parses s as host:port, host or :port. An empty string sets the zero
+// Endpoint.
+func (v *hostPortValue) Set(s string) error {
+	hp, err := parseHostPort(s, v.opts)
+	if err != nil {
+		return err
+	}
+	*v.value = hp
+	return nil
+}
+
+func (v *hostPortValue) Type() string {
+	return "hostPort"
+}
+
+func (v *hostPortValue) String() string { return v.value.String() }
+
+func hostPortConv(sval string) (interface{}, error) {
+	return parseHostPort(sval, endpointOptions{})
+}
+
+// GetHostPort returns the Endpoint value of a flag with the given name
+func (f *FlagSet) GetHostPort(name string) (Endpoint, error) {
+	val, err := f.getFlagType(name, "hostPort", hostPortConv)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	return val.(Endpoint), nil
+}
+
+// HostPortVar defines a host:port flag with specified name, default value, and usage string.
+// The argument p points to an Endpoint variable in which to store the value of the flag.
+// The options validate and complete the values.
+func (f *FlagSet) HostPortVar(p *Endpoint, name string, value Endpoint, usage string, opts ...EndpointOption) {
+	f.VarP(newHostPortValue(value, p, opts), name, "", usage)
+}
+
+// HostPortVarP is like HostPortVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) HostPortVarP(p *Endpoint, name, shorthand string, value Endpoint, usage string, opts ...EndpointOption) {
+	f.VarP(newHostPortValue(value, p, opts), name, shorthand, usage)
+}
+
+// HostPortVar defines a host:port flag with specified name, default value, and usage string.
+// The argument p points to an Endpoint variable in which to store the value of the flag.
+// The options validate and complete the values.
+func HostPortVar(p *Endpoint, name string, value Endpoint, usage string, opts ...EndpointOption) {
+	CommandLine.VarP(newHostPortValue(value, p, opts), name, "", usage)
+}
+
+// HostPortVarP is like HostPortVar, but accepts a shorthand letter that can be used after a single dash.
+func HostPortVarP(p *Endpoint, name, shorthand string, value Endpoint, usage string, opts ...EndpointOption) {
+	CommandLine.VarP(newHostPortValue(value, p, opts), name, shorthand, usage)
+}
+
+// HostPort defines a host:port flag with specified name, default value, and usage string.
+// The return value is the address of an Endpoint variable that stores the value of the flag.
+func (f *FlagSet) HostPort(name string, value Endpoint, usage string, opts ...EndpointOption) *Endpoint {
+	p := new(Endpoint)
+	f.HostPortVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// HostPortP is like HostPort, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) HostPortP(name, shorthand string, value Endpoint, usage string, opts ...EndpointOption) *Endpoint {
+	p := new(Endpoint)
+	f.HostPortVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// HostPort defines a host:port flag with specified name, default value, and usage string.
+// The return value is the address of an Endpoint variable that stores the value of the flag.
+func HostPort(name string, value Endpoint, usage string, opts ...EndpointOption) *Endpoint {
+	return CommandLine.HostPortP(name, "", value, usage, opts...)
+}
+
+// HostPortP is like HostPort, but accepts a shorthand letter that can be used after a single dash.
+func HostPortP(name, shorthand string, value Endpoint, usage string, opts ...EndpointOption) *Endpoint {
+	return CommandLine.HostPortP(name, shorthand, value, usage, opts...)
+}
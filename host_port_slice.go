@@ -0,0 +1,181 @@
+package pflag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// -- hostPortSlice Value
+type hostPortSliceValue struct {
+	value   *[]Endpoint
+	opts    endpointOptions
+	changed bool
+}
+
+func newHostPortSliceValue(val []Endpoint, p *[]Endpoint, opts []EndpointOption) *hostPortSliceValue {
+	v := &hostPortSliceValue{value: p, opts: newEndpointOptions(opts)}
+	*v.value = val
+	return v
+}
+
+// Set converts, and assigns, the comma-separated argument string representation as the []Endpoint value of this flag.
+// Values containing a comma can be quoted as in CSV.
+// If Set is called on a flag that already has a []Endpoint assigned, the newly converted values will be appended.
+func (s *hostPortSliceValue) Set(val string) error {
+	strSlice, err := readAsCSV(val)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	out := make([]Endpoint, 0, len(strSlice))
+	for _, str := range strSlice {
+		v, err := s.fromString(str)
+		if err != nil {
+			return err
+		}
+		out = append(out, v)
+	}
+
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+
+	s.changed = true
+
+	return nil
+}
+
+// Type returns a string that uniquely represents this flag's type.
+func (s *hostPortSliceValue) Type() string {
+	return "hostPortSlice"
+}
+
+// String defines a "native" format for this Endpoint slice flag value.
+func (s *hostPortSliceValue) String() string {
+	out, _ := writeAsCSV(s.GetSlice())
+	return "[" + out + "]"
+}
+
+func (s *hostPortSliceValue) fromString(val string) (Endpoint, error) {
+	if strings.TrimSpace(val) == "" {
+		return Endpoint{}, fmt.Errorf("empty string being converted to host:port")
+	}
+	return parseHostPort(val, s.opts)
+}
+
+func (s *hostPortSliceValue) toString(val Endpoint) string {
+	return val.String()
+}
+
+func (s *hostPortSliceValue) Append(val string) error {
+	v, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, v)
+	return nil
+}
+
+func (s *hostPortSliceValue) Replace(val []string) error {
+	out := make([]Endpoint, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *hostPortSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+func hostPortSliceConv(val string) (interface{}, error) {
+	val = strings.TrimSuffix(strings.TrimPrefix(val, "["), "]")
+	// Empty string would cause a slice with one (empty) entry
+	if len(val) == 0 {
+		return []Endpoint{}, nil
+	}
+	ss, err := readAsCSV(val)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Endpoint, len(ss))
+	for i, sval := range ss {
+		v, err := parseHostPort(sval, endpointOptions{})
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// GetHostPortSlice returns the []Endpoint value of a flag with the given name
+func (f *FlagSet) GetHostPortSlice(name string) ([]Endpoint, error) {
+	val, err := f.getFlagType(name, "hostPortSlice", hostPortSliceConv)
+	if err != nil {
+		return []Endpoint{}, err
+	}
+	return val.([]Endpoint), nil
+}
+
+// HostPortSliceVar defines a hostPortSlice flag with specified name, default value, and usage string.
+// The argument p points to a []Endpoint variable in which to store the value of the flag.
+// The options validate and complete the values.
+func (f *FlagSet) HostPortSliceVar(p *[]Endpoint, name string, value []Endpoint, usage string, opts ...EndpointOption) {
+	f.VarP(newHostPortSliceValue(value, p, opts), name, "", usage)
+}
+
+// HostPortSliceVarP is like HostPortSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) HostPortSliceVarP(p *[]Endpoint, name, shorthand string, value []Endpoint, usage string, opts ...EndpointOption) {
+	f.VarP(newHostPortSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// HostPortSliceVar defines a hostPortSlice flag with specified name, default value, and usage string.
+// The argument p points to a []Endpoint variable in which to store the value of the flag.
+// The options validate and complete the values.
+func HostPortSliceVar(p *[]Endpoint, name string, value []Endpoint, usage string, opts ...EndpointOption) {
+	CommandLine.VarP(newHostPortSliceValue(value, p, opts), name, "", usage)
+}
+
+// HostPortSliceVarP is like HostPortSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func HostPortSliceVarP(p *[]Endpoint, name, shorthand string, value []Endpoint, usage string, opts ...EndpointOption) {
+	CommandLine.VarP(newHostPortSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// HostPortSlice defines a hostPortSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []Endpoint variable that stores the value of that flag.
+func (f *FlagSet) HostPortSlice(name string, value []Endpoint, usage string, opts ...EndpointOption) *[]Endpoint {
+	p := []Endpoint{}
+	f.HostPortSliceVarP(&p, name, "", value, usage, opts...)
+	return &p
+}
+
+// HostPortSliceP is like HostPortSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) HostPortSliceP(name, shorthand string, value []Endpoint, usage string, opts ...EndpointOption) *[]Endpoint {
+	p := []Endpoint{}
+	f.HostPortSliceVarP(&p, name, shorthand, value, usage, opts...)
+	return &p
+}
+
+// HostPortSlice defines a hostPortSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []Endpoint variable that stores the value of the flag.
+func HostPortSlice(name string, value []Endpoint, usage string, opts ...EndpointOption) *[]Endpoint {
+	return CommandLine.HostPortSliceP(name, "", value, usage, opts...)
+}
+
+// HostPortSliceP is like HostPortSlice, but accepts a shorthand letter that can be used after a single dash.
+func HostPortSliceP(name, shorthand string, value []Endpoint, usage string, opts ...EndpointOption) *[]Endpoint {
+	return CommandLine.HostPortSliceP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,149 @@
+package pflag
+
+import (
+	"io"
+
+	"github.com/rsb/failure"
+)
+
+// -- inputFile Value
+type inputFileValue struct {
+	value *io.ReadCloser
+	path  string
+	file  io.ReadCloser
+	opts  openOptions
+}
+
+func newInputFileValue(val string, p *io.ReadCloser, opts openOptions) *inputFileValue {
+	v := &inputFileValue{value: p, opts: opts}
+	v.use(val, v.lazy(val))
+	return v
+}
+
+func (v *inputFileValue) lazy(path string) io.ReadCloser {
+	if path == "" {
+		return nil
+	}
+	return &lazyFile{open: func() (io.Closer, error) {
+		return openInput(path)
+	}}
+}
+
+// use makes file the value of the flag, closing the file of the previous
+// value.
+func (v *inputFileValue) use(path string, file io.ReadCloser) {
+	_ = v.Close()
+	v.path = path
+	v.file = file
+	*v.value = file
+}
+
+// Set opens the file at s, or stdin for "-". With OpenLazily the file is
+// opened when it is first read. An empty string leaves the flag without a
+// file.
+func (v *inputFileValue) Set(s string) error {
+	if s == "" || v.opts.lazy {
+		v.use(s, v.lazy(s))
+		return nil
+	}
+	file, err := openInput(s)
+	if err != nil {
+		return err
+	}
+	v.use(s, file)
+	return nil
+}
+
+func (v *inputFileValue) Type() string {
+	return "inputFile"
+}
+
+func (v *inputFileValue) String() string { return v.path }
+
+// saveState returns a func that makes path the value again. Set closed the
+// file of the path, so it is opened again lazily.
+func (v *inputFileValue) saveState() func() {
+	path := v.path
+	return func() { v.use(path, v.lazy(path)) }
+}
+
+// Close closes the file of the flag, if it was opened.
+func (v *inputFileValue) Close() error {
+	if v.file == nil {
+		return nil
+	}
+	file := v.file
+	v.file = nil
+	return file.Close()
+}
+
+// GetInputFile returns the io.ReadCloser value of a flag with the given name
+func (f *FlagSet) GetInputFile(name string) (io.ReadCloser, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return nil, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	v, ok := flag.Value.(*inputFileValue)
+	if !ok {
+		return nil, failure.InvalidState("trying to get inputFile value of flag of type %s", flag.Value.Type())
+	}
+	return *v.value, nil
+}
+
+func (f *FlagSet) varInputFile(p *io.ReadCloser, name, shorthand string, value string, usage string, opts []OpenOption) {
+	f.VarP(newInputFileValue(value, p, newOpenOptions(opts)), name, shorthand, usage)
+	_ = f.MarkFilename(name)
+}
+
+// InputFileVar defines an input file flag with specified name, default value, and usage string.
+// The argument p points to an io.ReadCloser variable in which to store the opened file, "-" is
+// stdin. Files are opened by Parse, the default value is opened when it is first read. Opened
+// files are closed by Close.
+func (f *FlagSet) InputFileVar(p *io.ReadCloser, name string, value string, usage string, opts ...OpenOption) {
+	f.varInputFile(p, name, "", value, usage, opts)
+}
+
+// InputFileVarP is like InputFileVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) InputFileVarP(p *io.ReadCloser, name, shorthand string, value string, usage string, opts ...OpenOption) {
+	f.varInputFile(p, name, shorthand, value, usage, opts)
+}
+
+// InputFileVar defines an input file flag with specified name, default value, and usage string.
+// The argument p points to an io.ReadCloser variable in which to store the opened file, "-" is
+// stdin. Files are opened by Parse, the default value is opened when it is first read. Opened
+// files are closed by Close.
+func InputFileVar(p *io.ReadCloser, name string, value string, usage string, opts ...OpenOption) {
+	CommandLine.varInputFile(p, name, "", value, usage, opts)
+}
+
+// InputFileVarP is like InputFileVar, but accepts a shorthand letter that can be used after a single dash.
+func InputFileVarP(p *io.ReadCloser, name, shorthand string, value string, usage string, opts ...OpenOption) {
+	CommandLine.varInputFile(p, name, shorthand, value, usage, opts)
+}
+
+// InputFile defines an input file flag with specified name, default value, and usage string.
+// The return value is the address of an io.ReadCloser variable that stores the opened file.
+func (f *FlagSet) InputFile(name string, value string, usage string, opts ...OpenOption) *io.ReadCloser {
+	p := new(io.ReadCloser)
+	f.InputFileVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// InputFileP is like InputFile, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) InputFileP(name, shorthand string, value string, usage string, opts ...OpenOption) *io.ReadCloser {
+	p := new(io.ReadCloser)
+	f.InputFileVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// InputFile defines an input file flag with specified name, default value, and usage string.
+// The return value is the address of an io.ReadCloser variable that stores the opened file.
+func InputFile(name string, value string, usage string, opts ...OpenOption) *io.ReadCloser {
+	return CommandLine.InputFileP(name, "", value, usage, opts...)
+}
+
+// InputFileP is like InputFile, but accepts a shorthand letter that can be used after a single dash.
+func InputFileP(name, shorthand string, value string, usage string, opts ...OpenOption) *io.ReadCloser {
+	return CommandLine.InputFileP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,93 @@
+package pflag
+
+// -- intRange Value
+type intRangeValue struct {
+	value  *IntInterval
+	bounds IntInterval
+}
+
+func newIntRangeValue(val IntInterval, p *IntInterval, bounds []IntInterval) *intRangeValue {
+	*p = val
+	return &intRangeValue{value: p, bounds: intRangeBounds(bounds)}
+}
+
+// Set parses s as min-max, min..max or a single value.
+func (r *intRangeValue) Set(s string) error {
+	v, err := parseRange(s, r.bounds)
+	if err != nil {
+		return err
+	}
+	*r.value = v
+	return nil
+}
+
+func (r *intRangeValue) Type() string {
+	return "intRange"
+}
+
+func (r *intRangeValue) String() string { return r.value.String() }
+
+func intRangeConv(sval string) (interface{}, error) {
+	return parseRange(sval, allInts)
+}
+
+// GetIntRange return the IntInterval value of a flag with the given name
+func (f *FlagSet) GetIntRange(name string) (IntInterval, error) {
+	val, err := f.getFlagType(name, "intRange", intRangeConv)
+	if err != nil {
+		return IntInterval{}, err
+	}
+	return val.(IntInterval), nil
+}
+
+// IntRangeVar defines an IntRange flag with specified name, default value, and usage string.
+// The argument p points to an IntInterval variable in which to store the value of the flag.
+// Values are given as min-max, min..max or a single value. When bounds is given the range
+// must lie within it.
+func (f *FlagSet) IntRangeVar(p *IntInterval, name string, value IntInterval, usage string, bounds ...IntInterval) {
+	f.VarP(newIntRangeValue(value, p, bounds), name, "", usage)
+}
+
+// IntRangeVarP is like IntRangeVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) IntRangeVarP(p *IntInterval, name, shorthand string, value IntInterval, usage string, bounds ...IntInterval) {
+	f.VarP(newIntRangeValue(value, p, bounds), name, shorthand, usage)
+}
+
+// IntRangeVar defines an IntRange flag with specified name, default value, and usage string.
+// The argument p points to an IntInterval variable in which to store the value of the flag.
+// Values are given as min-max, min..max or a single value. When bounds is given the range
+// must lie within it.
+func IntRangeVar(p *IntInterval, name string, value IntInterval, usage string, bounds ...IntInterval) {
+	CommandLine.VarP(newIntRangeValue(value, p, bounds), name, "", usage)
+}
+
+// IntRangeVarP is like IntRangeVar, but accepts a shorthand letter that can be used after a single dash.
+func IntRangeVarP(p *IntInterval, name, shorthand string, value IntInterval, usage string, bounds ...IntInterval) {
+	CommandLine.VarP(newIntRangeValue(value, p, bounds), name, shorthand, usage)
+}
+
+// IntRange defines an IntRange flag with specified name, default value, and usage string.
+// The return value is the address of an IntInterval variable that stores the value of the flag.
+func (f *FlagSet) IntRange(name string, value IntInterval, usage string, bounds ...IntInterval) *IntInterval {
+	p := new(IntInterval)
+	f.IntRangeVarP(p, name, "", value, usage, bounds...)
+	return p
+}
+
+// IntRangeP is like IntRange, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) IntRangeP(name, shorthand string, value IntInterval, usage string, bounds ...IntInterval) *IntInterval {
+	p := new(IntInterval)
+	f.IntRangeVarP(p, name, shorthand, value, usage, bounds...)
+	return p
+}
+
+// IntRange defines an IntRange flag with specified name, default value, and usage string.
+// The return value is the address of an IntInterval variable that stores the value of the flag.
+func IntRange(name string, value IntInterval, usage string, bounds ...IntInterval) *IntInterval {
+	return CommandLine.IntRangeP(name, "", value, usage, bounds...)
+}
+
+// IntRangeP is like IntRange, but accepts a shorthand letter that can be used after a single dash.
+func IntRangeP(name, shorthand string, value IntInterval, usage string, bounds ...IntInterval) *IntInterval {
+	return CommandLine.IntRangeP(name, shorthand, value, usage, bounds...)
+}
@@ -0,0 +1,160 @@
+package pflag
+
+import (
+	"strings"
+)
+
+// -- intRangeSlice Value
+type intRangeSliceValue struct {
+	value   *[]IntInterval
+	bounds  IntInterval
+	changed bool
+}
+
+func newIntRangeSliceValue(val []IntInterval, p *[]IntInterval, bounds []IntInterval) *intRangeSliceValue {
+	rsv := &intRangeSliceValue{value: p, bounds: intRangeBounds(bounds)}
+	*rsv.value = val
+	return rsv
+}
+
+func (s *intRangeSliceValue) Set(val string) error {
+	ss := strings.Split(val, ",")
+	out := make([]IntInterval, len(ss))
+	for i, d := range ss {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+	s.changed = true
+	return nil
+}
+
+func (s *intRangeSliceValue) Type() string {
+	return "intRangeSlice"
+}
+
+func (s *intRangeSliceValue) String() string {
+	return "[" + strings.Join(s.GetSlice(), ",") + "]"
+}
+
+func (s *intRangeSliceValue) fromString(val string) (IntInterval, error) {
+	return parseRange(val, s.bounds)
+}
+
+func (s *intRangeSliceValue) toString(val IntInterval) string {
+	return val.String()
+}
+
+func (s *intRangeSliceValue) Append(val string) error {
+	r, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, r)
+	return nil
+}
+
+func (s *intRangeSliceValue) Replace(val []string) error {
+	out := make([]IntInterval, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *intRangeSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+func intRangeSliceConv(val string) (interface{}, error) {
+	val = strings.Trim(val, "[]")
+	// Empty string would cause a slice with one (empty) entry
+	if len(val) == 0 {
+		return []IntInterval{}, nil
+	}
+	ss := strings.Split(val, ",")
+	out := make([]IntInterval, len(ss))
+	for i, d := range ss {
+		var err error
+		out[i], err = parseRange(d, allInts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// GetIntRangeSlice returns the []IntInterval value of a intRangeSlice flag with the given name
+func (f *FlagSet) GetIntRangeSlice(name string) ([]IntInterval, error) {
+	val, err := f.getFlagType(name, "intRangeSlice", intRangeSliceConv)
+	if err != nil {
+		return []IntInterval{}, err
+	}
+	return val.([]IntInterval), nil
+}
+
+// IntRangeSliceVar defines a intRangeSlice flag with specified name, default value, and usage string.
+// The argument p points to a []IntInterval variable in which to store the value of the flag.
+// Each range is given as min-max, min..max or a single value, within bounds when it is given.
+func (f *FlagSet) IntRangeSliceVar(p *[]IntInterval, name string, value []IntInterval, usage string, bounds ...IntInterval) {
+	f.VarP(newIntRangeSliceValue(value, p, bounds), name, "", usage)
+}
+
+// IntRangeSliceVarP is like IntRangeSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) IntRangeSliceVarP(p *[]IntInterval, name, shorthand string, value []IntInterval, usage string, bounds ...IntInterval) {
+	f.VarP(newIntRangeSliceValue(value, p, bounds), name, shorthand, usage)
+}
+
+// IntRangeSliceVar defines a intRangeSlice flag with specified name, default value, and usage string.
+// The argument p points to a []IntInterval variable in which to store the value of the flag.
+// Each range is given as min-max, min..max or a single value, within bounds when it is given.
+func IntRangeSliceVar(p *[]IntInterval, name string, value []IntInterval, usage string, bounds ...IntInterval) {
+	CommandLine.VarP(newIntRangeSliceValue(value, p, bounds), name, "", usage)
+}
+
+// IntRangeSliceVarP is like IntRangeSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func IntRangeSliceVarP(p *[]IntInterval, name, shorthand string, value []IntInterval, usage string, bounds ...IntInterval) {
+	CommandLine.VarP(newIntRangeSliceValue(value, p, bounds), name, shorthand, usage)
+}
+
+// IntRangeSlice defines a intRangeSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []IntInterval variable that stores the value of the flag.
+func (f *FlagSet) IntRangeSlice(name string, value []IntInterval, usage string, bounds ...IntInterval) *[]IntInterval {
+	p := []IntInterval{}
+	f.IntRangeSliceVarP(&p, name, "", value, usage, bounds...)
+	return &p
+}
+
+// IntRangeSliceP is like IntRangeSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) IntRangeSliceP(name, shorthand string, value []IntInterval, usage string, bounds ...IntInterval) *[]IntInterval {
+	p := []IntInterval{}
+	f.IntRangeSliceVarP(&p, name, shorthand, value, usage, bounds...)
+	return &p
+}
+
+// IntRangeSlice defines a intRangeSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []IntInterval variable that stores the value of the flag.
+func IntRangeSlice(name string, value []IntInterval, usage string, bounds ...IntInterval) *[]IntInterval {
+	return CommandLine.IntRangeSliceP(name, "", value, usage, bounds...)
+}
+
+// IntRangeSliceP is like IntRangeSlice, but accepts a shorthand letter that can be used after a single dash.
+func IntRangeSliceP(name, shorthand string, value []IntInterval, usage string, bounds ...IntInterval) *[]IntInterval {
+	return CommandLine.IntRangeSliceP(name, shorthand, value, usage, bounds...)
+}
@@ -0,0 +1,127 @@
+package pflag
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rsb/failure"
+)
+
+// parseLocation parses the name of a location in the IANA Time Zone
+// database, such as "Europe/Berlin", "UTC" or "Local", or a fixed offset
+// such as "+02:00" or "-0530".
+func parseLocation(s string) (*time.Location, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if s[0] == '+' || s[0] == '-' {
+		for _, layout := range []string{"-07:00", "-0700", "-07"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				_, offset := t.Zone()
+				return time.FixedZone(s, offset), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid zone offset: %q", s)
+	}
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone: %q", s)
+	}
+	return loc, nil
+}
+
+// -- time.Location Value
+type locationValue struct {
+	value **time.Location
+}
+
+func newLocationValue(val *time.Location, p **time.Location) *locationValue {
+	*p = val
+	return &locationValue{value: p}
+}
+
+func (l *locationValue) Set(s string) error {
+	loc, err := parseLocation(s)
+	if err != nil {
+		return err
+	}
+	*l.value = loc
+	return nil
+}
+
+func (l *locationValue) Type() string {
+	return "location"
+}
+
+func (l *locationValue) String() string {
+	if *l.value == nil {
+		return ""
+	}
+	return (*l.value).String()
+}
+
+// GetLocation return the *time.Location value of a flag with the given name
+func (f *FlagSet) GetLocation(name string) (*time.Location, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return nil, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	v, ok := flag.Value.(*locationValue)
+	if !ok {
+		return nil, failure.InvalidState("trying to get location value of flag of type %s", flag.Value.Type())
+	}
+	return *v.value, nil
+}
+
+// LocationVar defines a time zone flag, such as --tz, with specified name, default value,
+// and usage string. The argument p points to a *time.Location variable in which to store
+// the value of the flag. Values are IANA time zone names or fixed offsets such as +02:00.
+func (f *FlagSet) LocationVar(p **time.Location, name string, value *time.Location, usage string) {
+	f.VarP(newLocationValue(value, p), name, "", usage)
+}
+
+// LocationVarP is like LocationVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) LocationVarP(p **time.Location, name, shorthand string, value *time.Location, usage string) {
+	f.VarP(newLocationValue(value, p), name, shorthand, usage)
+}
+
+// LocationVar defines a time zone flag, such as --tz, with specified name, default value,
+// and usage string. The argument p points to a *time.Location variable in which to store
+// the value of the flag. Values are IANA time zone names or fixed offsets such as +02:00.
+func LocationVar(p **time.Location, name string, value *time.Location, usage string) {
+	CommandLine.VarP(newLocationValue(value, p), name, "", usage)
+}
+
+// LocationVarP is like LocationVar, but accepts a shorthand letter that can be used after a single dash.
+func LocationVarP(p **time.Location, name, shorthand string, value *time.Location, usage string) {
+	CommandLine.VarP(newLocationValue(value, p), name, shorthand, usage)
+}
+
+// Location defines a time zone flag with specified name, default value, and usage string.
+// The return value is the address of a *time.Location variable that stores the value of the flag.
+func (f *FlagSet) Location(name string, value *time.Location, usage string) **time.Location {
+	p := new(*time.Location)
+	f.LocationVarP(p, name, "", value, usage)
+	return p
+}
+
+// LocationP is like Location, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) LocationP(name, shorthand string, value *time.Location, usage string) **time.Location {
+	p := new(*time.Location)
+	f.LocationVarP(p, name, shorthand, value, usage)
+	return p
+}
+
+// Location defines a time zone flag with specified name, default value, and usage string.
+// The return value is the address of a *time.Location variable that stores the value of the flag.
+func Location(name string, value *time.Location, usage string) **time.Location {
+	return CommandLine.LocationP(name, "", value, usage)
+}
+
+// LocationP is like Location, but accepts a shorthand letter that can be used after a single dash.
+func LocationP(name, shorthand string, value *time.Location, usage string) **time.Location {
+	return CommandLine.LocationP(name, shorthand, value, usage)
+}
@@ -0,0 +1,166 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ManSection is an additional section of a man page. The body is plain text
+// in which blank lines separate paragraphs.
+type ManSection struct {
+	Title string
+	Body  string
+}
+
+// ManPageOptions configures GenManPage.
+type ManPageOptions struct {
+	// Title of the page, the upper cased program name when empty
+	Title string
+	// Section of the manual, "1" when empty
+	Section string
+	// Short is the one line description shown in NAME
+	Short string
+	// Usage follows the program name in SYNOPSIS, "[flags]" when empty
+	Usage string
+	// Date shown in the footer. When nil the time in SOURCE_DATE_EPOCH is
+	// used if set, otherwise no date is shown, so the output is reproducible.
+	Date *time.Time
+	// Source and Manual are shown in the footer and the header
+	Source string
+	Manual string
+	// Sections are appended after ENVIRONMENT
+	Sections []ManSection
+}
+
+// GenManPage writes a roff man page describing the flags in the FlagSet to w.
+// The OPTIONS section holds the same information as FlagUsages, and the
+// ENVIRONMENT section lists the variables bound with BindEnv. Hidden flags
+// are left out of both, unless they are hidden because they are deprecated.
+func (f *FlagSet) GenManPage(w io.Writer, opts ManPageOptions) error {
+	program := f.completionProgram()
+	if opts.Title == "" {
+		opts.Title = strings.ToUpper(program)
+	}
+	if opts.Section == "" {
+		opts.Section = "1"
+	}
+	if opts.Usage == "" {
+		opts.Usage = "[flags]"
+	}
+
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, ".TH %s %s %s %s %s\n",
+		roffQuote(opts.Title), roffQuote(opts.Section), roffQuote(manPageDate(opts.Date)),
+		roffQuote(opts.Source), roffQuote(opts.Manual))
+	buf.WriteString(".nh\n.ad l\n")
+
+	buf.WriteString(".SH NAME\n")
+	buf.WriteString(roffEscape(program))
+	if opts.Short != "" {
+		buf.WriteString(" \\- " + roffEscape(opts.Short))
+	}
+	buf.WriteString("\n")
+
+	buf.WriteString(".SH SYNOPSIS\n")
+	_, _ = fmt.Fprintf(buf, "\\fB%s\\fR %s\n", roffEscape(program), roffEscape(opts.Usage))
+
+	var env []*Flag
+	options := new(bytes.Buffer)
+	f.VisitAll(func(flag *Flag) {
+		if flag.Hidden && flag.Deprecated == "" {
+			return
+		}
+		if flag.EnvVar != "" {
+			env = append(env, flag)
+		}
+
+		u := f.newFlagUsage(flag)
+		options.WriteString(".TP\n")
+		if u.Short != "" {
+			_, _ = fmt.Fprintf(options, "\\fB\\-%s\\fR, ", roffEscape(u.Short))
+		}
+		_, _ = fmt.Fprintf(options, "\\fB\\-\\-%s\\fR", roffEscape(u.Name))
+		if u.Varname != "" {
+			_, _ = fmt.Fprintf(options, " \\fI%s\\fR", roffEscape(u.Varname))
+		}
+		options.WriteString(roffEscape(u.NoOptDefVal) + "\n")
+
+		text := u.Usage
+		if u.Default != "" {
+			text += " " + f.message(MsgDefault, u.Default)
+		}
+		if u.Deprecated != "" {
+			text += " " + f.message(MsgDeprecated, u.Deprecated)
+		}
+		if flag.ShortDeprecated != "" {
+			text += " " + f.message(MsgShorthandDeprecated, flag.Short, flag.ShortDeprecated)
+		}
+		options.WriteString(roffText(text))
+	})
+	if options.Len() > 0 {
+		buf.WriteString(".SH OPTIONS\n")
+		buf.Write(options.Bytes())
+	}
+
+	if len(env) > 0 {
+		buf.WriteString(".SH ENVIRONMENT\n")
+		for _, flag := range env {
+			_, _ = fmt.Fprintf(buf, ".TP\n\\fB%s\\fR\nSets \\fB\\-\\-%s\\fR unless it is given on the command line.\n",
+				roffEscape(flag.EnvVar), roffEscape(flag.Name))
+		}
+	}
+
+	for _, section := range opts.Sections {
+		_, _ = fmt.Fprintf(buf, ".SH %s\n", roffQuote(strings.ToUpper(section.Title)))
+		for i, paragraph := range strings.Split(strings.TrimSpace(section.Body), "\n\n") {
+			if i > 0 {
+				buf.WriteString(".PP\n")
+			}
+			buf.WriteString(roffText(paragraph))
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// manPageDate formats the date shown in the footer of a man page.
+func manPageDate(date *time.Time) string {
+	if date != nil {
+		return date.UTC().Format("2006-01-02")
+	}
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC().Format("2006-01-02")
+	}
+	return ""
+}
+
+// roffEscape escapes backslashes and dashes for roff.
+func roffEscape(s string) string {
+	return strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+}
+
+// roffQuote escapes s and wraps it in double quotes for use as a macro
+// argument.
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(roffEscape(s), `"`, `\(dq`) + `"`
+}
+
+// roffText escapes a block of text and protects lines that would otherwise
+// be read as requests. The result ends with a newline.
+func roffText(s string) string {
+	lines := strings.Split(roffEscape(s), "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			line = `\&` + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,44 @@
+package pflag_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenManPage(t *testing.T) {
+	f := setUpCompletionFlagSet()
+	f.Int("retries", 3, "number of retries\n.also when the server is down")
+	require.NoError(t, f.BindEnv("config", "MY_APP_CONFIG"))
+	require.NoError(t, f.BindEnv("secret", "MY_APP_SECRET"))
+
+	date := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	require.NoError(t, f.GenManPage(&buf, pflag.ManPageOptions{
+		Short:  "manage the app",
+		Date:   &date,
+		Source: "my-app 1.2.3",
+		Manual: "User Commands",
+		Usage:  "[flags] <command>",
+		Sections: []pflag.ManSection{
+			{Title: "Exit status", Body: "Zero on success.\n\nNon-zero when a \\ command fails."},
+		},
+	}))
+	assertGolden(t, "manpage/my-app.1.golden", buf.Bytes())
+}
+
+func TestGenManPageSourceDateEpoch(t *testing.T) {
+	f := pflag.NewFlagSet("app", pflag.ContinueOnError)
+
+	var buf bytes.Buffer
+	require.NoError(t, f.GenManPage(&buf, pflag.ManPageOptions{}))
+	require.Equal(t, ".TH \"APP\" \"1\" \"\" \"\" \"\"\n.nh\n.ad l\n.SH NAME\napp\n.SH SYNOPSIS\n\\fBapp\\fR [flags]\n", buf.String())
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	buf.Reset()
+	require.NoError(t, f.GenManPage(&buf, pflag.ManPageOptions{}))
+	require.Contains(t, buf.String(), ".TH \"APP\" \"1\" \"2023\\-11\\-14\" \"\" \"\"\n")
+}
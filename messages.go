@@ -0,0 +1,150 @@
+package pflag
+
+import (
+	"fmt"
+
+	"github.com/rsb/failure"
+)
+
+// MessageID identifies a message written or returned by a FlagSet. The
+// comment of every ID lists the arguments its message is formatted with.
+type MessageID string
+
+const (
+	// MsgBadFlagSyntax is returned for a malformed flag: the argument
+	MsgBadFlagSyntax MessageID = "bad_flag_syntax"
+	// MsgUnknownFlag is returned for an undefined flag: the flag name
+	MsgUnknownFlag MessageID = "unknown_flag"
+	// MsgUnknownShorthand is returned for an undefined shorthand: the
+	// shorthand and the shorthands it was given with
+	MsgUnknownShorthand MessageID = "unknown_shorthand"
+	// MsgFlagNeedsArgument is returned when a flag lacks its value: the
+	// argument
+	MsgFlagNeedsArgument MessageID = "flag_needs_argument"
+	// MsgShorthandNeedsArgument is returned when a shorthand lacks its value:
+	// the shorthand and the shorthands it was given with
+	MsgShorthandNeedsArgument MessageID = "shorthand_needs_argument"
+	// MsgInvalidArgument is returned when a value is rejected: the value, the
+	// flag and the error of the Value
+	MsgInvalidArgument MessageID = "invalid_argument"
+	// MsgInvalidSensitiveArgument is returned instead of MsgInvalidArgument
+	// when the value of a sensitive flag is rejected, so neither the value
+	// nor the error of the Value is disclosed: Redacted and the flag
+	MsgInvalidSensitiveArgument MessageID = "invalid_sensitive_argument"
+	// MsgFlagDeprecated is written when a deprecated flag is used: the flag
+	// name and the deprecation message
+	MsgFlagDeprecated MessageID = "flag_deprecated"
+	// MsgShorthandDeprecated is written when a deprecated shorthand is used:
+	// the shorthand and the deprecation message
+	MsgShorthandDeprecated MessageID = "shorthand_deprecated"
+	// MsgFeatureGateDeprecated is written when a deprecated feature gate is
+	// enabled: the gate and the flag
+	MsgFeatureGateDeprecated MessageID = "feature_gate_deprecated"
+	// MsgFlagRemoved is returned when a flag is used after its removal: the
+	// flag name, the version it was removed in and the deprecation message
+	MsgFlagRemoved MessageID = "flag_removed"
+	// MsgUsage is the first line of the default usage message: the name of
+	// the FlagSet
+	MsgUsage MessageID = "usage"
+	// MsgDefault follows the usage of a flag in help output: the default
+	MsgDefault MessageID = "default"
+	// MsgDeprecated follows the usage of a flag in help output: the
+	// deprecation message
+	MsgDeprecated MessageID = "deprecated"
+	// MsgOtherFlags is the title of the section listing the flags without a
+	// group
+	MsgOtherFlags MessageID = "other_flags"
+)
+
+// MessageCatalog provides the text of the messages of a FlagSet and the
+// usage of its flags, so applications can supply translations.
+type MessageCatalog interface {
+	// Message returns the text of the message id formatted with args.
+	Message(id MessageID, args ...interface{}) string
+	// Usage returns the usage text stored under key, see SetUsageKey, or
+	// false when there is none.
+	Usage(key string) (string, bool)
+}
+
+// Catalog is a MessageCatalog backed by maps. Messages are fmt format
+// strings, which can use explicit argument indexes such as %[2]s when a
+// language needs the arguments in a different order. Messages missing from
+// the catalog are taken from English.
+type Catalog struct {
+	Messages map[MessageID]string
+	Usages   map[string]string
+}
+
+// Message returns the text of the message id formatted with args.
+func (c Catalog) Message(id MessageID, args ...interface{}) string {
+	format, ok := c.Messages[id]
+	if !ok {
+		format = englishMessages[id]
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Usage returns the usage text stored under key.
+func (c Catalog) Usage(key string) (string, bool) {
+	usage, ok := c.Usages[key]
+	return usage, ok
+}
+
+var englishMessages = map[MessageID]string{
+	MsgBadFlagSyntax:            "bad flag syntax: %s",
+	MsgUnknownFlag:              "unknown flag: --%s",
+	MsgUnknownShorthand:         "unknown shorthand flag: %q in -%s",
+	MsgFlagNeedsArgument:        "flag needs an argument: %s",
+	MsgShorthandNeedsArgument:   "flag needs an argument: %q in -%s",
+	MsgInvalidArgument:          "invalid argument %q for %q flag: %v",
+	MsgInvalidSensitiveArgument: "invalid argument %q for %q flag: invalid value",
+	MsgFlagDeprecated:           "Flag --%s has been deprecated, %s",
+	MsgShorthandDeprecated:      "Flag shorthand -%s has been deprecated, %s",
+	MsgFeatureGateDeprecated:    "Feature gate %s of --%s has been deprecated and will be removed",
+	MsgFlagRemoved:              "flag --%s has been removed in %s, %s",
+	MsgUsage:                    "Usage of %s:",
+	MsgDefault:                  "(default %s)",
+	MsgDeprecated:               "(DEPRECATED: %s)",
+	MsgOtherFlags:               OtherFlagsGroup,
+}
+
+// English is the catalog used by a FlagSet unless SetMessageCatalog is
+// called.
+var English MessageCatalog = Catalog{}
+
+// SetMessageCatalog sets the catalog the messages of the FlagSet and the
+// usage of flags with a usage key are taken from. A nil catalog restores
+// English.
+func (f *FlagSet) SetMessageCatalog(c MessageCatalog) {
+	f.catalog = c
+}
+
+// SetUsageKey sets the key under which the usage of the named flag is looked
+// up in the message catalog. The usage given when the flag was defined is
+// shown when the catalog has no entry for the key.
+func (f *FlagSet) SetUsageKey(name, key string) error {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return failure.NotFound("flag (%s), does not exist", name)
+	}
+
+	if key == "" {
+		return failure.InvalidParam("usage key is empty, usage key for (%s) must be set", name)
+	}
+
+	flag.UsageKey = key
+	return nil
+}
+
+// messageCatalog returns the catalog of the FlagSet.
+func (f *FlagSet) messageCatalog() MessageCatalog {
+	if f.catalog == nil {
+		return English
+	}
+	return f.catalog
+}
+
+// message returns the text of the message id formatted with args.
+func (f *FlagSet) message(id MessageID, args ...interface{}) string {
+	return f.messageCatalog().Message(id, args...)
+}
@@ -0,0 +1,102 @@
+package pflag_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+var german = pflag.Catalog{
+	Messages: map[pflag.MessageID]string{
+		pflag.MsgUnknownFlag:         "unbekannte Option: --%s",
+		pflag.MsgInvalidArgument:     "ungültiger Wert %[1]q für die Option %[2]q: %[3]v",
+		pflag.MsgFlagDeprecated:      "Die Option --%s ist veraltet, %s",
+		pflag.MsgUsage:               "Aufruf von %s:",
+		pflag.MsgDefault:             "(Standard %s)",
+		pflag.MsgDeprecated:          "(VERALTET: %s)",
+		pflag.MsgOtherFlags:          "Weitere Optionen",
+		pflag.MsgShorthandDeprecated: "Die Kurzform -%s ist veraltet, %s",
+	},
+	Usages: map[string]string{
+		"workers": "Anzahl der `Worker`",
+	},
+}
+
+func setUpMessagesFlagSet() (*pflag.FlagSet, *bytes.Buffer) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Int("workers", 4, "number of workers")
+	f.String("name", "app", "name of the app")
+	f.Bool("old", false, "deprecated flag")
+	f.Lookup("old").Deprecated = "use --name"
+	_ = f.SetUsageKey("workers", "workers")
+	_ = f.SetUsageKey("name", "missing")
+	f.SetMessageCatalog(german)
+
+	var buf bytes.Buffer
+	f.SetOutput(&buf)
+	return f, &buf
+}
+
+func TestMessageCatalogErrors(t *testing.T) {
+	f, buf := setUpMessagesFlagSet()
+
+	err := f.Parse([]string{"--unknown"})
+	require.EqualError(t, err, "unbekannte Option: --unknown")
+
+	err = f.Parse([]string{"--workers=many"})
+	require.Contains(t, err.Error(), `ungültiger Wert "many" für die Option "--workers"`)
+
+	// messages missing from the catalog fall back to English
+	err = f.Parse([]string{"--name"})
+	require.EqualError(t, err, "flag needs an argument: --name")
+
+	require.NoError(t, f.Parse([]string{"--old"}))
+	require.Equal(t, "Die Option --old ist veraltet, use --name\n", buf.String())
+}
+
+func TestMessageCatalogUsage(t *testing.T) {
+	f, buf := setUpMessagesFlagSet()
+	require.NoError(t, f.SetGroup("workers", "Leistung"))
+
+	require.ErrorIs(t, f.Parse([]string{"--help"}), pflag.ErrHelp)
+	require.Equal(t, `Aufruf von test:
+Leistung:
+      --workers Worker   Anzahl der Worker (Standard 4)
+
+Weitere Optionen:
+      --name string   name of the app (Standard "app")
+      --old           deprecated flag (VERALTET: use --name)
+`, buf.String())
+}
+
+// commandLineUsage is the package Usage before any test replaces it.
+var commandLineUsage = pflag.Usage
+
+func TestMessageCatalogCommandLineUsage(t *testing.T) {
+	stderr, commandLine := os.Stderr, pflag.CommandLine
+	defer func() { os.Stderr, pflag.CommandLine = stderr, commandLine }()
+
+	out, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	require.NoError(t, err)
+	defer out.Close()
+	os.Stderr = out
+
+	pflag.CommandLine = pflag.NewFlagSet(os.Args[0], pflag.ContinueOnError)
+	pflag.CommandLine.Int("workers", 4, "number of workers")
+	pflag.CommandLine.SetMessageCatalog(german)
+	commandLineUsage()
+
+	got, err := os.ReadFile(out.Name())
+	require.NoError(t, err)
+	require.Equal(t, "Aufruf von "+os.Args[0]+":\n      --workers int   number of workers (Standard 4)\n", string(got))
+}
+
+func TestSetUsageKeyErrors(t *testing.T) {
+	f, _ := setUpMessagesFlagSet()
+	require.Error(t, f.SetUsageKey("missing", "key"))
+	require.Error(t, f.SetUsageKey("name", ""))
+}
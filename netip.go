@@ -0,0 +1,81 @@
+package pflag
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// NetipOption restricts the values accepted by the net/netip flags. Options
+// can be combined with |.
+type NetipOption int
+
+const (
+	// IPv4Only accepts IPv4 addresses only
+	IPv4Only NetipOption = 1 << iota
+	// IPv6Only accepts IPv6 addresses only, IPv4-mapped ones included
+	IPv6Only
+	// MaskedPrefix accepts prefixes without host bits only, such as
+	// 10.0.0.0/8 but not 10.1.2.3/8
+	MaskedPrefix
+)
+
+func combineNetipOptions(opts []NetipOption) NetipOption {
+	var out NetipOption
+	for _, opt := range opts {
+		out |= opt
+	}
+	return out
+}
+
+func checkAddr(addr netip.Addr, opts NetipOption) error {
+	switch {
+	case opts&IPv4Only != 0 && !addr.Is4():
+		return fmt.Errorf("%s is not an IPv4 address", addr)
+	case opts&IPv6Only != 0 && !addr.Is6():
+		return fmt.Errorf("%s is not an IPv6 address", addr)
+	}
+	return nil
+}
+
+func parseAddr(s string, opts NetipOption) (netip.Addr, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return netip.Addr{}, nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("failed to parse IP address: %q", s)
+	}
+	return addr, checkAddr(addr, opts)
+}
+
+func parsePrefix(s string, opts NetipOption) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return netip.Prefix{}, nil
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("failed to parse IP prefix: %q", s)
+	}
+	if err := checkAddr(prefix.Addr(), opts); err != nil {
+		return netip.Prefix{}, err
+	}
+	if opts&MaskedPrefix != 0 && prefix != prefix.Masked() {
+		return netip.Prefix{}, fmt.Errorf("%s has host bits set, use %s", prefix, prefix.Masked())
+	}
+	return prefix, nil
+}
+
+func parseAddrPort(s string, opts NetipOption) (netip.AddrPort, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return netip.AddrPort{}, nil
+	}
+	addrPort, err := netip.ParseAddrPort(s)
+	if err != nil {
+		return netip.AddrPort{}, fmt.Errorf("failed to parse IP address and port: %q", s)
+	}
+	return addrPort, checkAddr(addrPort.Addr(), opts)
+}
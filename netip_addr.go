@@ -0,0 +1,99 @@
+package pflag
+
+import "net/netip"
+
+// -- netip.Addr value
+type addrValue struct {
+	value *netip.Addr
+	opts  NetipOption
+}
+
+func newAddrValue(val netip.Addr, p *netip.Addr, opts []NetipOption) *addrValue {
+	*p = val
+	return &addrValue{value: p, opts: combineNetipOptions(opts)}
+}
+
+// String returns an empty string for the zero netip.Addr.
+func (v *addrValue) String() string {
+	if !v.value.IsValid() {
+		return ""
+	}
+	return v.value.String()
+}
+
+// Set parses s as an IP address. An empty string sets the zero netip.Addr.
+func (v *addrValue) Set(s string) error {
+	val, err := parseAddr(s, v.opts)
+	if err != nil {
+		return err
+	}
+	*v.value = val
+	return nil
+}
+
+func (v *addrValue) Type() string {
+	return "addr"
+}
+
+func addrConv(sval string) (interface{}, error) {
+	return parseAddr(sval, 0)
+}
+
+// GetAddr return the netip.Addr value of a flag with the given name
+func (f *FlagSet) GetAddr(name string) (netip.Addr, error) {
+	val, err := f.getFlagType(name, "addr", addrConv)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return val.(netip.Addr), nil
+}
+
+// AddrVar defines a netip.Addr flag with specified name, default value, and usage string.
+// The argument p points to a netip.Addr variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func (f *FlagSet) AddrVar(p *netip.Addr, name string, value netip.Addr, usage string, opts ...NetipOption) {
+	f.VarP(newAddrValue(value, p, opts), name, "", usage)
+}
+
+// AddrVarP is like AddrVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) AddrVarP(p *netip.Addr, name, shorthand string, value netip.Addr, usage string, opts ...NetipOption) {
+	f.VarP(newAddrValue(value, p, opts), name, shorthand, usage)
+}
+
+// AddrVar defines a netip.Addr flag with specified name, default value, and usage string.
+// The argument p points to a netip.Addr variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func AddrVar(p *netip.Addr, name string, value netip.Addr, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newAddrValue(value, p, opts), name, "", usage)
+}
+
+// AddrVarP is like AddrVar, but accepts a shorthand letter that can be used after a single dash.
+func AddrVarP(p *netip.Addr, name, shorthand string, value netip.Addr, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newAddrValue(value, p, opts), name, shorthand, usage)
+}
+
+// Addr defines a netip.Addr flag with specified name, default value, and usage string.
+// The return value is the address of a netip.Addr variable that stores the value of the flag.
+func (f *FlagSet) Addr(name string, value netip.Addr, usage string, opts ...NetipOption) *netip.Addr {
+	p := new(netip.Addr)
+	f.AddrVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// AddrP is like Addr, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) AddrP(name, shorthand string, value netip.Addr, usage string, opts ...NetipOption) *netip.Addr {
+	p := new(netip.Addr)
+	f.AddrVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// Addr defines a netip.Addr flag with specified name, default value, and usage string.
+// The return value is the address of a netip.Addr variable that stores the value of the flag.
+func Addr(name string, value netip.Addr, usage string, opts ...NetipOption) *netip.Addr {
+	return CommandLine.AddrP(name, "", value, usage, opts...)
+}
+
+// AddrP is like Addr, but accepts a shorthand letter that can be used after a single dash.
+func AddrP(name, shorthand string, value netip.Addr, usage string, opts ...NetipOption) *netip.Addr {
+	return CommandLine.AddrP(name, shorthand, value, usage, opts...)
+}
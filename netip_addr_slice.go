@@ -0,0 +1,179 @@
+package pflag
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+)
+
+// -- addrSlice Value
+type addrSliceValue struct {
+	value   *[]netip.Addr
+	opts    NetipOption
+	changed bool
+}
+
+func newAddrSliceValue(val []netip.Addr, p *[]netip.Addr, opts []NetipOption) *addrSliceValue {
+	v := &addrSliceValue{value: p, opts: combineNetipOptions(opts)}
+	*v.value = val
+	return v
+}
+
+// Set converts, and assigns, the comma-separated argument string representation as the []netip.Addr value of this flag.
+// If Set is called on a flag that already has a []netip.Addr assigned, the newly converted values will be appended.
+func (s *addrSliceValue) Set(val string) error {
+	strSlice, err := readAsCSV(val)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	out := make([]netip.Addr, 0, len(strSlice))
+	for _, str := range strSlice {
+		v, err := s.fromString(str)
+		if err != nil {
+			return err
+		}
+		out = append(out, v)
+	}
+
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+
+	s.changed = true
+
+	return nil
+}
+
+// Type returns a string that uniquely represents this flag's type.
+func (s *addrSliceValue) Type() string {
+	return "addrSlice"
+}
+
+// String defines a "native" format for this netip.Addr slice flag value.
+func (s *addrSliceValue) String() string {
+	out, _ := writeAsCSV(s.GetSlice())
+	return "[" + out + "]"
+}
+
+func (s *addrSliceValue) fromString(val string) (netip.Addr, error) {
+	if strings.TrimSpace(val) == "" {
+		return netip.Addr{}, fmt.Errorf("empty string being converted to an IP address")
+	}
+	return parseAddr(val, s.opts)
+}
+
+func (s *addrSliceValue) toString(val netip.Addr) string {
+	return val.String()
+}
+
+func (s *addrSliceValue) Append(val string) error {
+	v, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, v)
+	return nil
+}
+
+func (s *addrSliceValue) Replace(val []string) error {
+	out := make([]netip.Addr, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *addrSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+func addrSliceConv(val string) (interface{}, error) {
+	// only the outer brackets, IPv6 addresses with a port are bracketed too
+	val = strings.TrimSuffix(strings.TrimPrefix(val, "["), "]")
+	// Empty string would cause a slice with one (empty) entry
+	if len(val) == 0 {
+		return []netip.Addr{}, nil
+	}
+	ss := strings.Split(val, ",")
+	out := make([]netip.Addr, len(ss))
+	for i, sval := range ss {
+		v, err := parseAddr(sval, 0)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// GetAddrSlice returns the []netip.Addr value of a flag with the given name
+func (f *FlagSet) GetAddrSlice(name string) ([]netip.Addr, error) {
+	val, err := f.getFlagType(name, "addrSlice", addrSliceConv)
+	if err != nil {
+		return []netip.Addr{}, err
+	}
+	return val.([]netip.Addr), nil
+}
+
+// AddrSliceVar defines a addrSlice flag with specified name, default value, and usage string.
+// The argument p points to a []netip.Addr variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func (f *FlagSet) AddrSliceVar(p *[]netip.Addr, name string, value []netip.Addr, usage string, opts ...NetipOption) {
+	f.VarP(newAddrSliceValue(value, p, opts), name, "", usage)
+}
+
+// AddrSliceVarP is like AddrSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) AddrSliceVarP(p *[]netip.Addr, name, shorthand string, value []netip.Addr, usage string, opts ...NetipOption) {
+	f.VarP(newAddrSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// AddrSliceVar defines a []netip.Addr flag with specified name, default value, and usage string.
+// The argument p points to a []netip.Addr variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func AddrSliceVar(p *[]netip.Addr, name string, value []netip.Addr, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newAddrSliceValue(value, p, opts), name, "", usage)
+}
+
+// AddrSliceVarP is like AddrSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func AddrSliceVarP(p *[]netip.Addr, name, shorthand string, value []netip.Addr, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newAddrSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// AddrSlice defines a []netip.Addr flag with specified name, default value, and usage string.
+// The return value is the address of a []netip.Addr variable that stores the value of that flag.
+func (f *FlagSet) AddrSlice(name string, value []netip.Addr, usage string, opts ...NetipOption) *[]netip.Addr {
+	p := []netip.Addr{}
+	f.AddrSliceVarP(&p, name, "", value, usage, opts...)
+	return &p
+}
+
+// AddrSliceP is like AddrSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) AddrSliceP(name, shorthand string, value []netip.Addr, usage string, opts ...NetipOption) *[]netip.Addr {
+	p := []netip.Addr{}
+	f.AddrSliceVarP(&p, name, shorthand, value, usage, opts...)
+	return &p
+}
+
+// AddrSlice defines a []netip.Addr flag with specified name, default value, and usage string.
+// The return value is the address of a []netip.Addr variable that stores the value of the flag.
+func AddrSlice(name string, value []netip.Addr, usage string, opts ...NetipOption) *[]netip.Addr {
+	return CommandLine.AddrSliceP(name, "", value, usage, opts...)
+}
+
+// AddrSliceP is like AddrSlice, but accepts a shorthand letter that can be used after a single dash.
+func AddrSliceP(name, shorthand string, value []netip.Addr, usage string, opts ...NetipOption) *[]netip.Addr {
+	return CommandLine.AddrSliceP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,99 @@
+package pflag
+
+import "net/netip"
+
+// -- netip.AddrPort value
+type addrPortValue struct {
+	value *netip.AddrPort
+	opts  NetipOption
+}
+
+func newAddrPortValue(val netip.AddrPort, p *netip.AddrPort, opts []NetipOption) *addrPortValue {
+	*p = val
+	return &addrPortValue{value: p, opts: combineNetipOptions(opts)}
+}
+
+// String returns an empty string for the zero netip.AddrPort.
+func (v *addrPortValue) String() string {
+	if !v.value.IsValid() {
+		return ""
+	}
+	return v.value.String()
+}
+
+// Set parses s as an IP address and port. An empty string sets the zero netip.AddrPort.
+func (v *addrPortValue) Set(s string) error {
+	val, err := parseAddrPort(s, v.opts)
+	if err != nil {
+		return err
+	}
+	*v.value = val
+	return nil
+}
+
+func (v *addrPortValue) Type() string {
+	return "addrPort"
+}
+
+func addrPortConv(sval string) (interface{}, error) {
+	return parseAddrPort(sval, 0)
+}
+
+// GetAddrPort return the netip.AddrPort value of a flag with the given name
+func (f *FlagSet) GetAddrPort(name string) (netip.AddrPort, error) {
+	val, err := f.getFlagType(name, "addrPort", addrPortConv)
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	return val.(netip.AddrPort), nil
+}
+
+// AddrPortVar defines a netip.AddrPort flag with specified name, default value, and usage string.
+// The argument p points to a netip.AddrPort variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func (f *FlagSet) AddrPortVar(p *netip.AddrPort, name string, value netip.AddrPort, usage string, opts ...NetipOption) {
+	f.VarP(newAddrPortValue(value, p, opts), name, "", usage)
+}
+
+// AddrPortVarP is like AddrPortVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) AddrPortVarP(p *netip.AddrPort, name, shorthand string, value netip.AddrPort, usage string, opts ...NetipOption) {
+	f.VarP(newAddrPortValue(value, p, opts), name, shorthand, usage)
+}
+
+// AddrPortVar defines a netip.AddrPort flag with specified name, default value, and usage string.
+// The argument p points to a netip.AddrPort variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func AddrPortVar(p *netip.AddrPort, name string, value netip.AddrPort, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newAddrPortValue(value, p, opts), name, "", usage)
+}
+
+// AddrPortVarP is like AddrPortVar, but accepts a shorthand letter that can be used after a single dash.
+func AddrPortVarP(p *netip.AddrPort, name, shorthand string, value netip.AddrPort, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newAddrPortValue(value, p, opts), name, shorthand, usage)
+}
+
+// AddrPort defines a netip.AddrPort flag with specified name, default value, and usage string.
+// The return value is the address of a netip.AddrPort variable that stores the value of the flag.
+func (f *FlagSet) AddrPort(name string, value netip.AddrPort, usage string, opts ...NetipOption) *netip.AddrPort {
+	p := new(netip.AddrPort)
+	f.AddrPortVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// AddrPortP is like AddrPort, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) AddrPortP(name, shorthand string, value netip.AddrPort, usage string, opts ...NetipOption) *netip.AddrPort {
+	p := new(netip.AddrPort)
+	f.AddrPortVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// AddrPort defines a netip.AddrPort flag with specified name, default value, and usage string.
+// The return value is the address of a netip.AddrPort variable that stores the value of the flag.
+func AddrPort(name string, value netip.AddrPort, usage string, opts ...NetipOption) *netip.AddrPort {
+	return CommandLine.AddrPortP(name, "", value, usage, opts...)
+}
+
+// AddrPortP is like AddrPort, but accepts a shorthand letter that can be used after a single dash.
+func AddrPortP(name, shorthand string, value netip.AddrPort, usage string, opts ...NetipOption) *netip.AddrPort {
+	return CommandLine.AddrPortP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,179 @@
+package pflag
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+)
+
+// -- addrPortSlice Value
+type addrPortSliceValue struct {
+	value   *[]netip.AddrPort
+	opts    NetipOption
+	changed bool
+}
+
+func newAddrPortSliceValue(val []netip.AddrPort, p *[]netip.AddrPort, opts []NetipOption) *addrPortSliceValue {
+	v := &addrPortSliceValue{value: p, opts: combineNetipOptions(opts)}
+	*v.value = val
+	return v
+}
+
+// Set converts, and assigns, the comma-separated argument string representation as the []netip.AddrPort value of this flag.
+// If Set is called on a flag that already has a []netip.AddrPort assigned, the newly converted values will be appended.
+func (s *addrPortSliceValue) Set(val string) error {
+	strSlice, err := readAsCSV(val)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	out := make([]netip.AddrPort, 0, len(strSlice))
+	for _, str := range strSlice {
+		v, err := s.fromString(str)
+		if err != nil {
+			return err
+		}
+		out = append(out, v)
+	}
+
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+
+	s.changed = true
+
+	return nil
+}
+
+// Type returns a string that uniquely represents this flag's type.
+func (s *addrPortSliceValue) Type() string {
+	return "addrPortSlice"
+}
+
+// String defines a "native" format for this netip.AddrPort slice flag value.
+func (s *addrPortSliceValue) String() string {
+	out, _ := writeAsCSV(s.GetSlice())
+	return "[" + out + "]"
+}
+
+func (s *addrPortSliceValue) fromString(val string) (netip.AddrPort, error) {
+	if strings.TrimSpace(val) == "" {
+		return netip.AddrPort{}, fmt.Errorf("empty string being converted to an IP address and port")
+	}
+	return parseAddrPort(val, s.opts)
+}
+
+func (s *addrPortSliceValue) toString(val netip.AddrPort) string {
+	return val.String()
+}
+
+func (s *addrPortSliceValue) Append(val string) error {
+	v, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, v)
+	return nil
+}
+
+func (s *addrPortSliceValue) Replace(val []string) error {
+	out := make([]netip.AddrPort, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *addrPortSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+func addrPortSliceConv(val string) (interface{}, error) {
+	// only the outer brackets, IPv6 addresses with a port are bracketed too
+	val = strings.TrimSuffix(strings.TrimPrefix(val, "["), "]")
+	// Empty string would cause a slice with one (empty) entry
+	if len(val) == 0 {
+		return []netip.AddrPort{}, nil
+	}
+	ss := strings.Split(val, ",")
+	out := make([]netip.AddrPort, len(ss))
+	for i, sval := range ss {
+		v, err := parseAddrPort(sval, 0)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// GetAddrPortSlice returns the []netip.AddrPort value of a flag with the given name
+func (f *FlagSet) GetAddrPortSlice(name string) ([]netip.AddrPort, error) {
+	val, err := f.getFlagType(name, "addrPortSlice", addrPortSliceConv)
+	if err != nil {
+		return []netip.AddrPort{}, err
+	}
+	return val.([]netip.AddrPort), nil
+}
+
+// AddrPortSliceVar defines a addrPortSlice flag with specified name, default value, and usage string.
+// The argument p points to a []netip.AddrPort variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func (f *FlagSet) AddrPortSliceVar(p *[]netip.AddrPort, name string, value []netip.AddrPort, usage string, opts ...NetipOption) {
+	f.VarP(newAddrPortSliceValue(value, p, opts), name, "", usage)
+}
+
+// AddrPortSliceVarP is like AddrPortSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) AddrPortSliceVarP(p *[]netip.AddrPort, name, shorthand string, value []netip.AddrPort, usage string, opts ...NetipOption) {
+	f.VarP(newAddrPortSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// AddrPortSliceVar defines a []netip.AddrPort flag with specified name, default value, and usage string.
+// The argument p points to a []netip.AddrPort variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func AddrPortSliceVar(p *[]netip.AddrPort, name string, value []netip.AddrPort, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newAddrPortSliceValue(value, p, opts), name, "", usage)
+}
+
+// AddrPortSliceVarP is like AddrPortSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func AddrPortSliceVarP(p *[]netip.AddrPort, name, shorthand string, value []netip.AddrPort, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newAddrPortSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// AddrPortSlice defines a []netip.AddrPort flag with specified name, default value, and usage string.
+// The return value is the address of a []netip.AddrPort variable that stores the value of that flag.
+func (f *FlagSet) AddrPortSlice(name string, value []netip.AddrPort, usage string, opts ...NetipOption) *[]netip.AddrPort {
+	p := []netip.AddrPort{}
+	f.AddrPortSliceVarP(&p, name, "", value, usage, opts...)
+	return &p
+}
+
+// AddrPortSliceP is like AddrPortSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) AddrPortSliceP(name, shorthand string, value []netip.AddrPort, usage string, opts ...NetipOption) *[]netip.AddrPort {
+	p := []netip.AddrPort{}
+	f.AddrPortSliceVarP(&p, name, shorthand, value, usage, opts...)
+	return &p
+}
+
+// AddrPortSlice defines a []netip.AddrPort flag with specified name, default value, and usage string.
+// The return value is the address of a []netip.AddrPort variable that stores the value of the flag.
+func AddrPortSlice(name string, value []netip.AddrPort, usage string, opts ...NetipOption) *[]netip.AddrPort {
+	return CommandLine.AddrPortSliceP(name, "", value, usage, opts...)
+}
+
+// AddrPortSliceP is like AddrPortSlice, but accepts a shorthand letter that can be used after a single dash.
+func AddrPortSliceP(name, shorthand string, value []netip.AddrPort, usage string, opts ...NetipOption) *[]netip.AddrPort {
+	return CommandLine.AddrPortSliceP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,99 @@
+package pflag
+
+import "net/netip"
+
+// -- netip.Prefix value
+type prefixValue struct {
+	value *netip.Prefix
+	opts  NetipOption
+}
+
+func newPrefixValue(val netip.Prefix, p *netip.Prefix, opts []NetipOption) *prefixValue {
+	*p = val
+	return &prefixValue{value: p, opts: combineNetipOptions(opts)}
+}
+
+// String returns an empty string for the zero netip.Prefix.
+func (v *prefixValue) String() string {
+	if !v.value.IsValid() {
+		return ""
+	}
+	return v.value.String()
+}
+
+// Set parses s as an IP prefix. An empty string sets the zero netip.Prefix.
+func (v *prefixValue) Set(s string) error {
+	val, err := parsePrefix(s, v.opts)
+	if err != nil {
+		return err
+	}
+	*v.value = val
+	return nil
+}
+
+func (v *prefixValue) Type() string {
+	return "prefix"
+}
+
+func prefixConv(sval string) (interface{}, error) {
+	return parsePrefix(sval, 0)
+}
+
+// GetPrefix return the netip.Prefix value of a flag with the given name
+func (f *FlagSet) GetPrefix(name string) (netip.Prefix, error) {
+	val, err := f.getFlagType(name, "prefix", prefixConv)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return val.(netip.Prefix), nil
+}
+
+// PrefixVar defines a netip.Prefix flag with specified name, default value, and usage string.
+// The argument p points to a netip.Prefix variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func (f *FlagSet) PrefixVar(p *netip.Prefix, name string, value netip.Prefix, usage string, opts ...NetipOption) {
+	f.VarP(newPrefixValue(value, p, opts), name, "", usage)
+}
+
+// PrefixVarP is like PrefixVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PrefixVarP(p *netip.Prefix, name, shorthand string, value netip.Prefix, usage string, opts ...NetipOption) {
+	f.VarP(newPrefixValue(value, p, opts), name, shorthand, usage)
+}
+
+// PrefixVar defines a netip.Prefix flag with specified name, default value, and usage string.
+// The argument p points to a netip.Prefix variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func PrefixVar(p *netip.Prefix, name string, value netip.Prefix, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newPrefixValue(value, p, opts), name, "", usage)
+}
+
+// PrefixVarP is like PrefixVar, but accepts a shorthand letter that can be used after a single dash.
+func PrefixVarP(p *netip.Prefix, name, shorthand string, value netip.Prefix, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newPrefixValue(value, p, opts), name, shorthand, usage)
+}
+
+// Prefix defines a netip.Prefix flag with specified name, default value, and usage string.
+// The return value is the address of a netip.Prefix variable that stores the value of the flag.
+func (f *FlagSet) Prefix(name string, value netip.Prefix, usage string, opts ...NetipOption) *netip.Prefix {
+	p := new(netip.Prefix)
+	f.PrefixVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// PrefixP is like Prefix, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PrefixP(name, shorthand string, value netip.Prefix, usage string, opts ...NetipOption) *netip.Prefix {
+	p := new(netip.Prefix)
+	f.PrefixVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// Prefix defines a netip.Prefix flag with specified name, default value, and usage string.
+// The return value is the address of a netip.Prefix variable that stores the value of the flag.
+func Prefix(name string, value netip.Prefix, usage string, opts ...NetipOption) *netip.Prefix {
+	return CommandLine.PrefixP(name, "", value, usage, opts...)
+}
+
+// PrefixP is like Prefix, but accepts a shorthand letter that can be used after a single dash.
+func PrefixP(name, shorthand string, value netip.Prefix, usage string, opts ...NetipOption) *netip.Prefix {
+	return CommandLine.PrefixP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,179 @@
+package pflag
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+)
+
+// -- prefixSlice Value
+type prefixSliceValue struct {
+	value   *[]netip.Prefix
+	opts    NetipOption
+	changed bool
+}
+
+func newPrefixSliceValue(val []netip.Prefix, p *[]netip.Prefix, opts []NetipOption) *prefixSliceValue {
+	v := &prefixSliceValue{value: p, opts: combineNetipOptions(opts)}
+	*v.value = val
+	return v
+}
+
+// Set converts, and assigns, the comma-separated argument string representation as the []netip.Prefix value of this flag.
+// If Set is called on a flag that already has a []netip.Prefix assigned, the newly converted values will be appended.
+func (s *prefixSliceValue) Set(val string) error {
+	strSlice, err := readAsCSV(val)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	out := make([]netip.Prefix, 0, len(strSlice))
+	for _, str := range strSlice {
+		v, err := s.fromString(str)
+		if err != nil {
+			return err
+		}
+		out = append(out, v)
+	}
+
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+
+	s.changed = true
+
+	return nil
+}
+
+// Type returns a string that uniquely represents this flag's type.
+func (s *prefixSliceValue) Type() string {
+	return "prefixSlice"
+}
+
+// String defines a "native" format for this netip.Prefix slice flag value.
+func (s *prefixSliceValue) String() string {
+	out, _ := writeAsCSV(s.GetSlice())
+	return "[" + out + "]"
+}
+
+func (s *prefixSliceValue) fromString(val string) (netip.Prefix, error) {
+	if strings.TrimSpace(val) == "" {
+		return netip.Prefix{}, fmt.Errorf("empty string being converted to an IP prefix")
+	}
+	return parsePrefix(val, s.opts)
+}
+
+func (s *prefixSliceValue) toString(val netip.Prefix) string {
+	return val.String()
+}
+
+func (s *prefixSliceValue) Append(val string) error {
+	v, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, v)
+	return nil
+}
+
+func (s *prefixSliceValue) Replace(val []string) error {
+	out := make([]netip.Prefix, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *prefixSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+func prefixSliceConv(val string) (interface{}, error) {
+	// only the outer brackets, IPv6 addresses with a port are bracketed too
+	val = strings.TrimSuffix(strings.TrimPrefix(val, "["), "]")
+	// Empty string would cause a slice with one (empty) entry
+	if len(val) == 0 {
+		return []netip.Prefix{}, nil
+	}
+	ss := strings.Split(val, ",")
+	out := make([]netip.Prefix, len(ss))
+	for i, sval := range ss {
+		v, err := parsePrefix(sval, 0)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// GetPrefixSlice returns the []netip.Prefix value of a flag with the given name
+func (f *FlagSet) GetPrefixSlice(name string) ([]netip.Prefix, error) {
+	val, err := f.getFlagType(name, "prefixSlice", prefixSliceConv)
+	if err != nil {
+		return []netip.Prefix{}, err
+	}
+	return val.([]netip.Prefix), nil
+}
+
+// PrefixSliceVar defines a prefixSlice flag with specified name, default value, and usage string.
+// The argument p points to a []netip.Prefix variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func (f *FlagSet) PrefixSliceVar(p *[]netip.Prefix, name string, value []netip.Prefix, usage string, opts ...NetipOption) {
+	f.VarP(newPrefixSliceValue(value, p, opts), name, "", usage)
+}
+
+// PrefixSliceVarP is like PrefixSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PrefixSliceVarP(p *[]netip.Prefix, name, shorthand string, value []netip.Prefix, usage string, opts ...NetipOption) {
+	f.VarP(newPrefixSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// PrefixSliceVar defines a []netip.Prefix flag with specified name, default value, and usage string.
+// The argument p points to a []netip.Prefix variable in which to store the value of the flag.
+// The options restrict the accepted values.
+func PrefixSliceVar(p *[]netip.Prefix, name string, value []netip.Prefix, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newPrefixSliceValue(value, p, opts), name, "", usage)
+}
+
+// PrefixSliceVarP is like PrefixSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func PrefixSliceVarP(p *[]netip.Prefix, name, shorthand string, value []netip.Prefix, usage string, opts ...NetipOption) {
+	CommandLine.VarP(newPrefixSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// PrefixSlice defines a []netip.Prefix flag with specified name, default value, and usage string.
+// The return value is the address of a []netip.Prefix variable that stores the value of that flag.
+func (f *FlagSet) PrefixSlice(name string, value []netip.Prefix, usage string, opts ...NetipOption) *[]netip.Prefix {
+	p := []netip.Prefix{}
+	f.PrefixSliceVarP(&p, name, "", value, usage, opts...)
+	return &p
+}
+
+// PrefixSliceP is like PrefixSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PrefixSliceP(name, shorthand string, value []netip.Prefix, usage string, opts ...NetipOption) *[]netip.Prefix {
+	p := []netip.Prefix{}
+	f.PrefixSliceVarP(&p, name, shorthand, value, usage, opts...)
+	return &p
+}
+
+// PrefixSlice defines a []netip.Prefix flag with specified name, default value, and usage string.
+// The return value is the address of a []netip.Prefix variable that stores the value of the flag.
+func PrefixSlice(name string, value []netip.Prefix, usage string, opts ...NetipOption) *[]netip.Prefix {
+	return CommandLine.PrefixSliceP(name, "", value, usage, opts...)
+}
+
+// PrefixSliceP is like PrefixSlice, but accepts a shorthand letter that can be used after a single dash.
+func PrefixSliceP(name, shorthand string, value []netip.Prefix, usage string, opts ...NetipOption) *[]netip.Prefix {
+	return CommandLine.PrefixSliceP(name, shorthand, value, usage, opts...)
+}
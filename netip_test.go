@@ -0,0 +1,180 @@
+package pflag_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddr(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []pflag.NetipOption
+		expected string
+		ok       bool
+	}{
+		{"192.168.1.1", nil, "192.168.1.1", true},
+		{" 10.0.0.1 ", nil, "10.0.0.1", true},
+		{"::1", nil, "::1", true},
+		{"fe80::1%eth0", nil, "fe80::1%eth0", true},
+		{"", nil, "", true},
+		{"192.168.1", nil, "", false},
+		{"localhost", nil, "", false},
+		{"10.0.0.1", []pflag.NetipOption{pflag.IPv4Only}, "10.0.0.1", true},
+		{"::1", []pflag.NetipOption{pflag.IPv4Only}, "", false},
+		{"::ffff:10.0.0.1", []pflag.NetipOption{pflag.IPv4Only}, "", false},
+		{"::1", []pflag.NetipOption{pflag.IPv6Only}, "::1", true},
+		{"10.0.0.1", []pflag.NetipOption{pflag.IPv6Only}, "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			addr := f.Addr("address", netip.Addr{}, "IP address", tc.opts...)
+
+			err := f.Parse([]string{"--address=" + tc.input})
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, f.Lookup("address").Value.String())
+
+			got, err := f.GetAddr("address")
+			require.NoError(t, err)
+			require.Equal(t, *addr, got)
+		})
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []pflag.NetipOption
+		expected string
+		ok       bool
+	}{
+		{"10.0.0.0/8", nil, "10.0.0.0/8", true},
+		{"10.1.2.3/8", nil, "10.1.2.3/8", true},
+		{"2001:db8::/32", nil, "2001:db8::/32", true},
+		{"10.0.0.0", nil, "", false},
+		{"10.0.0.0/33", nil, "", false},
+		{"10.0.0.0/8", []pflag.NetipOption{pflag.MaskedPrefix}, "10.0.0.0/8", true},
+		{"10.1.2.3/8", []pflag.NetipOption{pflag.MaskedPrefix}, "", false},
+		{"2001:db8::/32", []pflag.NetipOption{pflag.IPv4Only | pflag.MaskedPrefix}, "", false},
+		{"2001:db8::/32", []pflag.NetipOption{pflag.IPv6Only, pflag.MaskedPrefix}, "2001:db8::/32", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			prefix := f.PrefixP("prefix", "p", netip.Prefix{}, "IP prefix", tc.opts...)
+
+			err := f.Parse([]string{"-p", tc.input})
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, prefix.String())
+
+			got, err := f.GetPrefix("prefix")
+			require.NoError(t, err)
+			require.Equal(t, *prefix, got)
+		})
+	}
+}
+
+func TestMaskedPrefixSuggestsMaskedForm(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Prefix("prefix", netip.Prefix{}, "IP prefix", pflag.MaskedPrefix)
+
+	err := f.Parse([]string{"--prefix=10.1.2.3/8"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "use 10.0.0.0/8")
+}
+
+func TestAddrPort(t *testing.T) {
+	var addrPort netip.AddrPort
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.AddrPortVar(&addrPort, "listen", netip.MustParseAddrPort("127.0.0.1:8080"), "listen address", pflag.IPv4Only)
+
+	require.NoError(t, f.Parse([]string{}))
+	require.Equal(t, netip.MustParseAddrPort("127.0.0.1:8080"), addrPort)
+
+	require.NoError(t, f.Parse([]string{"--listen=0.0.0.0:9090"}))
+	got, err := f.GetAddrPort("listen")
+	require.NoError(t, err)
+	require.Equal(t, netip.MustParseAddrPort("0.0.0.0:9090"), got)
+
+	require.Error(t, f.Parse([]string{"--listen=[::1]:9090"}))
+	require.Error(t, f.Parse([]string{"--listen=0.0.0.0"}))
+}
+
+func TestNetipSlices(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	addrs := f.AddrSlice("addrs", []netip.Addr{}, "addresses")
+	prefixes := f.PrefixSlice("prefixes", nil, "prefixes", pflag.MaskedPrefix)
+	addrPorts := f.AddrPortSlice("peers", nil, "peers", pflag.IPv6Only)
+
+	err := f.Parse([]string{
+		"--addrs=10.0.0.1,::1", "--addrs", "192.168.0.1",
+		"--prefixes=10.0.0.0/8,192.168.0.0/16",
+		"--peers=[::1]:80",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []netip.Addr{
+		netip.MustParseAddr("10.0.0.1"),
+		netip.MustParseAddr("::1"),
+		netip.MustParseAddr("192.168.0.1"),
+	}, *addrs)
+	require.Len(t, *prefixes, 2)
+	require.Equal(t, []netip.AddrPort{netip.MustParseAddrPort("[::1]:80")}, *addrPorts)
+
+	gotAddrs, err := f.GetAddrSlice("addrs")
+	require.NoError(t, err)
+	require.Equal(t, *addrs, gotAddrs)
+
+	gotPrefixes, err := f.GetPrefixSlice("prefixes")
+	require.NoError(t, err)
+	require.Equal(t, *prefixes, gotPrefixes)
+
+	gotAddrPorts, err := f.GetAddrPortSlice("peers")
+	require.NoError(t, err)
+	require.Equal(t, *addrPorts, gotAddrPorts)
+
+	require.Error(t, f.Parse([]string{"--prefixes=10.1.0.0/8"}))
+	require.Error(t, f.Parse([]string{"--peers=127.0.0.1:80"}))
+	require.Error(t, f.Parse([]string{"--addrs=10.0.0.1,,::1"}))
+}
+
+func TestNetipSliceValue(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.AddrSlice("addrs", []netip.Addr{netip.MustParseAddr("10.0.0.1")}, "addresses", pflag.IPv4Only)
+
+	sv, ok := f.Lookup("addrs").Value.(pflag.SliceValue)
+	require.True(t, ok)
+	require.NoError(t, sv.Append("10.0.0.2"))
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, sv.GetSlice())
+	require.Error(t, sv.Append("::1"))
+
+	require.NoError(t, sv.Replace([]string{"192.168.0.1"}))
+	require.Equal(t, "[192.168.0.1]", f.Lookup("addrs").Value.String())
+	require.Error(t, sv.Replace([]string{"::1"}))
+}
+
+func TestNetipZeroDefaults(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Addr("addr", netip.Addr{}, "address")
+	f.Prefix("prefix", netip.Prefix{}, "prefix")
+	f.AddrPort("listen", netip.MustParseAddrPort("127.0.0.1:80"), "listen address")
+	f.AddrSlice("addrs", nil, "addresses")
+
+	usages := f.FlagUsages()
+	require.Contains(t, usages, "--addr addr         address\n")
+	require.Contains(t, usages, "--prefix prefix     prefix\n")
+	require.Contains(t, usages, "--addrs addrSlice   addresses\n")
+	require.Contains(t, usages, "listen address (default 127.0.0.1:80)\n")
+}
@@ -0,0 +1,241 @@
+package pflag
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// OpenOption changes how the files of InputFile and OutputFile flags are
+// opened.
+type OpenOption func(*openOptions)
+
+type openOptions struct {
+	lazy   bool
+	flag   int
+	atomic bool
+	perm   os.FileMode
+}
+
+// OpenLazily defers opening the file until it is first read or written, so
+// errors are returned by Read or Write instead of Parse.
+func OpenLazily() OpenOption {
+	return func(o *openOptions) {
+		o.lazy = true
+	}
+}
+
+// OpenCreate creates output files that do not exist.
+func OpenCreate() OpenOption {
+	return func(o *openOptions) {
+		o.flag |= os.O_CREATE
+	}
+}
+
+// OpenTruncate truncates output files that exist.
+func OpenTruncate() OpenOption {
+	return func(o *openOptions) {
+		o.flag |= os.O_TRUNC
+	}
+}
+
+// OpenAppend appends writes to the end of output files.
+func OpenAppend() OpenOption {
+	return func(o *openOptions) {
+		o.flag |= os.O_APPEND
+	}
+}
+
+// OpenAtomic writes output to a temporary file next to the target, which
+// replaces the target when it is closed. Readers never see a partially
+// written file, and an existing target keeps its mode. It can not be
+// combined with OpenAppend.
+func OpenAtomic() OpenOption {
+	return func(o *openOptions) {
+		o.atomic = true
+	}
+}
+
+// OpenPerm sets the permission bits, before the umask, of created output
+// files. The default is 0666.
+func OpenPerm(perm os.FileMode) OpenOption {
+	return func(o *openOptions) {
+		o.perm = perm
+	}
+}
+
+func newOpenOptions(opts []OpenOption) openOptions {
+	o := openOptions{perm: 0o666}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// openInput opens path for reading, "-" is stdin.
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// openOutput opens path for writing, "-" is stdout. Without any of
+// OpenCreate, OpenTruncate and OpenAppend the file is created or truncated
+// as by os.Create.
+func (o openOptions) openOutput(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+
+	flag := o.flag
+	if flag == 0 {
+		flag = os.O_CREATE | os.O_TRUNC
+	}
+	if !o.atomic {
+		return os.OpenFile(path, os.O_WRONLY|flag, o.perm)
+	}
+
+	if flag&os.O_APPEND != 0 {
+		return nil, fmt.Errorf("unable to append to %q atomically", path)
+	}
+	if flag&os.O_CREATE == 0 {
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+	}
+	return createAtomicFile(path, o.perm)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// atomicFile is a temporary file that is renamed to path when closed.
+type atomicFile struct {
+	*os.File
+	path string
+}
+
+// createAtomicFile creates the temporary file for path. It gets the mode of
+// an existing target, so replacing it keeps its permissions, or else perm.
+func createAtomicFile(path string, perm os.FileMode) (*atomicFile, error) {
+	info, err := os.Stat(path)
+	keep := err == nil
+	if keep {
+		perm = info.Mode().Perm()
+	}
+
+	dir, base := filepath.Split(path)
+	for try := 0; ; try++ {
+		name := filepath.Join(dir, "."+base+".tmp"+strconv.Itoa(int(rand.Int31())))
+		file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+		if os.IsExist(err) && try < 100 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			// the umask may have cleared bits of the mode
+			if err := file.Chmod(perm); err != nil {
+				_ = file.Close()
+				_ = os.Remove(name)
+				return nil, err
+			}
+		}
+		return &atomicFile{File: file, path: path}, nil
+	}
+}
+
+// Close flushes the temporary file and moves it into place. The temporary
+// file is removed when that fails.
+func (a *atomicFile) Close() error {
+	name := a.File.Name()
+	err := a.File.Sync()
+	if closeErr := a.File.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(name, a.path)
+	}
+	if err != nil {
+		_ = os.Remove(name)
+	}
+	return err
+}
+
+// lazyFile opens its file on the first Read or Write.
+type lazyFile struct {
+	open   func() (io.Closer, error)
+	file   io.Closer
+	err    error
+	closed bool
+}
+
+func (l *lazyFile) get() (io.Closer, error) {
+	if l.file == nil && l.err == nil {
+		if l.closed {
+			return nil, os.ErrClosed
+		}
+		file, err := l.open()
+		if err != nil {
+			l.err = err
+			return nil, err
+		}
+		l.file = file
+	}
+	return l.file, l.err
+}
+
+func (l *lazyFile) Read(p []byte) (int, error) {
+	file, err := l.get()
+	if err != nil {
+		return 0, err
+	}
+	return file.(io.Reader).Read(p)
+}
+
+func (l *lazyFile) Write(p []byte) (int, error) {
+	file, err := l.get()
+	if err != nil {
+		return 0, err
+	}
+	return file.(io.Writer).Write(p)
+}
+
+// Close closes the file if it was opened.
+func (l *lazyFile) Close() error {
+	l.closed = true
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// Close closes the files opened by InputFile and OutputFile flags, and the
+// values of any other flags that implement io.Closer. All values are closed,
+// the first error is returned.
+func (f *FlagSet) Close() error {
+	var first error
+	f.VisitAll(func(flag *Flag) {
+		c, ok := flag.Value.(io.Closer)
+		if !ok {
+			return
+		}
+		if err := c.Close(); err != nil && first == nil {
+			first = fmt.Errorf("unable to close %q flag: %w", flag.Name, err)
+		}
+	})
+	return first
+}
+
+// Close closes the files opened by command-line flags.
+func Close() error {
+	return CommandLine.Close()
+}
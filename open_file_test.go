@@ -0,0 +1,166 @@
+package pflag_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "in.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0o644))
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	in := f.InputFileP("input", "i", "", "input file")
+	lazy := f.InputFile("lazy", "", "lazy input", pflag.OpenLazily())
+	def := f.InputFile("default", filepath.Join(dir, "missing.txt"), "default input")
+	none := f.InputFile("none", "", "no input")
+
+	require.NoError(t, f.Parse([]string{"-i", path, "--lazy", filepath.Join(dir, "missing.txt")}))
+	require.Equal(t, path, f.Lookup("input").Value.String())
+
+	data, err := io.ReadAll(*in)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	_, err = io.ReadAll(*lazy)
+	require.True(t, os.IsNotExist(err))
+	_, err = io.ReadAll(*def)
+	require.True(t, os.IsNotExist(err))
+	require.Nil(t, *none)
+
+	got, err := f.GetInputFile("input")
+	require.NoError(t, err)
+	require.Equal(t, *in, got)
+	_, err = f.GetOutputFile("input")
+	require.Error(t, err)
+
+	err = f.Parse([]string{"--input", filepath.Join(dir, "missing.txt")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--input")
+
+	require.NoError(t, f.Close())
+	require.NoError(t, f.Close())
+	_, err = (*in).Read(make([]byte, 1))
+	require.Error(t, err)
+}
+
+func TestInputFileStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	_, err = w.Write([]byte("from stdin"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	in := f.InputFile("input", "-", "input file")
+	require.NoError(t, f.Parse([]string{}))
+
+	data, err := io.ReadAll(*in)
+	require.NoError(t, err)
+	require.Equal(t, "from stdin", string(data))
+
+	require.NoError(t, f.Close())
+	_, err = r.Stat()
+	require.NoError(t, err, "stdin must stay open")
+	require.NoError(t, r.Close())
+}
+
+func TestOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+
+	tests := []struct {
+		name     string
+		opts     []pflag.OpenOption
+		expected string
+		err      string
+	}{
+		{"default", nil, "new", ""},
+		{"append", []pflag.OpenOption{pflag.OpenAppend()}, "old-new", ""},
+		{"truncate", []pflag.OpenOption{pflag.OpenTruncate()}, "new", ""},
+		{"atomic", []pflag.OpenOption{pflag.OpenAtomic()}, "new", ""},
+		{"lazy", []pflag.OpenOption{pflag.OpenLazily(), pflag.OpenAppend()}, "old-new", ""},
+		{"atomic append", []pflag.OpenOption{pflag.OpenAtomic(), pflag.OpenAppend()}, "", "atomically"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(existing, []byte("old-"), 0o644))
+
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			out := f.OutputFile("output", "", "output file", tc.opts...)
+
+			err := f.Parse([]string{"--output", existing})
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			_, err = (*out).Write([]byte("new"))
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			data, err := os.ReadFile(existing)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(data))
+		})
+	}
+}
+
+func TestOutputFileCreate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.OutputFile("append", "", "append", pflag.OpenAppend())
+	atomic := f.OutputFile("atomic", "", "atomic", pflag.OpenAtomic(), pflag.OpenCreate(), pflag.OpenPerm(0o600))
+	stdout := f.OutputFile("stdout", "-", "stdout")
+
+	err := f.Parse([]string{"--append", path})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no such file or directory")
+
+	require.NoError(t, f.Parse([]string{"--atomic", path}))
+	_, err = (*atomic).Write([]byte("data"))
+	require.NoError(t, err)
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err), "atomic output must not appear before Close")
+
+	require.NoError(t, f.Close())
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.Equal(t, "-", f.Lookup("stdout").Value.String())
+	require.NotNil(t, *stdout)
+	require.Contains(t, f.FlagUsages(), "--atomic outputFile")
+
+	// replacing an existing file keeps its mode, even bits the umask clears
+	require.NoError(t, os.Chmod(path, 0o666))
+	require.NoError(t, f.Parse([]string{"--atomic", path}))
+	_, err = (*atomic).Write([]byte("more"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o666), info.Mode().Perm())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "more", string(data))
+}
@@ -0,0 +1,116 @@
+package pflag
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rsb/failure"
+)
+
+// OptionalBoolAuto is the value of an optionalBool flag that leaves the
+// choice to the program.
+const OptionalBoolAuto = "auto"
+
+// -- optionalBool Value
+type optionalBoolValue struct {
+	value **bool
+}
+
+func newOptionalBoolValue(p **bool) *optionalBoolValue {
+	*p = nil
+	return &optionalBoolValue{value: p}
+}
+
+// Set stores the boolean s, "auto" or an empty string resets the flag to nil.
+func (b *optionalBoolValue) Set(s string) error {
+	if s == "" || strings.EqualFold(s, OptionalBoolAuto) {
+		*b.value = nil
+		return nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*b.value = &v
+	return nil
+}
+
+func (b *optionalBoolValue) Type() string {
+	return "optionalBool"
+}
+
+func (b *optionalBoolValue) String() string {
+	if *b.value == nil {
+		return OptionalBoolAuto
+	}
+	return strconv.FormatBool(**b.value)
+}
+
+func (b *optionalBoolValue) IsBoolFlag() bool { return true }
+
+// GetOptionalBool return the *bool value of a flag with the given name, nil
+// when the value was not provided or is auto
+func (f *FlagSet) GetOptionalBool(name string) (*bool, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return nil, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	b, ok := flag.Value.(*optionalBoolValue)
+	if !ok {
+		return nil, failure.InvalidState("trying to get optionalBool value of flag of type %s", flag.Value.Type())
+	}
+	return *b.value, nil
+}
+
+// OptionalBoolVar defines a three-state bool flag with specified name and usage string.
+// The argument p points to a *bool variable in which to store the value of the flag, which
+// stays nil until a value is provided by any source. --name sets true, --name=false sets
+// false and --name=auto sets nil again.
+func (f *FlagSet) OptionalBoolVar(p **bool, name string, usage string) {
+	f.OptionalBoolVarP(p, name, "", usage)
+}
+
+// OptionalBoolVarP is like OptionalBoolVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) OptionalBoolVarP(p **bool, name, shorthand string, usage string) {
+	flag := f.VarPF(newOptionalBoolValue(p), name, shorthand, usage)
+	flag.NoOptDefVal = "true"
+	_ = f.SetCompletionValues(name, "true", "false", OptionalBoolAuto)
+}
+
+// OptionalBoolVar defines a three-state bool flag with specified name and usage string.
+// The argument p points to a *bool variable in which to store the value of the flag, which
+// stays nil until a value is provided by any source. --name sets true, --name=false sets
+// false and --name=auto sets nil again.
+func OptionalBoolVar(p **bool, name string, usage string) {
+	CommandLine.OptionalBoolVarP(p, name, "", usage)
+}
+
+// OptionalBoolVarP is like OptionalBoolVar, but accepts a shorthand letter that can be used after a single dash.
+func OptionalBoolVarP(p **bool, name, shorthand string, usage string) {
+	CommandLine.OptionalBoolVarP(p, name, shorthand, usage)
+}
+
+// OptionalBool defines a three-state bool flag with specified name and usage string.
+// The return value is the address of a *bool variable that stores the value of the flag.
+func (f *FlagSet) OptionalBool(name string, usage string) **bool {
+	return f.OptionalBoolP(name, "", usage)
+}
+
+// OptionalBoolP is like OptionalBool, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) OptionalBoolP(name, shorthand string, usage string) **bool {
+	p := new(*bool)
+	f.OptionalBoolVarP(p, name, shorthand, usage)
+	return p
+}
+
+// OptionalBool defines a three-state bool flag with specified name and usage string.
+// The return value is the address of a *bool variable that stores the value of the flag.
+func OptionalBool(name string, usage string) **bool {
+	return CommandLine.OptionalBoolP(name, "", usage)
+}
+
+// OptionalBoolP is like OptionalBool, but accepts a shorthand letter that can be used after a single dash.
+func OptionalBoolP(name, shorthand string, usage string) **bool {
+	return CommandLine.OptionalBoolP(name, shorthand, usage)
+}
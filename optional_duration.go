@@ -0,0 +1,108 @@
+package pflag
+
+import (
+	"time"
+
+	"github.com/rsb/failure"
+)
+
+// -- optionalDuration Value
+type optionalDurationValue struct {
+	value **time.Duration
+}
+
+func newOptionalDurationValue(p **time.Duration) *optionalDurationValue {
+	*p = nil
+	return &optionalDurationValue{value: p}
+}
+
+// Set stores the duration s, an empty string resets the flag to nil. Days
+// and weeks are accepted as by ParseExtendedDuration.
+func (d *optionalDurationValue) Set(s string) error {
+	if s == "" {
+		*d.value = nil
+		return nil
+	}
+	v, err := ParseExtendedDuration(s)
+	if err != nil {
+		return err
+	}
+	*d.value = &v
+	return nil
+}
+
+func (d *optionalDurationValue) Type() string {
+	return "optionalDuration"
+}
+
+func (d *optionalDurationValue) String() string {
+	if *d.value == nil {
+		return ""
+	}
+	return FormatExtendedDuration(**d.value)
+}
+
+// GetOptionalDuration return the *time.Duration value of a flag with the given name, nil
+// when the value was not provided
+func (f *FlagSet) GetOptionalDuration(name string) (*time.Duration, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return nil, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	d, ok := flag.Value.(*optionalDurationValue)
+	if !ok {
+		return nil, failure.InvalidState("trying to get optionalDuration value of flag of type %s", flag.Value.Type())
+	}
+	return *d.value, nil
+}
+
+// OptionalDurationVar defines an optional time.Duration flag with specified name and usage string.
+// The argument p points to a *time.Duration variable in which to store the value of the flag, which
+// stays nil until a value is provided by any source.
+func (f *FlagSet) OptionalDurationVar(p **time.Duration, name string, usage string) {
+	f.VarP(newOptionalDurationValue(p), name, "", usage)
+}
+
+// OptionalDurationVarP is like OptionalDurationVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) OptionalDurationVarP(p **time.Duration, name, shorthand string, usage string) {
+	f.VarP(newOptionalDurationValue(p), name, shorthand, usage)
+}
+
+// OptionalDurationVar defines an optional time.Duration flag with specified name and usage string.
+// The argument p points to a *time.Duration variable in which to store the value of the flag, which
+// stays nil until a value is provided by any source.
+func OptionalDurationVar(p **time.Duration, name string, usage string) {
+	CommandLine.VarP(newOptionalDurationValue(p), name, "", usage)
+}
+
+// OptionalDurationVarP is like OptionalDurationVar, but accepts a shorthand letter that can be used after a single dash.
+func OptionalDurationVarP(p **time.Duration, name, shorthand string, usage string) {
+	CommandLine.VarP(newOptionalDurationValue(p), name, shorthand, usage)
+}
+
+// OptionalDuration defines an optional time.Duration flag with specified name and usage string.
+// The return value is the address of a *time.Duration variable that stores the value of the flag.
+func (f *FlagSet) OptionalDuration(name string, usage string) **time.Duration {
+	p := new(*time.Duration)
+	f.OptionalDurationVarP(p, name, "", usage)
+	return p
+}
+
+// OptionalDurationP is like OptionalDuration, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) OptionalDurationP(name, shorthand string, usage string) **time.Duration {
+	p := new(*time.Duration)
+	f.OptionalDurationVarP(p, name, shorthand, usage)
+	return p
+}
+
+// OptionalDuration defines an optional time.Duration flag with specified name and usage string.
+// The return value is the address of a *time.Duration variable that stores the value of the flag.
+func OptionalDuration(name string, usage string) **time.Duration {
+	return CommandLine.OptionalDurationP(name, "", usage)
+}
+
+// OptionalDurationP is like OptionalDuration, but accepts a shorthand letter that can be used after a single dash.
+func OptionalDurationP(name, shorthand string, usage string) **time.Duration {
+	return CommandLine.OptionalDurationP(name, shorthand, usage)
+}
@@ -0,0 +1,108 @@
+package pflag
+
+import (
+	"strconv"
+
+	"github.com/rsb/failure"
+)
+
+// -- optionalInt Value
+type optionalIntValue struct {
+	value **int
+}
+
+func newOptionalIntValue(p **int) *optionalIntValue {
+	*p = nil
+	return &optionalIntValue{value: p}
+}
+
+// Set stores the integer s, an empty string resets the flag to nil.
+func (i *optionalIntValue) Set(s string) error {
+	if s == "" {
+		*i.value = nil
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	if err != nil {
+		return err
+	}
+	n := int(v)
+	*i.value = &n
+	return nil
+}
+
+func (i *optionalIntValue) Type() string {
+	return "optionalInt"
+}
+
+func (i *optionalIntValue) String() string {
+	if *i.value == nil {
+		return ""
+	}
+	return strconv.Itoa(**i.value)
+}
+
+// GetOptionalInt return the *int value of a flag with the given name, nil
+// when the value was not provided
+func (f *FlagSet) GetOptionalInt(name string) (*int, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return nil, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	i, ok := flag.Value.(*optionalIntValue)
+	if !ok {
+		return nil, failure.InvalidState("trying to get optionalInt value of flag of type %s", flag.Value.Type())
+	}
+	return *i.value, nil
+}
+
+// OptionalIntVar defines an optional int flag with specified name and usage string.
+// The argument p points to a *int variable in which to store the value of the flag, which
+// stays nil until a value is provided by any source.
+func (f *FlagSet) OptionalIntVar(p **int, name string, usage string) {
+	f.VarP(newOptionalIntValue(p), name, "", usage)
+}
+
+// OptionalIntVarP is like OptionalIntVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) OptionalIntVarP(p **int, name, shorthand string, usage string) {
+	f.VarP(newOptionalIntValue(p), name, shorthand, usage)
+}
+
+// OptionalIntVar defines an optional int flag with specified name and usage string.
+// The argument p points to a *int variable in which to store the value of the flag, which
+// stays nil until a value is provided by any source.
+func OptionalIntVar(p **int, name string, usage string) {
+	CommandLine.VarP(newOptionalIntValue(p), name, "", usage)
+}
+
+// OptionalIntVarP is like OptionalIntVar, but accepts a shorthand letter that can be used after a single dash.
+func OptionalIntVarP(p **int, name, shorthand string, usage string) {
+	CommandLine.VarP(newOptionalIntValue(p), name, shorthand, usage)
+}
+
+// OptionalInt defines an optional int flag with specified name and usage string.
+// The return value is the address of a *int variable that stores the value of the flag.
+func (f *FlagSet) OptionalInt(name string, usage string) **int {
+	p := new(*int)
+	f.OptionalIntVarP(p, name, "", usage)
+	return p
+}
+
+// OptionalIntP is like OptionalInt, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) OptionalIntP(name, shorthand string, usage string) **int {
+	p := new(*int)
+	f.OptionalIntVarP(p, name, shorthand, usage)
+	return p
+}
+
+// OptionalInt defines an optional int flag with specified name and usage string.
+// The return value is the address of a *int variable that stores the value of the flag.
+func OptionalInt(name string, usage string) **int {
+	return CommandLine.OptionalIntP(name, "", usage)
+}
+
+// OptionalIntP is like OptionalInt, but accepts a shorthand letter that can be used after a single dash.
+func OptionalIntP(name, shorthand string, usage string) **int {
+	return CommandLine.OptionalIntP(name, shorthand, usage)
+}
@@ -0,0 +1,123 @@
+package pflag_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalBool(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected *bool
+	}{
+		{[]string{}, nil},
+		{[]string{"--color"}, boolPtr(true)},
+		{[]string{"-c"}, boolPtr(true)},
+		{[]string{"--color=false"}, boolPtr(false)},
+		{[]string{"--color=true"}, boolPtr(true)},
+		{[]string{"--color", "--color=AUTO"}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			color := f.OptionalBoolP("color", "c", "colorize output")
+
+			require.NoError(t, f.Parse(tc.args))
+			require.Equal(t, tc.expected, *color)
+
+			got, err := f.GetOptionalBool("color")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.OptionalBool("color", "colorize output")
+	err := f.Parse([]string{"--color=maybe"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid argument "maybe" for "--color" flag`)
+
+	require.Equal(t, "auto", f.Lookup("color").Value.String())
+	require.Equal(t, []string{"true", "false", "auto"}, f.Lookup("color").Annotations[pflag.CompletionValues])
+	require.Contains(t, f.FlagUsages(), "--color   colorize output\n")
+}
+
+func TestOptionalInt(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	retries := f.OptionalIntP("retries", "r", "number of retries")
+	workers := f.OptionalInt("workers", "number of workers")
+
+	require.Nil(t, *retries)
+	require.NoError(t, f.Parse([]string{"-r", "0"}))
+	require.NotNil(t, *retries)
+	require.Equal(t, 0, **retries)
+	require.Nil(t, *workers)
+
+	got, err := f.GetOptionalInt("retries")
+	require.NoError(t, err)
+	require.Equal(t, *retries, got)
+
+	require.NoError(t, f.Parse([]string{"--workers=0x10"}))
+	require.Equal(t, 16, **workers)
+	require.Equal(t, "16", f.Lookup("workers").Value.String())
+
+	require.NoError(t, f.Set("workers", ""))
+	require.Nil(t, *workers)
+
+	require.Error(t, f.Parse([]string{"--workers=many"}))
+	_, err = f.GetOptionalBool("workers")
+	require.Error(t, err)
+
+	require.Contains(t, f.FlagUsages(), "--workers int   number of workers\n")
+}
+
+func TestOptionalDuration(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	timeout := f.OptionalDuration("timeout", "request timeout")
+	ttl := f.OptionalDurationP("ttl", "t", "cache ttl")
+
+	require.NoError(t, f.Parse([]string{"--timeout=0s", "-t", "2d"}))
+	require.Equal(t, time.Duration(0), **timeout)
+	require.Equal(t, 48*time.Hour, **ttl)
+	require.Equal(t, "2d", f.Lookup("ttl").Value.String())
+
+	got, err := f.GetOptionalDuration("ttl")
+	require.NoError(t, err)
+	require.Equal(t, *ttl, got)
+
+	require.Error(t, f.Parse([]string{"--timeout=soon"}))
+	require.Contains(t, f.FlagUsages(), "--timeout duration   request timeout\n")
+}
+
+func TestOptionalSources(t *testing.T) {
+	t.Setenv("APP_COLOR", "false")
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	color := f.OptionalBool("color", "colorize output")
+	retries := f.OptionalInt("retries", "number of retries")
+	timeout := f.OptionalDuration("timeout", "request timeout")
+	require.NoError(t, f.BindEnv("color", "APP_COLOR"))
+
+	require.NoError(t, f.Parse([]string{}))
+	require.NoError(t, f.ParseEnv())
+	require.NoError(t, f.ParseConfig(strings.NewReader(`{"retries": 0}`), pflag.ConfigJSON))
+
+	require.False(t, f.Changed("color"))
+	require.Equal(t, boolPtr(false), *color)
+	require.Equal(t, 0, **retries)
+	require.Nil(t, *timeout)
+
+	err := f.ParseConfig(strings.NewReader(`{"retries": 3, "timeout": "never"}`), pflag.ConfigJSON)
+	require.Error(t, err)
+	require.Equal(t, 0, **retries)
+	require.Nil(t, *timeout)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,149 @@
+package pflag
+
+import (
+	"io"
+
+	"github.com/rsb/failure"
+)
+
+// -- outputFile Value
+type outputFileValue struct {
+	value *io.WriteCloser
+	path  string
+	file  io.WriteCloser
+	opts  openOptions
+}
+
+func newOutputFileValue(val string, p *io.WriteCloser, opts openOptions) *outputFileValue {
+	v := &outputFileValue{value: p, opts: opts}
+	v.use(val, v.lazy(val))
+	return v
+}
+
+func (v *outputFileValue) lazy(path string) io.WriteCloser {
+	if path == "" {
+		return nil
+	}
+	return &lazyFile{open: func() (io.Closer, error) {
+		return v.opts.openOutput(path)
+	}}
+}
+
+// use makes file the value of the flag, closing the file of the previous
+// value.
+func (v *outputFileValue) use(path string, file io.WriteCloser) {
+	_ = v.Close()
+	v.path = path
+	v.file = file
+	*v.value = file
+}
+
+// Set opens the file at s, or stdout for "-". With OpenLazily the file is
+// opened when it is first written. An empty string leaves the flag without a
+// file.
+func (v *outputFileValue) Set(s string) error {
+	if s == "" || v.opts.lazy {
+		v.use(s, v.lazy(s))
+		return nil
+	}
+	file, err := v.opts.openOutput(s)
+	if err != nil {
+		return err
+	}
+	v.use(s, file)
+	return nil
+}
+
+func (v *outputFileValue) Type() string {
+	return "outputFile"
+}
+
+func (v *outputFileValue) String() string { return v.path }
+
+// saveState returns a func that makes path the value again. Set closed the
+// file of the path, so it is opened again lazily.
+func (v *outputFileValue) saveState() func() {
+	path := v.path
+	return func() { v.use(path, v.lazy(path)) }
+}
+
+// Close closes the file of the flag, if it was opened.
+func (v *outputFileValue) Close() error {
+	if v.file == nil {
+		return nil
+	}
+	file := v.file
+	v.file = nil
+	return file.Close()
+}
+
+// GetOutputFile returns the io.WriteCloser value of a flag with the given name
+func (f *FlagSet) GetOutputFile(name string) (io.WriteCloser, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return nil, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	v, ok := flag.Value.(*outputFileValue)
+	if !ok {
+		return nil, failure.InvalidState("trying to get outputFile value of flag of type %s", flag.Value.Type())
+	}
+	return *v.value, nil
+}
+
+func (f *FlagSet) varOutputFile(p *io.WriteCloser, name, shorthand string, value string, usage string, opts []OpenOption) {
+	f.VarP(newOutputFileValue(value, p, newOpenOptions(opts)), name, shorthand, usage)
+	_ = f.MarkFilename(name)
+}
+
+// OutputFileVar defines an output file flag with specified name, default value, and usage string.
+// The argument p points to an io.WriteCloser variable in which to store the opened file, "-" is
+// stdout. Files are opened by Parse, the default value is opened when it is first written. Unless
+// the options say otherwise files are created or truncated. Opened files are closed by Close.
+func (f *FlagSet) OutputFileVar(p *io.WriteCloser, name string, value string, usage string, opts ...OpenOption) {
+	f.varOutputFile(p, name, "", value, usage, opts)
+}
+
+// OutputFileVarP is like OutputFileVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) OutputFileVarP(p *io.WriteCloser, name, shorthand string, value string, usage string, opts ...OpenOption) {
+	f.varOutputFile(p, name, shorthand, value, usage, opts)
+}
+
+// OutputFileVar defines an output file flag with specified name, default value, and usage string.
+// The argument p points to an io.WriteCloser variable in which to store the opened file, "-" is
+// stdout. Files are opened by Parse, the default value is opened when it is first written. Unless
+// the options say otherwise files are created or truncated. Opened files are closed by Close.
+func OutputFileVar(p *io.WriteCloser, name string, value string, usage string, opts ...OpenOption) {
+	CommandLine.varOutputFile(p, name, "", value, usage, opts)
+}
+
+// OutputFileVarP is like OutputFileVar, but accepts a shorthand letter that can be used after a single dash.
+func OutputFileVarP(p *io.WriteCloser, name, shorthand string, value string, usage string, opts ...OpenOption) {
+	CommandLine.varOutputFile(p, name, shorthand, value, usage, opts)
+}
+
+// OutputFile defines an output file flag with specified name, default value, and usage string.
+// The return value is the address of an io.WriteCloser variable that stores the opened file.
+func (f *FlagSet) OutputFile(name string, value string, usage string, opts ...OpenOption) *io.WriteCloser {
+	p := new(io.WriteCloser)
+	f.OutputFileVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// OutputFileP is like OutputFile, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) OutputFileP(name, shorthand string, value string, usage string, opts ...OpenOption) *io.WriteCloser {
+	p := new(io.WriteCloser)
+	f.OutputFileVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// OutputFile defines an output file flag with specified name, default value, and usage string.
+// The return value is the address of an io.WriteCloser variable that stores the opened file.
+func OutputFile(name string, value string, usage string, opts ...OpenOption) *io.WriteCloser {
+	return CommandLine.OutputFileP(name, "", value, usage, opts...)
+}
+
+// OutputFileP is like OutputFile, but accepts a shorthand letter that can be used after a single dash.
+func OutputFileP(name, shorthand string, value string, usage string, opts ...OpenOption) *io.WriteCloser {
+	return CommandLine.OutputFileP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,361 @@
+package pflag
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathOption validates or transforms the values of Path, File and Dir flags.
+type PathOption func(*pathOptions)
+
+type pathOptions struct {
+	mustExist    bool
+	mustNotExist bool
+	regular      bool
+	directory    bool
+	readable     bool
+	writable     bool
+	expandUser   bool
+	expandEnv    bool
+	absolute     bool
+	base         string
+	glob         bool
+	extensions   []string
+}
+
+// PathMustExist rejects paths that do not exist.
+func PathMustExist() PathOption {
+	return func(o *pathOptions) {
+		o.mustExist = true
+	}
+}
+
+// PathMustNotExist rejects paths that exist.
+func PathMustNotExist() PathOption {
+	return func(o *pathOptions) {
+		o.mustNotExist = true
+	}
+}
+
+// PathRegularFile rejects paths that exist but are not regular files. File
+// flags always have it.
+func PathRegularFile() PathOption {
+	return func(o *pathOptions) {
+		o.regular = true
+	}
+}
+
+// PathDirectory rejects paths that exist but are not directories. Dir flags
+// always have it.
+func PathDirectory() PathOption {
+	return func(o *pathOptions) {
+		o.directory = true
+	}
+}
+
+// PathReadable rejects paths that exist but can not be read.
+func PathReadable() PathOption {
+	return func(o *pathOptions) {
+		o.readable = true
+	}
+}
+
+// PathWritable rejects paths that can not be written, or created when they do
+// not exist.
+func PathWritable() PathOption {
+	return func(o *pathOptions) {
+		o.writable = true
+	}
+}
+
+// PathExpandUser replaces a leading ~ with the home directory of the user.
+func PathExpandUser() PathOption {
+	return func(o *pathOptions) {
+		o.expandUser = true
+	}
+}
+
+// PathExpandEnv replaces $VAR and ${VAR} with the value of the environment
+// variable.
+func PathExpandEnv() PathOption {
+	return func(o *pathOptions) {
+		o.expandEnv = true
+	}
+}
+
+// PathAbsolute makes relative paths absolute by joining them to base, or to
+// the working directory when base is empty.
+func PathAbsolute(base string) PathOption {
+	return func(o *pathOptions) {
+		o.absolute = true
+		o.base = base
+	}
+}
+
+// PathGlob expands the values of PathSlice, FileSlice and DirSlice flags that
+// are patterns, as in filepath.Match, to the matching paths. Patterns that
+// match nothing are kept as they are, unless PathMustExist is given. It has
+// no effect on single path flags.
+func PathGlob() PathOption {
+	return func(o *pathOptions) {
+		o.glob = true
+	}
+}
+
+// PathExtensions rejects files without one of the extensions, such as "yaml"
+// or ".yml", and offers only such files during shell completion.
+func PathExtensions(extensions ...string) PathOption {
+	return func(o *pathOptions) {
+		o.extensions = append(o.extensions, extensions...)
+	}
+}
+
+type pathKind int
+
+const (
+	kindPath pathKind = iota
+	kindFile
+	kindDir
+)
+
+func (k pathKind) typeName() string {
+	switch k {
+	case kindFile:
+		return "file"
+	case kindDir:
+		return "dir"
+	}
+	return "path"
+}
+
+func newPathOptions(kind pathKind, opts []PathOption) pathOptions {
+	var o pathOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	switch kind {
+	case kindFile:
+		o.regular = true
+	case kindDir:
+		o.directory = true
+	}
+	return o
+}
+
+// resolve expands and absolutizes path as requested by the options.
+func (o pathOptions) resolve(path string) (string, error) {
+	if o.expandUser && (path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator))) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("unable to expand %q: %v", path, err)
+		}
+		path = home + path[1:]
+	}
+	if o.expandEnv {
+		path = os.ExpandEnv(path)
+	}
+	if o.absolute && !filepath.IsAbs(path) {
+		if o.base != "" {
+			path = filepath.Join(o.base, path)
+		} else {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return "", fmt.Errorf("unable to make %q absolute: %v", path, err)
+			}
+			path = abs
+		}
+	}
+	return filepath.Clean(path), nil
+}
+
+// check validates path against the options.
+func (o pathOptions) check(path string) error {
+	if len(o.extensions) > 0 && !hasExtension(path, o.extensions) {
+		return fmt.Errorf("%q must have extension %s", path, strings.Join(o.extensions, ", "))
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		if o.mustExist {
+			return fmt.Errorf("%q does not exist", path)
+		}
+		if o.writable {
+			return checkWritable(filepath.Dir(path), path)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("unable to access %q: %v", path, err)
+	case o.mustNotExist:
+		return fmt.Errorf("%q already exists", path)
+	case o.regular && !info.Mode().IsRegular():
+		if info.IsDir() {
+			return fmt.Errorf("%q is a directory, not a file", path)
+		}
+		return fmt.Errorf("%q is not a regular file", path)
+	case o.directory && !info.IsDir():
+		return fmt.Errorf("%q is not a directory", path)
+	}
+
+	if o.readable {
+		if err := checkAccess(path, false); err != nil {
+			return fmt.Errorf("%q is not readable: %v", path, unwrapPathError(err))
+		}
+	}
+	if o.writable {
+		return checkWritable(path, path)
+	}
+	return nil
+}
+
+// checkWritable checks whether target, which is path or the directory path
+// would be created in, can be written. Nothing is opened, so FIFOs do not
+// block, and nothing is created.
+func checkWritable(target, path string) error {
+	if err := checkAccess(target, true); err != nil {
+		return fmt.Errorf("%q is not writable: %v", path, unwrapPathError(err))
+	}
+	return nil
+}
+
+func unwrapPathError(err error) error {
+	if pathErr, ok := err.(*os.PathError); ok {
+		return pathErr.Err
+	}
+	return err
+}
+
+func hasExtension(path string, extensions []string) bool {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	for _, want := range extensions {
+		if strings.EqualFold(ext, strings.TrimPrefix(want, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// parse resolves and validates path. An empty path is left empty.
+func (o pathOptions) parse(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	path, err := o.resolve(path)
+	if err != nil {
+		return "", err
+	}
+	return path, o.check(path)
+}
+
+// annotatePath sets the completion annotations matching the kind of the
+// named flag.
+func (f *FlagSet) annotatePath(name string, kind pathKind, o pathOptions) {
+	if kind == kindDir {
+		_ = f.MarkDirname(name)
+		return
+	}
+	_ = f.MarkFilename(name, o.extensions...)
+}
+
+// -- path Value, also used by File and Dir flags
+type pathValue struct {
+	value *string
+	kind  pathKind
+	opts  pathOptions
+}
+
+func newPathValue(kind pathKind, val string, p *string, opts pathOptions) *pathValue {
+	*p = val
+	return &pathValue{value: p, kind: kind, opts: opts}
+}
+
+// Set resolves and validates s. An empty string sets an empty path.
+func (v *pathValue) Set(s string) error {
+	path, err := v.opts.parse(s)
+	if err != nil {
+		return err
+	}
+	*v.value = path
+	return nil
+}
+
+func (v *pathValue) Type() string {
+	return v.kind.typeName()
+}
+
+func (v *pathValue) String() string { return *v.value }
+
+func (v *pathValue) saveState() func() {
+	path := *v.value
+	return func() { *v.value = path }
+}
+
+// GetPath return the path value of a flag with the given name
+func (f *FlagSet) GetPath(name string) (string, error) {
+	val, err := f.getFlagType(name, "path", stringConv)
+	if err != nil {
+		return "", err
+	}
+	return val.(string), nil
+}
+
+// varPath defines a Path, File or Dir flag.
+func (f *FlagSet) varPath(kind pathKind, p *string, name, shorthand string, value string, usage string, opts []PathOption) {
+	o := newPathOptions(kind, opts)
+	f.VarP(newPathValue(kind, value, p, o), name, shorthand, usage)
+	f.annotatePath(name, kind, o)
+}
+
+// PathVar defines a filesystem path flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// Values are expanded, resolved and validated as the options request, the default value is
+// used as given. The value is completed with file names.
+func (f *FlagSet) PathVar(p *string, name string, value string, usage string, opts ...PathOption) {
+	f.varPath(kindPath, p, name, "", value, usage, opts)
+}
+
+// PathVarP is like PathVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PathVarP(p *string, name, shorthand string, value string, usage string, opts ...PathOption) {
+	f.varPath(kindPath, p, name, shorthand, value, usage, opts)
+}
+
+// PathVar defines a filesystem path flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// Values are expanded, resolved and validated as the options request, the default value is
+// used as given. The value is completed with file names.
+func PathVar(p *string, name string, value string, usage string, opts ...PathOption) {
+	CommandLine.varPath(kindPath, p, name, "", value, usage, opts)
+}
+
+// PathVarP is like PathVar, but accepts a shorthand letter that can be used after a single dash.
+func PathVarP(p *string, name, shorthand string, value string, usage string, opts ...PathOption) {
+	CommandLine.varPath(kindPath, p, name, shorthand, value, usage, opts)
+}
+
+// Path defines a filesystem path flag with specified name, default value, and usage string.
+// The return value is the address of a string variable that stores the value of the flag.
+func (f *FlagSet) Path(name string, value string, usage string, opts ...PathOption) *string {
+	p := new(string)
+	f.PathVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// PathP is like Path, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PathP(name, shorthand string, value string, usage string, opts ...PathOption) *string {
+	p := new(string)
+	f.PathVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// Path defines a filesystem path flag with specified name, default value, and usage string.
+// The return value is the address of a string variable that stores the value of the flag.
+func Path(name string, value string, usage string, opts ...PathOption) *string {
+	return CommandLine.PathP(name, "", value, usage, opts...)
+}
+
+// PathP is like Path, but accepts a shorthand letter that can be used after a single dash.
+func PathP(name, shorthand string, value string, usage string, opts ...PathOption) *string {
+	return CommandLine.PathP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,20 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package pflag
+
+import "os"
+
+// checkAccess reports whether path can be written, or read, from its
+// permission bits, as there is no access(2) on this platform.
+func checkAccess(path string, write bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	perm := info.Mode().Perm()
+	if write && perm&0o222 == 0 || !write && perm&0o444 == 0 {
+		return os.ErrPermission
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package pflag
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// -- pathSlice Value, also used by FileSlice and DirSlice flags
+type pathSliceValue struct {
+	value   *[]string
+	kind    pathKind
+	opts    pathOptions
+	changed bool
+}
+
+func newPathSliceValue(kind pathKind, val []string, p *[]string, opts pathOptions) *pathSliceValue {
+	psv := &pathSliceValue{value: p, kind: kind, opts: opts}
+	*psv.value = val
+	return psv
+}
+
+// Set resolves and validates the comma-separated paths in val, expanding
+// patterns when the flag has the PathGlob option. If Set is called on a flag that
+// already has paths assigned, the new paths will be appended.
+func (s *pathSliceValue) Set(val string) error {
+	v, err := readAsCSV(val)
+	if err != nil {
+		return err
+	}
+	out, err := s.expand(v)
+	if err != nil {
+		return err
+	}
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+	s.changed = true
+	return nil
+}
+
+// expand resolves and validates every value, replacing patterns with the
+// paths they match.
+func (s *pathSliceValue) expand(values []string) ([]string, error) {
+	out := make([]string, 0, len(values))
+	for _, val := range values {
+		if strings.TrimSpace(val) == "" {
+			return nil, fmt.Errorf("empty path")
+		}
+		path, err := s.opts.resolve(val)
+		if err != nil {
+			return nil, err
+		}
+
+		matches := []string{path}
+		if s.opts.glob && strings.ContainsAny(path, `*?[`) {
+			found, err := filepath.Glob(path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %v", val, err)
+			}
+			if len(found) > 0 {
+				matches = found
+			} else if s.opts.mustExist {
+				return nil, fmt.Errorf("no paths match %q", val)
+			}
+		}
+
+		for _, match := range matches {
+			if err := s.opts.check(match); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, matches...)
+	}
+	return out, nil
+}
+
+func (s *pathSliceValue) Type() string {
+	return s.kind.typeName() + "Slice"
+}
+
+func (s *pathSliceValue) saveState() func() {
+	paths, changed := append((*s.value)[:0:0], *s.value...), s.changed
+	return func() { *s.value, s.changed = append(paths[:0:0], paths...), changed }
+}
+
+func (s *pathSliceValue) String() string {
+	str, _ := writeAsCSV(*s.value)
+	return "[" + str + "]"
+}
+
+func (s *pathSliceValue) Append(val string) error {
+	out, err := s.expand([]string{val})
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, out...)
+	return nil
+}
+
+func (s *pathSliceValue) Replace(val []string) error {
+	out, err := s.expand(val)
+	if err != nil {
+		return err
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *pathSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	copy(out, *s.value)
+	return out
+}
+
+// GetPathSlice return the []string value of a pathSlice flag with the given name
+func (f *FlagSet) GetPathSlice(name string) ([]string, error) {
+	val, err := f.getFlagType(name, "pathSlice", stringSliceConv)
+	if err != nil {
+		return []string{}, err
+	}
+	return val.([]string), nil
+}
+
+// GetFileSlice return the []string value of a fileSlice flag with the given name
+func (f *FlagSet) GetFileSlice(name string) ([]string, error) {
+	val, err := f.getFlagType(name, "fileSlice", stringSliceConv)
+	if err != nil {
+		return []string{}, err
+	}
+	return val.([]string), nil
+}
+
+// GetDirSlice return the []string value of a dirSlice flag with the given name
+func (f *FlagSet) GetDirSlice(name string) ([]string, error) {
+	val, err := f.getFlagType(name, "dirSlice", stringSliceConv)
+	if err != nil {
+		return []string{}, err
+	}
+	return val.([]string), nil
+}
+
+// varPathSlice defines a PathSlice, FileSlice or DirSlice flag.
+func (f *FlagSet) varPathSlice(kind pathKind, p *[]string, name, shorthand string, value []string, usage string, opts []PathOption) {
+	o := newPathOptions(kind, opts)
+	f.VarP(newPathSliceValue(kind, value, p, o), name, shorthand, usage)
+	f.annotatePath(name, kind, o)
+}
+
+// PathSliceVar defines a pathSlice flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag.
+// Each path is handled like the value of a Path flag, patterns are expanded with the PathGlob option.
+func (f *FlagSet) PathSliceVar(p *[]string, name string, value []string, usage string, opts ...PathOption) {
+	f.varPathSlice(kindPath, p, name, "", value, usage, opts)
+}
+
+// PathSliceVarP is like PathSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PathSliceVarP(p *[]string, name, shorthand string, value []string, usage string, opts ...PathOption) {
+	f.varPathSlice(kindPath, p, name, shorthand, value, usage, opts)
+}
+
+// PathSliceVar defines a pathSlice flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the value of the flag.
+// Each path is handled like the value of a Path flag, patterns are expanded with the PathGlob option.
+func PathSliceVar(p *[]string, name string, value []string, usage string, opts ...PathOption) {
+	CommandLine.varPathSlice(kindPath, p, name, "", value, usage, opts)
+}
+
+// PathSliceVarP is like PathSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func PathSliceVarP(p *[]string, name, shorthand string, value []string, usage string, opts ...PathOption) {
+	CommandLine.varPathSlice(kindPath, p, name, shorthand, value, usage, opts)
+}
+
+// PathSlice defines a pathSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []string variable that stores the value of the flag.
+func (f *FlagSet) PathSlice(name string, value []string, usage string, opts ...PathOption) *[]string {
+	p := []string{}
+	f.PathSliceVarP(&p, name, "", value, usage, opts...)
+	return &p
+}
+
+// PathSliceP is like PathSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PathSliceP(name, shorthand string, value []string, usage string, opts ...PathOption) *[]string {
+	p := []string{}
+	f.PathSliceVarP(&p, name, shorthand, value, usage, opts...)
+	return &p
+}
+
+// PathSlice defines a pathSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []string variable that stores the value of the flag.
+func PathSlice(name string, value []string, usage string, opts ...PathOption) *[]string {
+	return CommandLine.PathSliceP(name, "", value, usage, opts...)
+}
+
+// PathSliceP is like PathSlice, but accepts a shorthand letter that can be used after a single dash.
+func PathSliceP(name, shorthand string, value []string, usage string, opts ...PathOption) *[]string {
+	return CommandLine.PathSliceP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,173 @@
+package pflag_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpPathDir(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("a: 1\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("b: 1\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "data"), 0o755))
+	return dir
+}
+
+func TestPathChecks(t *testing.T) {
+	dir := setUpPathDir(t)
+	file := filepath.Join(dir, "app.yaml")
+	missing := filepath.Join(dir, "missing.yaml")
+	sub := filepath.Join(dir, "data")
+
+	tests := []struct {
+		name  string
+		def   func(f *pflag.FlagSet)
+		value string
+		err   string
+	}{
+		{"path exists", func(f *pflag.FlagSet) { f.Path("p", "", "", pflag.PathMustExist()) }, sub, ""},
+		{"path missing", func(f *pflag.FlagSet) { f.Path("p", "", "", pflag.PathMustExist()) }, missing, "does not exist"},
+		{"path must not exist", func(f *pflag.FlagSet) { f.Path("p", "", "", pflag.PathMustNotExist()) }, file, "already exists"},
+		{"path new", func(f *pflag.FlagSet) { f.Path("p", "", "", pflag.PathMustNotExist(), pflag.PathWritable()) }, missing, ""},
+		{"path regular", func(f *pflag.FlagSet) { f.Path("p", "", "", pflag.PathRegularFile()) }, sub, "is a directory, not a file"},
+		{"path directory", func(f *pflag.FlagSet) { f.Path("p", "", "", pflag.PathDirectory()) }, file, "is not a directory"},
+		{"file", func(f *pflag.FlagSet) { f.File("p", "", "", pflag.PathReadable(), pflag.PathWritable()) }, file, ""},
+		{"file is dir", func(f *pflag.FlagSet) { f.File("p", "", "") }, sub, "is a directory, not a file"},
+		{"file missing", func(f *pflag.FlagSet) { f.File("p", "", "") }, missing, ""},
+		{"file extension", func(f *pflag.FlagSet) { f.File("p", "", "", pflag.PathExtensions("yaml", ".yml")) }, filepath.Join(dir, "notes.txt"), "must have extension yaml, .yml"},
+		{"file extension case", func(f *pflag.FlagSet) { f.File("p", "", "", pflag.PathExtensions("YAML")) }, file, ""},
+		{"dir", func(f *pflag.FlagSet) { f.Dir("p", "", "", pflag.PathMustExist(), pflag.PathWritable()) }, sub, ""},
+		{"dir is file", func(f *pflag.FlagSet) { f.Dir("p", "", "") }, file, "is not a directory"},
+		{"dir in missing parent", func(f *pflag.FlagSet) { f.Dir("p", "", "", pflag.PathWritable()) }, filepath.Join(dir, "x", "y"), "is not writable"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			tc.def(f)
+
+			err := f.Parse([]string{"--p", tc.value})
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				require.Contains(t, err.Error(), `"--p"`)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.value, f.Lookup("p").Value.String())
+		})
+	}
+}
+
+func TestPathChecksCreateNothing(t *testing.T) {
+	dir := setUpPathDir(t)
+	before, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Dir("dir", "", "", pflag.PathWritable())
+	f.Path("out", "", "", pflag.PathWritable())
+	require.NoError(t, f.Parse([]string{"--dir", dir, "--out", filepath.Join(dir, "out.csv")}))
+
+	after, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
+
+func TestPathExpansion(t *testing.T) {
+	dir := setUpPathDir(t)
+	t.Setenv("HOME", dir)
+	t.Setenv("APP_ENV", "prod")
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	config := f.FileP("config", "c", "~/app.yaml", "config file", pflag.PathExpandUser(), pflag.PathMustExist())
+	out := f.Path("out", "", "output", pflag.PathExpandEnv(), pflag.PathAbsolute("/srv"))
+	data := f.Dir("data", "", "data dir", pflag.PathAbsolute(""))
+
+	require.NoError(t, f.Parse([]string{}))
+	require.Equal(t, "~/app.yaml", *config)
+
+	require.NoError(t, f.Parse([]string{"-c", "~/app.yaml", "--out", "reports/$APP_ENV/../${APP_ENV}.csv", "--data", "data"}))
+	require.Equal(t, filepath.Join(dir, "app.yaml"), *config)
+	require.Equal(t, "/srv/reports/prod.csv", *out)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(wd, "data"), *data)
+
+	got, err := f.GetFile("config")
+	require.NoError(t, err)
+	require.Equal(t, *config, got)
+
+	got, err = f.GetPath("out")
+	require.NoError(t, err)
+	require.Equal(t, *out, got)
+
+	_, err = f.GetDir("config")
+	require.Error(t, err)
+}
+
+func TestPathSliceGlob(t *testing.T) {
+	dir := setUpPathDir(t)
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	files := f.FileSlice("files", nil, "input files", pflag.PathGlob(), pflag.PathAbsolute(dir))
+	dirs := f.DirSlice("dirs", nil, "dirs", pflag.PathGlob(), pflag.PathMustExist())
+	paths := f.PathSlice("paths", nil, "paths")
+
+	require.NoError(t, f.Parse([]string{"--files", "*.yaml,notes.txt", "--files=new-*.json", "--dirs", filepath.Join(dir, "d*"), "--paths=*.go"}))
+	require.Equal(t, []string{
+		filepath.Join(dir, "app.yaml"),
+		filepath.Join(dir, "b.yaml"),
+		filepath.Join(dir, "notes.txt"),
+		filepath.Join(dir, "new-*.json"),
+	}, *files)
+	require.Equal(t, []string{filepath.Join(dir, "data")}, *dirs)
+	require.Equal(t, []string{"*.go"}, *paths)
+
+	got, err := f.GetFileSlice("files")
+	require.NoError(t, err)
+	require.Equal(t, *files, got)
+
+	gotDirs, err := f.GetDirSlice("dirs")
+	require.NoError(t, err)
+	require.Equal(t, *dirs, gotDirs)
+
+	gotPaths, err := f.GetPathSlice("paths")
+	require.NoError(t, err)
+	require.Equal(t, *paths, gotPaths)
+
+	err = f.Parse([]string{"--dirs", filepath.Join(dir, "x*")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no paths match")
+
+	err = f.Parse([]string{"--files", "d*"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is a directory")
+
+	sv, ok := f.Lookup("files").Value.(pflag.SliceValue)
+	require.True(t, ok)
+	require.NoError(t, sv.Replace([]string{"b.*"}))
+	require.Equal(t, []string{filepath.Join(dir, "b.yaml")}, sv.GetSlice())
+	require.Error(t, sv.Append("data"))
+}
+
+func TestPathCompletionAnnotations(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.File("config", "", "config file", pflag.PathExtensions("yaml", "yml"))
+	f.Dir("data", "", "data dir")
+	f.PathSlice("paths", nil, "paths")
+
+	require.Equal(t, []string{"yaml", "yml"}, f.Lookup("config").Annotations[pflag.CompletionFilenameExt])
+	require.Contains(t, f.Lookup("data").Annotations, pflag.CompletionDirectory)
+	require.Contains(t, f.Lookup("paths").Annotations, pflag.CompletionFilenameExt)
+
+	usages := f.FlagUsages()
+	require.Contains(t, usages, "--config file ")
+	require.Contains(t, usages, "--data dir ")
+	require.Contains(t, usages, "--paths pathSlice ")
+}
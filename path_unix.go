@@ -0,0 +1,22 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package pflag
+
+import "syscall"
+
+// The modes of access(2).
+const (
+	accessWrite = 0x2
+	accessRead  = 0x4
+)
+
+// checkAccess reports whether path can be written, or read, with access(2),
+// so nothing is opened or created.
+func checkAccess(path string, write bool) error {
+	mode := uint32(accessRead)
+	if write {
+		mode = accessWrite
+	}
+	return syscall.Access(path, mode)
+}
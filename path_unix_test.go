@@ -0,0 +1,24 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package pflag_test
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathChecksFIFO(t *testing.T) {
+	fifo := filepath.Join(t.TempDir(), "fifo")
+	require.NoError(t, syscall.Mkfifo(fifo, 0o600))
+
+	// the checks must not open the FIFO, which blocks without a peer
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	path := f.Path("p", "", "", pflag.PathMustExist(), pflag.PathReadable(), pflag.PathWritable())
+	require.NoError(t, f.Parse([]string{"--p", fifo}))
+	require.Equal(t, fifo, *path)
+}
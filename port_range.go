@@ -0,0 +1,94 @@
+package pflag
+
+// -- portRange Value
+type portRangeValue struct {
+	value  *PortInterval
+	bounds PortInterval
+}
+
+func newPortRangeValue(val PortInterval, p *PortInterval, bounds []PortInterval) *portRangeValue {
+	*p = val
+	return &portRangeValue{value: p, bounds: portRangeBounds(bounds)}
+}
+
+// Set parses s as min-max, min..max or a single port, an empty string sets
+// the zero PortInterval.
+func (r *portRangeValue) Set(s string) error {
+	v, err := parsePortRange(s, r.bounds)
+	if err != nil {
+		return err
+	}
+	*r.value = v
+	return nil
+}
+
+func (r *portRangeValue) Type() string {
+	return "portRange"
+}
+
+func (r *portRangeValue) String() string { return r.value.String() }
+
+func portRangeConv(sval string) (interface{}, error) {
+	return parsePortRange(sval, portNumbers)
+}
+
+// GetPortRange return the PortInterval value of a flag with the given name
+func (f *FlagSet) GetPortRange(name string) (PortInterval, error) {
+	val, err := f.getFlagType(name, "portRange", portRangeConv)
+	if err != nil {
+		return PortInterval{}, err
+	}
+	return val.(PortInterval), nil
+}
+
+// PortRangeVar defines a PortRange flag with specified name, default value, and usage string.
+// The argument p points to a PortInterval variable in which to store the value of the flag.
+// Values are given as min-max, min..max or a single port, between 1 and 65535 or within
+// bounds when it is given.
+func (f *FlagSet) PortRangeVar(p *PortInterval, name string, value PortInterval, usage string, bounds ...PortInterval) {
+	f.VarP(newPortRangeValue(value, p, bounds), name, "", usage)
+}
+
+// PortRangeVarP is like PortRangeVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PortRangeVarP(p *PortInterval, name, shorthand string, value PortInterval, usage string, bounds ...PortInterval) {
+	f.VarP(newPortRangeValue(value, p, bounds), name, shorthand, usage)
+}
+
+// PortRangeVar defines a PortRange flag with specified name, default value, and usage string.
+// The argument p points to a PortInterval variable in which to store the value of the flag.
+// Values are given as min-max, min..max or a single port, between 1 and 65535 or within
+// bounds when it is given.
+func PortRangeVar(p *PortInterval, name string, value PortInterval, usage string, bounds ...PortInterval) {
+	CommandLine.VarP(newPortRangeValue(value, p, bounds), name, "", usage)
+}
+
+// PortRangeVarP is like PortRangeVar, but accepts a shorthand letter that can be used after a single dash.
+func PortRangeVarP(p *PortInterval, name, shorthand string, value PortInterval, usage string, bounds ...PortInterval) {
+	CommandLine.VarP(newPortRangeValue(value, p, bounds), name, shorthand, usage)
+}
+
+// PortRange defines a PortRange flag with specified name, default value, and usage string.
+// The return value is the address of a PortInterval variable that stores the value of the flag.
+func (f *FlagSet) PortRange(name string, value PortInterval, usage string, bounds ...PortInterval) *PortInterval {
+	p := new(PortInterval)
+	f.PortRangeVarP(p, name, "", value, usage, bounds...)
+	return p
+}
+
+// PortRangeP is like PortRange, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PortRangeP(name, shorthand string, value PortInterval, usage string, bounds ...PortInterval) *PortInterval {
+	p := new(PortInterval)
+	f.PortRangeVarP(p, name, shorthand, value, usage, bounds...)
+	return p
+}
+
+// PortRange defines a PortRange flag with specified name, default value, and usage string.
+// The return value is the address of a PortInterval variable that stores the value of the flag.
+func PortRange(name string, value PortInterval, usage string, bounds ...PortInterval) *PortInterval {
+	return CommandLine.PortRangeP(name, "", value, usage, bounds...)
+}
+
+// PortRangeP is like PortRange, but accepts a shorthand letter that can be used after a single dash.
+func PortRangeP(name, shorthand string, value PortInterval, usage string, bounds ...PortInterval) *PortInterval {
+	return CommandLine.PortRangeP(name, shorthand, value, usage, bounds...)
+}
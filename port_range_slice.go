@@ -0,0 +1,160 @@
+package pflag
+
+import (
+	"strings"
+)
+
+// -- portRangeSlice Value
+type portRangeSliceValue struct {
+	value   *[]PortInterval
+	bounds  PortInterval
+	changed bool
+}
+
+func newPortRangeSliceValue(val []PortInterval, p *[]PortInterval, bounds []PortInterval) *portRangeSliceValue {
+	rsv := &portRangeSliceValue{value: p, bounds: portRangeBounds(bounds)}
+	*rsv.value = val
+	return rsv
+}
+
+func (s *portRangeSliceValue) Set(val string) error {
+	ss := strings.Split(val, ",")
+	out := make([]PortInterval, len(ss))
+	for i, d := range ss {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+	s.changed = true
+	return nil
+}
+
+func (s *portRangeSliceValue) Type() string {
+	return "portRangeSlice"
+}
+
+func (s *portRangeSliceValue) String() string {
+	return "[" + strings.Join(s.GetSlice(), ",") + "]"
+}
+
+func (s *portRangeSliceValue) fromString(val string) (PortInterval, error) {
+	return parsePortInterval(val, s.bounds)
+}
+
+func (s *portRangeSliceValue) toString(val PortInterval) string {
+	return val.String()
+}
+
+func (s *portRangeSliceValue) Append(val string) error {
+	r, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, r)
+	return nil
+}
+
+func (s *portRangeSliceValue) Replace(val []string) error {
+	out := make([]PortInterval, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *portRangeSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+func portRangeSliceConv(val string) (interface{}, error) {
+	val = strings.Trim(val, "[]")
+	// Empty string would cause a slice with one (empty) entry
+	if len(val) == 0 {
+		return []PortInterval{}, nil
+	}
+	ss := strings.Split(val, ",")
+	out := make([]PortInterval, len(ss))
+	for i, d := range ss {
+		var err error
+		out[i], err = parsePortInterval(d, portNumbers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// GetPortRangeSlice returns the []PortInterval value of a portRangeSlice flag with the given name
+func (f *FlagSet) GetPortRangeSlice(name string) ([]PortInterval, error) {
+	val, err := f.getFlagType(name, "portRangeSlice", portRangeSliceConv)
+	if err != nil {
+		return []PortInterval{}, err
+	}
+	return val.([]PortInterval), nil
+}
+
+// PortRangeSliceVar defines a portRangeSlice flag with specified name, default value, and usage string.
+// The argument p points to a []PortInterval variable in which to store the value of the flag.
+// Each range is given as min-max, min..max or a single port, within bounds when it is given.
+func (f *FlagSet) PortRangeSliceVar(p *[]PortInterval, name string, value []PortInterval, usage string, bounds ...PortInterval) {
+	f.VarP(newPortRangeSliceValue(value, p, bounds), name, "", usage)
+}
+
+// PortRangeSliceVarP is like PortRangeSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PortRangeSliceVarP(p *[]PortInterval, name, shorthand string, value []PortInterval, usage string, bounds ...PortInterval) {
+	f.VarP(newPortRangeSliceValue(value, p, bounds), name, shorthand, usage)
+}
+
+// PortRangeSliceVar defines a portRangeSlice flag with specified name, default value, and usage string.
+// The argument p points to a []PortInterval variable in which to store the value of the flag.
+// Each range is given as min-max, min..max or a single port, within bounds when it is given.
+func PortRangeSliceVar(p *[]PortInterval, name string, value []PortInterval, usage string, bounds ...PortInterval) {
+	CommandLine.VarP(newPortRangeSliceValue(value, p, bounds), name, "", usage)
+}
+
+// PortRangeSliceVarP is like PortRangeSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func PortRangeSliceVarP(p *[]PortInterval, name, shorthand string, value []PortInterval, usage string, bounds ...PortInterval) {
+	CommandLine.VarP(newPortRangeSliceValue(value, p, bounds), name, shorthand, usage)
+}
+
+// PortRangeSlice defines a portRangeSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []PortInterval variable that stores the value of the flag.
+func (f *FlagSet) PortRangeSlice(name string, value []PortInterval, usage string, bounds ...PortInterval) *[]PortInterval {
+	p := []PortInterval{}
+	f.PortRangeSliceVarP(&p, name, "", value, usage, bounds...)
+	return &p
+}
+
+// PortRangeSliceP is like PortRangeSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) PortRangeSliceP(name, shorthand string, value []PortInterval, usage string, bounds ...PortInterval) *[]PortInterval {
+	p := []PortInterval{}
+	f.PortRangeSliceVarP(&p, name, shorthand, value, usage, bounds...)
+	return &p
+}
+
+// PortRangeSlice defines a portRangeSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []PortInterval variable that stores the value of the flag.
+func PortRangeSlice(name string, value []PortInterval, usage string, bounds ...PortInterval) *[]PortInterval {
+	return CommandLine.PortRangeSliceP(name, "", value, usage, bounds...)
+}
+
+// PortRangeSliceP is like PortRangeSlice, but accepts a shorthand letter that can be used after a single dash.
+func PortRangeSliceP(name, shorthand string, value []PortInterval, usage string, bounds ...PortInterval) *[]PortInterval {
+	return CommandLine.PortRangeSliceP(name, shorthand, value, usage, bounds...)
+}
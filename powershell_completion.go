@@ -0,0 +1,153 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GenPowerShellCompletion writes a PowerShell completion script for the
+// flags in the FlagSet to w.
+func (f *FlagSet) GenPowerShellCompletion(w io.Writer) error {
+	program := f.completionProgram()
+
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, "# powershell completion for %s\n\n", program)
+	_, _ = fmt.Fprintf(buf, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", psQuote(program))
+	buf.WriteString("    param($WordToComplete, $CommandAst, $CursorPosition)\n\n")
+	buf.WriteString("    $flags = @(\n")
+	for _, cf := range f.completionFlags() {
+		names := []string{psQuote("--" + cf.name)}
+		if cf.short != "" {
+			names = append(names, psQuote("-"+cf.short))
+		}
+
+		kind := "none"
+		if cf.takesValue {
+			switch {
+			case len(cf.values) > 0:
+				kind = "values"
+			case cf.dirs:
+				kind = "dirs"
+			case cf.files:
+				kind = "files"
+			default:
+				kind = "value"
+			}
+		}
+
+		values := make([]string, len(cf.values))
+		for i, v := range cf.values {
+			values[i] = psQuote(v)
+		}
+		exts := make([]string, len(cf.extensions))
+		for i, ext := range cf.extensions {
+			exts[i] = psQuote("." + ext)
+		}
+
+		_, _ = fmt.Fprintf(buf, "        @{ Names = @(%s); Description = %s; Kind = '%s'; Values = @(%s); Extensions = @(%s) }\n",
+			strings.Join(names, ", "), psQuote(cf.usage), kind, strings.Join(values, ", "), strings.Join(exts, ", "))
+	}
+	buf.WriteString("    )\n\n")
+	buf.WriteString(`    $words = @($CommandAst.CommandElements |
+        Where-Object { $_.Extent.EndOffset -le $CursorPosition } |
+        ForEach-Object { $_.ToString() })
+    if ($WordToComplete -ne '' -and $words.Count -gt 0) {
+        $words = @($words | Select-Object -SkipLast 1)
+    }
+    $prev = if ($words.Count -gt 1) { $words[-1] } else { '' }
+
+    $flag = $flags | Where-Object { $_.Names -contains $prev } | Select-Object -First 1
+    if ($flag -and $flag.Kind -ne 'none') {
+        switch ($flag.Kind) {
+            'values' {
+                $flag.Values | Where-Object { $_ -like "$WordToComplete*" } | ForEach-Object {
+                    [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+                }
+            }
+            'dirs' {
+                Get-ChildItem -Directory -Path "$WordToComplete*" -ErrorAction SilentlyContinue | ForEach-Object {
+                    [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ProviderContainer', $_.Name)
+                }
+            }
+            'files' {
+                Get-ChildItem -Path "$WordToComplete*" -ErrorAction SilentlyContinue |
+                    Where-Object { $_.PSIsContainer -or $flag.Extensions.Count -eq 0 -or $flag.Extensions -contains $_.Extension } |
+                    ForEach-Object {
+                        [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ProviderItem', $_.Name)
+                    }
+            }
+        }
+        return
+    }
+
+    if ($WordToComplete -like '-*') {
+        foreach ($f in $flags) {
+            foreach ($name in $f.Names) {
+                if ($name -like "$WordToComplete*") {
+                    $tip = if ($f.Description) { $f.Description } else { $name }
+                    [System.Management.Automation.CompletionResult]::new($name, $name, 'ParameterName', $tip)
+                }
+            }
+        }
+    }
+}
+`)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// psQuote quotes s as a PowerShell single quoted string.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// GenPowerShellDynamicCompletion writes a PowerShell completion script to w
+// that asks the program itself for candidates through CompleteCommand.
+func (f *FlagSet) GenPowerShellDynamicCompletion(w io.Writer) error {
+	program := f.completionProgram()
+
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, "# powershell completion for %s\n\n", program)
+	_, _ = fmt.Fprintf(buf, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", psQuote(program))
+	_, _ = fmt.Fprintf(buf, `    param($WordToComplete, $CommandAst, $CursorPosition)
+
+    $words = @($CommandAst.CommandElements |
+        Where-Object { $_.Extent.StartOffset -lt $CursorPosition } |
+        ForEach-Object { $_.ToString() })
+    $program = $words[0]
+    $rest = @($words | Select-Object -Skip 1)
+    if ($WordToComplete -eq '') {
+        $rest += '""'
+    }
+
+    $out = @(& $program %s @rest 2>$null)
+    if ($out.Count -eq 0) {
+        return
+    }
+    $directive = [int]($out[-1].TrimStart(':'))
+    $out = @($out | Select-Object -SkipLast 1)
+
+    if ($directive -band %d) {
+        return
+    }
+    if ($directive -band (%d -bor %d)) {
+        # leave file and directory names to the default completion
+        return
+    }
+
+    foreach ($line in $out) {
+        $value, $description = $line -split "`+"`t"+`", 2
+        if (-not $description) {
+            $description = $value
+        }
+        [System.Management.Automation.CompletionResult]::new($value, $value, 'ParameterValue', $description)
+    }
+}
+`, CompleteCommand, CompletionError, CompletionFilterDirs, CompletionFilterFileExt)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,199 @@
+package pflag
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IntInterval is an inclusive range of integers, as given to IntRange flags.
+type IntInterval struct {
+	Min int
+	Max int
+}
+
+// Contains reports whether n lies in the range.
+func (r IntInterval) Contains(n int) bool {
+	return r.Min <= n && n <= r.Max
+}
+
+// Iter calls fn for every integer of the range in ascending order, until fn
+// returns false.
+func (r IntInterval) Iter(fn func(n int) bool) {
+	if r.Min > r.Max {
+		return
+	}
+	for n := r.Min; ; n++ {
+		if !fn(n) || n == r.Max {
+			return
+		}
+	}
+}
+
+// String formats the range as min-max, or as a single value when min and
+// max are equal.
+func (r IntInterval) String() string {
+	if r.Min == r.Max {
+		return strconv.Itoa(r.Min)
+	}
+	return strconv.Itoa(r.Min) + "-" + strconv.Itoa(r.Max)
+}
+
+// MergeIntIntervals returns the union of ranges as ranges that are sorted and
+// neither overlap nor touch, so 1-3, 2-5 and 6 become 1-6.
+func MergeIntIntervals(ranges ...IntInterval) []IntInterval {
+	sorted := make([]IntInterval, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Min <= r.Max {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Min < sorted[j].Min })
+
+	var out []IntInterval
+	for _, r := range sorted {
+		if n := len(out); n > 0 && (out[n-1].Max == math.MaxInt || r.Min <= out[n-1].Max+1) {
+			if r.Max > out[n-1].Max {
+				out[n-1].Max = r.Max
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// PortInterval is an inclusive range of ports, as given to PortRange flags.
+// The zero PortInterval is no range: it contains no port and is written as
+// an empty string, so port 0 can only be given as the start of a range.
+type PortInterval struct {
+	Min uint16
+	Max uint16
+}
+
+// Contains reports whether port lies in the range.
+func (r PortInterval) Contains(port uint16) bool {
+	return r != (PortInterval{}) && r.Min <= port && port <= r.Max
+}
+
+// Iter calls fn for every port of the range in ascending order, until fn
+// returns false.
+func (r PortInterval) Iter(fn func(port uint16) bool) {
+	if r == (PortInterval{}) {
+		return
+	}
+	r.interval().Iter(func(n int) bool { return fn(uint16(n)) })
+}
+
+// String formats the range as min-max, or as a single port when min and max
+// are equal. The zero PortInterval is an empty string.
+func (r PortInterval) String() string {
+	if r == (PortInterval{}) {
+		return ""
+	}
+	return r.interval().String()
+}
+
+func (r PortInterval) interval() IntInterval {
+	return IntInterval{Min: int(r.Min), Max: int(r.Max)}
+}
+
+// MergePortIntervals returns the union of ranges as ranges that are sorted and
+// neither overlap nor touch.
+func MergePortIntervals(ranges ...PortInterval) []PortInterval {
+	in := make([]IntInterval, 0, len(ranges))
+	for _, r := range ranges {
+		if r != (PortInterval{}) {
+			in = append(in, r.interval())
+		}
+	}
+	merged := MergeIntIntervals(in...)
+	out := make([]PortInterval, len(merged))
+	for i, r := range merged {
+		out[i] = PortInterval{Min: uint16(r.Min), Max: uint16(r.Max)}
+	}
+	return out
+}
+
+// allInts are the bounds of IntRange flags without bounds of their own.
+var allInts = IntInterval{Min: math.MinInt, Max: math.MaxInt}
+
+// allPorts are the bounds of PortRange flags without bounds of their own.
+var allPorts = PortInterval{Min: 1, Max: 65535}
+
+// portNumbers are all the values a PortInterval can hold, the getters read
+// flags with them as the flag checked its own bounds already.
+var portNumbers = PortInterval{Min: 0, Max: math.MaxUint16}
+
+// parseRange parses min-max, min..max or a single value into a range that
+// lies within bounds. Negative numbers are accepted, as in -10--1.
+func parseRange(s string, bounds IntInterval) (IntInterval, error) {
+	s = strings.TrimSpace(s)
+	lo, hi := s, s
+	if i := strings.Index(s, ".."); i >= 0 {
+		lo, hi = s[:i], s[i+2:]
+	} else if i := strings.IndexByte(s, '-'); i >= 0 {
+		if j := strings.IndexByte(s[i+1:], '-'); i == 0 && j >= 0 {
+			i += j + 1
+		}
+		if i > 0 {
+			lo, hi = s[:i], s[i+1:]
+		}
+	}
+
+	from, err := strconv.ParseInt(strings.TrimSpace(lo), 10, strconv.IntSize)
+	if err != nil {
+		return IntInterval{}, fmt.Errorf("invalid range %q, must be a number or a range such as 1-10 or 1..10", s)
+	}
+	to, err := strconv.ParseInt(strings.TrimSpace(hi), 10, strconv.IntSize)
+	if err != nil {
+		return IntInterval{}, fmt.Errorf("invalid range %q, must be a number or a range such as 1-10 or 1..10", s)
+	}
+
+	r := IntInterval{Min: int(from), Max: int(to)}
+	if r.Min > r.Max {
+		return IntInterval{}, fmt.Errorf("invalid range %q, %d is greater than %d", s, r.Min, r.Max)
+	}
+	if r.Min < bounds.Min || r.Max > bounds.Max {
+		return IntInterval{}, fmt.Errorf("range %q is out of bounds, must be within %s", s, bounds)
+	}
+	return r, nil
+}
+
+// parsePortRange parses s like parseRange, an empty string is the zero
+// PortInterval.
+func parsePortRange(s string, bounds PortInterval) (PortInterval, error) {
+	if strings.TrimSpace(s) == "" {
+		return PortInterval{}, nil
+	}
+	return parsePortInterval(s, bounds)
+}
+
+// parsePortInterval parses s like parseRange. Port 0 alone is rejected, as
+// it is the zero PortInterval.
+func parsePortInterval(s string, bounds PortInterval) (PortInterval, error) {
+	r, err := parseRange(s, bounds.interval())
+	if err != nil {
+		return PortInterval{}, err
+	}
+	if r == (IntInterval{}) {
+		return PortInterval{}, fmt.Errorf("invalid range %q, port 0 alone is no range", strings.TrimSpace(s))
+	}
+	return PortInterval{Min: uint16(r.Min), Max: uint16(r.Max)}, nil
+}
+
+func intRangeBounds(bounds []IntInterval) IntInterval {
+	if len(bounds) == 0 {
+		return allInts
+	}
+	return bounds[len(bounds)-1]
+}
+
+func portRangeBounds(bounds []PortInterval) PortInterval {
+	if len(bounds) == 0 {
+		return allPorts
+	}
+	return bounds[len(bounds)-1]
+}
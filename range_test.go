@@ -0,0 +1,228 @@
+package pflag_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntRange(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected pflag.IntInterval
+		err      string
+	}{
+		{"1-10", pflag.IntInterval{Min: 1, Max: 10}, ""},
+		{"1..10", pflag.IntInterval{Min: 1, Max: 10}, ""},
+		{" 3 ", pflag.IntInterval{Min: 3, Max: 3}, ""},
+		{"-5", pflag.IntInterval{Min: -5, Max: -5}, ""},
+		{"-10--1", pflag.IntInterval{Min: -10, Max: -1}, ""},
+		{"-10-5", pflag.IntInterval{Min: -10, Max: 5}, ""},
+		{"-10..-1", pflag.IntInterval{Min: -10, Max: -1}, ""},
+		{"10-1", pflag.IntInterval{}, `invalid range "10-1", 10 is greater than 1`},
+		{"1-", pflag.IntInterval{}, `invalid range "1-"`},
+		{"a..b", pflag.IntInterval{}, `invalid range "a..b"`},
+		{"", pflag.IntInterval{}, `invalid range ""`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			shards := f.IntRangeP("shards", "s", pflag.IntInterval{}, "shards to serve")
+
+			err := f.Parse([]string{"--shards", tc.value})
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				require.Contains(t, err.Error(), "--shards")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, *shards)
+
+			got, err := f.GetIntRange("shards")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestIntRangeBounds(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	shards := f.IntRange("shards", pflag.IntInterval{Min: 0, Max: 3}, "shards to serve", pflag.IntInterval{Min: 0, Max: 15})
+
+	require.Contains(t, f.FlagUsages(), "--shards intRange   shards to serve (default 0-3)\n")
+	require.NoError(t, f.Parse([]string{"--shards=4..15"}))
+	require.Equal(t, pflag.IntInterval{Min: 4, Max: 15}, *shards)
+
+	err := f.Parse([]string{"--shards=8-16"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `range "8-16" is out of bounds, must be within 0-15`)
+
+	_, err = f.GetPortRange("shards")
+	require.Error(t, err)
+}
+
+func TestPortRange(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected pflag.PortInterval
+		err      string
+	}{
+		{"8000-8100", pflag.PortInterval{Min: 8000, Max: 8100}, ""},
+		{"8000..8100", pflag.PortInterval{Min: 8000, Max: 8100}, ""},
+		{"443", pflag.PortInterval{Min: 443, Max: 443}, ""},
+		{"1-65535", pflag.PortInterval{Min: 1, Max: 65535}, ""},
+		{"", pflag.PortInterval{}, ""},
+		{"0-10", pflag.PortInterval{}, "out of bounds, must be within 1-65535"},
+		{"65000-65536", pflag.PortInterval{}, "out of bounds, must be within 1-65535"},
+		{"9000-8000", pflag.PortInterval{}, "9000 is greater than 8000"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			ports := f.PortRange("ports", pflag.PortInterval{Min: 1, Max: 2}, "ports to allocate")
+
+			err := f.Parse([]string{"--ports", tc.value})
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, *ports)
+
+			got, err := f.GetPortRange("ports")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.PortRange("ports", pflag.PortInterval{}, "ports to allocate", pflag.PortInterval{Min: 1024, Max: 65535})
+	f.PortRange("admin", pflag.PortInterval{Min: 9000, Max: 9000}, "admin port")
+	require.Error(t, f.Parse([]string{"--ports", "80"}))
+
+	usages := f.FlagUsages()
+	require.Contains(t, usages, "ports to allocate\n")
+	require.Contains(t, usages, "admin port (default 9000)\n")
+}
+
+func TestPortRangeOwnBounds(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.PortRange("ports", pflag.PortInterval{}, "ports to allocate", pflag.PortInterval{Min: 0, Max: 1023})
+	f.PortRangeSlice("more", nil, "more ports", pflag.PortInterval{Min: 0, Max: 1023})
+
+	require.NoError(t, f.Parse([]string{"--ports", "0-10", "--more", "0-10,80"}))
+
+	got, err := f.GetPortRange("ports")
+	require.NoError(t, err)
+	require.Equal(t, pflag.PortInterval{Min: 0, Max: 10}, got)
+	require.Equal(t, "0-10", f.Lookup("ports").Value.String())
+
+	gotSlice, err := f.GetPortRangeSlice("more")
+	require.NoError(t, err)
+	require.Equal(t, []pflag.PortInterval{{Min: 0, Max: 10}, {Min: 80, Max: 80}}, gotSlice)
+
+	err = f.Parse([]string{"--ports", "0"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid range "0", port 0 alone is no range`)
+	require.Error(t, f.Parse([]string{"--more", "0"}))
+}
+
+func TestRangeHelpers(t *testing.T) {
+	r := pflag.IntInterval{Min: -1, Max: 2}
+	require.True(t, r.Contains(-1))
+	require.True(t, r.Contains(2))
+	require.False(t, r.Contains(3))
+
+	var got []int
+	r.Iter(func(n int) bool {
+		got = append(got, n)
+		return true
+	})
+	require.Equal(t, []int{-1, 0, 1, 2}, got)
+
+	got = nil
+	r.Iter(func(n int) bool {
+		got = append(got, n)
+		return n < 0
+	})
+	require.Equal(t, []int{-1, 0}, got)
+
+	got = nil
+	pflag.IntInterval{Min: math.MaxInt - 1, Max: math.MaxInt}.Iter(func(n int) bool {
+		got = append(got, n)
+		return true
+	})
+	require.Equal(t, []int{math.MaxInt - 1, math.MaxInt}, got)
+
+	var ports []uint16
+	pflag.PortInterval{Min: 65534, Max: 65535}.Iter(func(port uint16) bool {
+		ports = append(ports, port)
+		return true
+	})
+	require.Equal(t, []uint16{65534, 65535}, ports)
+	require.True(t, pflag.PortInterval{Min: 80, Max: 90}.Contains(85))
+	require.Equal(t, "", pflag.PortInterval{}.String())
+	require.False(t, pflag.PortInterval{}.Contains(0))
+	pflag.PortInterval{}.Iter(func(port uint16) bool {
+		ports = append(ports, port)
+		return true
+	})
+	require.Equal(t, []uint16{65534, 65535}, ports)
+
+	require.Equal(t, []pflag.IntInterval{{Min: 1, Max: 6}, {Min: 8, Max: 10}}, pflag.MergeIntIntervals(
+		pflag.IntInterval{Min: 8, Max: 9},
+		pflag.IntInterval{Min: 2, Max: 5},
+		pflag.IntInterval{Min: 1, Max: 3},
+		pflag.IntInterval{Min: 6, Max: 6},
+		pflag.IntInterval{Min: 9, Max: 10},
+		pflag.IntInterval{Min: 5, Max: 4},
+	))
+	require.Equal(t, []pflag.IntInterval{{Min: 0, Max: math.MaxInt}}, pflag.MergeIntIntervals(
+		pflag.IntInterval{Min: 5, Max: math.MaxInt},
+		pflag.IntInterval{Min: 0, Max: 10},
+		pflag.IntInterval{Min: math.MaxInt, Max: math.MaxInt},
+	))
+	require.Nil(t, pflag.MergeIntIntervals())
+
+	require.Equal(t, []pflag.PortInterval{{Min: 8000, Max: 8200}}, pflag.MergePortIntervals(
+		pflag.PortInterval{},
+		pflag.PortInterval{Min: 8101, Max: 8200},
+		pflag.PortInterval{Min: 8000, Max: 8100},
+	))
+}
+
+func TestRangeSlices(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	shards := f.IntRangeSliceP("shards", "s", nil, "shards to serve", pflag.IntInterval{Min: 0, Max: 63})
+	ports := f.PortRangeSlice("ports", []pflag.PortInterval{{Min: 80, Max: 80}}, "ports to allocate")
+
+	require.Contains(t, f.FlagUsages(), "ports to allocate (default [80])\n")
+	require.NoError(t, f.Parse([]string{"-s", "0-7,16..23", "--shards=40", "--ports", "8000-8100,9000"}))
+	require.Equal(t, []pflag.IntInterval{{Min: 0, Max: 7}, {Min: 16, Max: 23}, {Min: 40, Max: 40}}, *shards)
+	require.Equal(t, []pflag.PortInterval{{Min: 8000, Max: 8100}, {Min: 9000, Max: 9000}}, *ports)
+	require.Equal(t, "[0-7,16-23,40]", f.Lookup("shards").Value.String())
+
+	gotShards, err := f.GetIntRangeSlice("shards")
+	require.NoError(t, err)
+	require.Equal(t, *shards, gotShards)
+
+	gotPorts, err := f.GetPortRangeSlice("ports")
+	require.NoError(t, err)
+	require.Equal(t, *ports, gotPorts)
+
+	require.Error(t, f.Parse([]string{"--shards", "60-64"}))
+	require.Error(t, f.Parse([]string{"--ports", "8000,,9000"}))
+
+	sv, ok := f.Lookup("ports").Value.(pflag.SliceValue)
+	require.True(t, ok)
+	require.NoError(t, sv.Replace([]string{"1..2"}))
+	require.NoError(t, sv.Append("3"))
+	require.Equal(t, []string{"1-2", "3"}, sv.GetSlice())
+	require.Error(t, sv.Append("0"))
+}
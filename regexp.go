@@ -0,0 +1,324 @@
+package pflag
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rsb/failure"
+)
+
+// RegexpOption changes how the patterns of Regexp flags are compiled.
+// Options can be combined with |.
+type RegexpOption int
+
+const (
+	// RegexpAnchored matches the pattern against the whole input, as if it
+	// was written ^(?:pattern)$
+	RegexpAnchored RegexpOption = 1 << iota
+	// RegexpPOSIX restricts patterns to POSIX ERE syntax with
+	// leftmost-longest matching, like regexp.CompilePOSIX. As there, ^ and $
+	// match at line boundaries, so with RegexpAnchored the pattern is written
+	// ^(pattern)$ and matches a whole line, with a leading capture group.
+	RegexpPOSIX
+	// RegexpIgnoreCase matches case-insensitively, as if the pattern started
+	// with (?i)
+	RegexpIgnoreCase
+)
+
+func combineRegexpOptions(opts []RegexpOption) RegexpOption {
+	var out RegexpOption
+	for _, opt := range opts {
+		out |= opt
+	}
+	return out
+}
+
+const (
+	regexpIgnoreCasePrefix = "(?i)"
+	regexpAnchorPrefix     = "^(?:"
+	regexpAnchorSuffix     = ")$"
+	posixAnchorPrefix      = "^("
+	posixAnchorSuffix      = ")$"
+)
+
+// compileRegexp compiles pattern with the options. Syntax errors report the
+// position in pattern where they occur.
+func compileRegexp(pattern string, opts RegexpOption) (*regexp.Regexp, error) {
+	flags := syntax.Perl
+	if opts&RegexpPOSIX != 0 {
+		flags = syntax.POSIX
+	}
+	if _, err := syntax.Parse(pattern, flags); err != nil {
+		if syntaxErr, ok := err.(*syntax.Error); ok {
+			pos := strings.Index(pattern, syntaxErr.Expr)
+			if pos < 0 {
+				pos = 0
+			}
+			return nil, fmt.Errorf("invalid regexp %q at position %d: %s: `%s`", pattern, pos, syntaxErr.Code, syntaxErr.Expr)
+		}
+		return nil, fmt.Errorf("invalid regexp %q: %v", pattern, err)
+	}
+
+	// the pattern is valid, so the wrapped one is too
+	if opts&RegexpPOSIX != 0 {
+		re, err := regexp.CompilePOSIX(posixPattern(pattern, opts))
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp %q: %v", pattern, err)
+		}
+		return re, nil
+	}
+
+	wrapped := pattern
+	if opts&RegexpAnchored != 0 {
+		wrapped = regexpAnchorPrefix + wrapped + regexpAnchorSuffix
+	}
+	if opts&RegexpIgnoreCase != 0 {
+		wrapped = regexpIgnoreCasePrefix + wrapped
+	}
+	re, err := regexp.Compile(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regexp %q: %v", pattern, err)
+	}
+	return re, nil
+}
+
+// posixPattern applies the options to a valid POSIX pattern. POSIX syntax has
+// neither flags nor non-capturing groups, so case folding replaces literals
+// and bracket expressions with the classes they match, and anchoring wraps
+// the pattern in a group.
+func posixPattern(pattern string, opts RegexpOption) string {
+	if opts&RegexpIgnoreCase != 0 {
+		var b strings.Builder
+		for s := pattern; s != ""; {
+			n := posixAtomLen(s)
+			b.WriteString(foldPOSIXAtom(s[:n]))
+			s = s[n:]
+		}
+		pattern = b.String()
+	}
+	if opts&RegexpAnchored != 0 {
+		pattern = posixAnchorPrefix + pattern + posixAnchorSuffix
+	}
+	return pattern
+}
+
+// posixAtomLen returns the length of the escape, bracket expression or rune
+// s starts with.
+func posixAtomLen(s string) int {
+	switch s[0] {
+	case '\\':
+		return 1 + posixEscapeLen(s[1:])
+	case '[':
+		i := 1
+		if i < len(s) && s[i] == '^' {
+			i++
+		}
+		// ] is a literal as the first character of the class
+		for first := true; i < len(s); first = false {
+			switch {
+			case s[i] == ']' && !first:
+				return i + 1
+			case strings.HasPrefix(s[i:], "[:") && strings.Contains(s[i+2:], ":]"):
+				i += strings.Index(s[i+2:], ":]") + 4
+			case s[i] == '\\':
+				i += 1 + posixEscapeLen(s[i+1:])
+			default:
+				_, size := utf8.DecodeRuneInString(s[i:])
+				i += size
+			}
+		}
+		return len(s)
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return size
+}
+
+// posixEscapeLen returns the length of the escape s starts with, after the
+// backslash.
+func posixEscapeLen(s string) int {
+	if s == "" {
+		return 0
+	}
+	switch {
+	case s[0] == 'x' && len(s) > 1 && s[1] == '{':
+		return strings.IndexByte(s, '}') + 1
+	case s[0] == 'x':
+		return 3
+	case s[0] >= '0' && s[0] <= '7':
+		n := 1
+		for n < 3 && n < len(s) && s[n] >= '0' && s[n] <= '7' {
+			n++
+		}
+		return n
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return size
+}
+
+// foldPOSIXAtom returns a bracket expression of the runes atom matches with
+// case folding, or atom itself when folding does not change it.
+func foldPOSIXAtom(atom string) string {
+	if r, _ := utf8.DecodeRuneInString(atom); atom[0] != '\\' && atom[0] != '[' && unicode.SimpleFold(r) == r {
+		return atom
+	}
+	re, err := syntax.Parse(atom, syntax.POSIX|syntax.FoldCase)
+	if err != nil {
+		return atom
+	}
+
+	var ranges []rune
+	switch re.Op {
+	case syntax.OpLiteral:
+		if len(re.Rune) != 1 || re.Flags&syntax.FoldCase == 0 {
+			return atom
+		}
+		r := re.Rune[0]
+		ranges = append(ranges, r, r)
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			ranges = append(ranges, f, f)
+		}
+	case syntax.OpCharClass:
+		ranges = re.Rune
+	default:
+		return atom
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := 0; i < len(ranges); i += 2 {
+		_, _ = fmt.Fprintf(&b, `\x{%x}`, ranges[i])
+		if ranges[i+1] != ranges[i] {
+			_, _ = fmt.Fprintf(&b, `-\x{%x}`, ranges[i+1])
+		}
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// regexpPattern returns the pattern re was compiled from with the options.
+// The flags set the pattern they compiled aside, as folded POSIX patterns
+// can not be turned back.
+func regexpPattern(re *regexp.Regexp, opts RegexpOption) string {
+	if re == nil {
+		return ""
+	}
+	pattern := re.String()
+	prefix, suffix := regexpAnchorPrefix, regexpAnchorSuffix
+	if opts&RegexpPOSIX != 0 {
+		prefix, suffix = posixAnchorPrefix, posixAnchorSuffix
+	} else if opts&RegexpIgnoreCase != 0 {
+		pattern = strings.TrimPrefix(pattern, regexpIgnoreCasePrefix)
+	}
+	if opts&RegexpAnchored != 0 && strings.HasPrefix(pattern, prefix) && strings.HasSuffix(pattern, suffix) {
+		pattern = pattern[len(prefix) : len(pattern)-len(suffix)]
+	}
+	return pattern
+}
+
+// -- regexp.Regexp Value
+type regexpValue struct {
+	value    **regexp.Regexp
+	opts     RegexpOption
+	compiled *regexp.Regexp
+	given    string
+}
+
+func newRegexpValue(val *regexp.Regexp, p **regexp.Regexp, opts []RegexpOption) *regexpValue {
+	*p = val
+	return &regexpValue{value: p, opts: combineRegexpOptions(opts)}
+}
+
+// Set compiles s. An empty string sets a nil *regexp.Regexp.
+func (r *regexpValue) Set(s string) error {
+	if s == "" {
+		*r.value = nil
+		return nil
+	}
+	re, err := compileRegexp(s, r.opts)
+	if err != nil {
+		return err
+	}
+	*r.value, r.compiled, r.given = re, re, s
+	return nil
+}
+
+func (r *regexpValue) Type() string {
+	return "regexp"
+}
+
+// String returns the pattern as given, without the anchors and flags added
+// by the options.
+func (r *regexpValue) String() string {
+	if *r.value != nil && *r.value == r.compiled {
+		return r.given
+	}
+	return regexpPattern(*r.value, r.opts)
+}
+
+// GetRegexp return the *regexp.Regexp value of a flag with the given name
+func (f *FlagSet) GetRegexp(name string) (*regexp.Regexp, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return nil, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	r, ok := flag.Value.(*regexpValue)
+	if !ok {
+		return nil, failure.InvalidState("trying to get regexp value of flag of type %s", flag.Value.Type())
+	}
+	return *r.value, nil
+}
+
+// RegexpVar defines a *regexp.Regexp flag with specified name, default value, and usage string.
+// The argument p points to a *regexp.Regexp variable in which to store the value of the flag.
+// The pattern is compiled when the flag is set, so invalid patterns fail Parse.
+func (f *FlagSet) RegexpVar(p **regexp.Regexp, name string, value *regexp.Regexp, usage string, opts ...RegexpOption) {
+	f.VarP(newRegexpValue(value, p, opts), name, "", usage)
+}
+
+// RegexpVarP is like RegexpVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) RegexpVarP(p **regexp.Regexp, name, shorthand string, value *regexp.Regexp, usage string, opts ...RegexpOption) {
+	f.VarP(newRegexpValue(value, p, opts), name, shorthand, usage)
+}
+
+// RegexpVar defines a *regexp.Regexp flag with specified name, default value, and usage string.
+// The argument p points to a *regexp.Regexp variable in which to store the value of the flag.
+// The pattern is compiled when the flag is set, so invalid patterns fail Parse.
+func RegexpVar(p **regexp.Regexp, name string, value *regexp.Regexp, usage string, opts ...RegexpOption) {
+	CommandLine.VarP(newRegexpValue(value, p, opts), name, "", usage)
+}
+
+// RegexpVarP is like RegexpVar, but accepts a shorthand letter that can be used after a single dash.
+func RegexpVarP(p **regexp.Regexp, name, shorthand string, value *regexp.Regexp, usage string, opts ...RegexpOption) {
+	CommandLine.VarP(newRegexpValue(value, p, opts), name, shorthand, usage)
+}
+
+// Regexp defines a *regexp.Regexp flag with specified name, default value, and usage string.
+// The return value is the address of a *regexp.Regexp variable that stores the value of the flag.
+func (f *FlagSet) Regexp(name string, value *regexp.Regexp, usage string, opts ...RegexpOption) **regexp.Regexp {
+	p := new(*regexp.Regexp)
+	f.RegexpVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// RegexpP is like Regexp, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) RegexpP(name, shorthand string, value *regexp.Regexp, usage string, opts ...RegexpOption) **regexp.Regexp {
+	p := new(*regexp.Regexp)
+	f.RegexpVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// Regexp defines a *regexp.Regexp flag with specified name, default value, and usage string.
+// The return value is the address of a *regexp.Regexp variable that stores the value of the flag.
+func Regexp(name string, value *regexp.Regexp, usage string, opts ...RegexpOption) **regexp.Regexp {
+	return CommandLine.RegexpP(name, "", value, usage, opts...)
+}
+
+// RegexpP is like Regexp, but accepts a shorthand letter that can be used after a single dash.
+func RegexpP(name, shorthand string, value *regexp.Regexp, usage string, opts ...RegexpOption) **regexp.Regexp {
+	return CommandLine.RegexpP(name, shorthand, value, usage, opts...)
+}
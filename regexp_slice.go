@@ -0,0 +1,155 @@
+package pflag
+
+import (
+	"regexp"
+
+	"github.com/rsb/failure"
+)
+
+// -- regexpSlice Value
+type regexpSliceValue struct {
+	value   *[]*regexp.Regexp
+	given   []string
+	opts    RegexpOption
+	changed bool
+}
+
+func newRegexpSliceValue(val []*regexp.Regexp, p *[]*regexp.Regexp, opts []RegexpOption) *regexpSliceValue {
+	rsv := &regexpSliceValue{value: p, opts: combineRegexpOptions(opts)}
+	*rsv.value = val
+	rsv.given = make([]string, len(val))
+	return rsv
+}
+
+// Set compiles val and adds it to the patterns. Patterns are not split at
+// commas, since commas are common in patterns such as a{1,3}.
+func (s *regexpSliceValue) Set(val string) error {
+	re, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	if !s.changed {
+		*s.value, s.given = []*regexp.Regexp{re}, []string{val}
+		s.changed = true
+	} else {
+		*s.value = append(*s.value, re)
+		s.given = append(s.given, val)
+	}
+	return nil
+}
+
+func (s *regexpSliceValue) Type() string {
+	return "regexpSlice"
+}
+
+func (s *regexpSliceValue) String() string {
+	str, _ := writeAsCSV(s.GetSlice())
+	return "[" + str + "]"
+}
+
+func (s *regexpSliceValue) fromString(val string) (*regexp.Regexp, error) {
+	return compileRegexp(val, s.opts)
+}
+
+func (s *regexpSliceValue) toString(i int) string {
+	if len(s.given) == len(*s.value) && s.given[i] != "" {
+		return s.given[i]
+	}
+	return regexpPattern((*s.value)[i], s.opts)
+}
+
+func (s *regexpSliceValue) Append(val string) error {
+	re, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, re)
+	s.given = append(s.given, val)
+	return nil
+}
+
+func (s *regexpSliceValue) Replace(val []string) error {
+	out := make([]*regexp.Regexp, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	s.given = append([]string{}, val...)
+	return nil
+}
+
+func (s *regexpSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i := range *s.value {
+		out[i] = s.toString(i)
+	}
+	return out
+}
+
+// GetRegexpSlice returns the []*regexp.Regexp value of a flag with the given name
+func (f *FlagSet) GetRegexpSlice(name string) ([]*regexp.Regexp, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return []*regexp.Regexp{}, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	s, ok := flag.Value.(*regexpSliceValue)
+	if !ok {
+		return []*regexp.Regexp{}, failure.InvalidState("trying to get regexpSlice value of flag of type %s", flag.Value.Type())
+	}
+	return append([]*regexp.Regexp{}, *s.value...), nil
+}
+
+// RegexpSliceVar defines a regexpSlice flag with specified name, default value, and usage string.
+// The argument p points to a []*regexp.Regexp variable in which to store the values of the multiple flags.
+// The value of each argument is a single pattern and is not separated by comma.
+func (f *FlagSet) RegexpSliceVar(p *[]*regexp.Regexp, name string, value []*regexp.Regexp, usage string, opts ...RegexpOption) {
+	f.VarP(newRegexpSliceValue(value, p, opts), name, "", usage)
+}
+
+// RegexpSliceVarP is like RegexpSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) RegexpSliceVarP(p *[]*regexp.Regexp, name, shorthand string, value []*regexp.Regexp, usage string, opts ...RegexpOption) {
+	f.VarP(newRegexpSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// RegexpSliceVar defines a regexpSlice flag with specified name, default value, and usage string.
+// The argument p points to a []*regexp.Regexp variable in which to store the values of the multiple flags.
+// The value of each argument is a single pattern and is not separated by comma.
+func RegexpSliceVar(p *[]*regexp.Regexp, name string, value []*regexp.Regexp, usage string, opts ...RegexpOption) {
+	CommandLine.VarP(newRegexpSliceValue(value, p, opts), name, "", usage)
+}
+
+// RegexpSliceVarP is like RegexpSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func RegexpSliceVarP(p *[]*regexp.Regexp, name, shorthand string, value []*regexp.Regexp, usage string, opts ...RegexpOption) {
+	CommandLine.VarP(newRegexpSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// RegexpSlice defines a regexpSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []*regexp.Regexp variable that stores the value of the flag.
+func (f *FlagSet) RegexpSlice(name string, value []*regexp.Regexp, usage string, opts ...RegexpOption) *[]*regexp.Regexp {
+	p := []*regexp.Regexp{}
+	f.RegexpSliceVarP(&p, name, "", value, usage, opts...)
+	return &p
+}
+
+// RegexpSliceP is like RegexpSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) RegexpSliceP(name, shorthand string, value []*regexp.Regexp, usage string, opts ...RegexpOption) *[]*regexp.Regexp {
+	p := []*regexp.Regexp{}
+	f.RegexpSliceVarP(&p, name, shorthand, value, usage, opts...)
+	return &p
+}
+
+// RegexpSlice defines a regexpSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []*regexp.Regexp variable that stores the value of the flag.
+func RegexpSlice(name string, value []*regexp.Regexp, usage string, opts ...RegexpOption) *[]*regexp.Regexp {
+	return CommandLine.RegexpSliceP(name, "", value, usage, opts...)
+}
+
+// RegexpSliceP is like RegexpSlice, but accepts a shorthand letter that can be used after a single dash.
+func RegexpSliceP(name, shorthand string, value []*regexp.Regexp, usage string, opts ...RegexpOption) *[]*regexp.Regexp {
+	return CommandLine.RegexpSliceP(name, shorthand, value, usage, opts...)
+}
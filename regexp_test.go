@@ -0,0 +1,184 @@
+package pflag_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexp(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	filter := f.Regexp("filter", nil, "lines to keep")
+
+	require.NoError(t, f.Parse([]string{"--filter=err(or)?"}))
+	require.True(t, (*filter).MatchString("an error occurred"))
+	require.False(t, (*filter).MatchString("ERROR"))
+	require.Equal(t, "err(or)?", f.Lookup("filter").Value.String())
+
+	got, err := f.GetRegexp("filter")
+	require.NoError(t, err)
+	require.Same(t, *filter, got)
+
+	require.NoError(t, f.Parse([]string{"--filter="}))
+	require.Nil(t, *filter)
+
+	_, err = f.GetRegexpSlice("filter")
+	require.Error(t, err)
+}
+
+func TestRegexpErrorPosition(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{"a[b", `invalid regexp "a[b" at position 1: missing closing ]: ` + "`[b`"},
+		{"ab**", `invalid regexp "ab**" at position 2: invalid nested repetition operator: ` + "`**`"},
+		{"(ab", `invalid regexp "(ab" at position 0: missing closing ): ` + "`(ab`"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			f.Regexp("filter", nil, "lines to keep")
+
+			err := f.Parse([]string{"--filter", tc.pattern})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestRegexpOptions(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	anchored := f.Regexp("name", nil, "name", pflag.RegexpAnchored)
+	ignoreCase := f.Regexp("level", nil, "level", pflag.RegexpIgnoreCase|pflag.RegexpAnchored)
+	posix := f.RegexpP("posix", "p", nil, "posix", pflag.RegexpPOSIX)
+
+	require.NoError(t, f.Parse([]string{"--name=a|ab", "--level=warn|error", "-p", "a|ab"}))
+
+	require.True(t, (*anchored).MatchString("ab"))
+	require.False(t, (*anchored).MatchString("xab"))
+	require.Equal(t, "a|ab", f.Lookup("name").Value.String())
+
+	require.True(t, (*ignoreCase).MatchString("ERROR"))
+	require.False(t, (*ignoreCase).MatchString("ERRORS"))
+	require.Equal(t, "warn|error", f.Lookup("level").Value.String())
+
+	require.Equal(t, "ab", (*posix).FindString("abc"))
+
+	require.Error(t, f.Parse([]string{"--posix", `\d+`}))
+	require.NoError(t, f.Parse([]string{"--name", `\d+`}))
+}
+
+func TestRegexpDefault(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Regexp("include", regexp.MustCompile(`\.go$`), "files to include")
+	f.Regexp("exclude", nil, "files to exclude")
+
+	usages := f.FlagUsages()
+	require.Contains(t, usages, "files to include (default \\.go$)\n")
+	require.Contains(t, usages, "files to exclude\n")
+}
+
+func TestRegexpSlice(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	excludes := f.RegexpSlice("exclude", nil, "patterns to exclude", pflag.RegexpIgnoreCase)
+
+	require.NoError(t, f.Parse([]string{"--exclude=^a{1,3}$", "--exclude", "vendor/"}))
+	require.Len(t, *excludes, 2)
+	require.True(t, (*excludes)[0].MatchString("AA"))
+	require.True(t, (*excludes)[1].MatchString("Vendor/x"))
+	require.Equal(t, `["^a{1,3}$",vendor/]`, f.Lookup("exclude").Value.String())
+
+	got, err := f.GetRegexpSlice("exclude")
+	require.NoError(t, err)
+	require.Equal(t, *excludes, got)
+
+	err = f.Parse([]string{"--exclude=a(b"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at position 0")
+
+	sv, ok := f.Lookup("exclude").Value.(pflag.SliceValue)
+	require.True(t, ok)
+	require.NoError(t, sv.Replace([]string{"x", "y"}))
+	require.Equal(t, []string{"x", "y"}, sv.GetSlice())
+	require.Error(t, sv.Append("["))
+
+	require.Contains(t, f.FlagUsages(), "patterns to exclude")
+}
+
+func TestRegexpSliceRoundTrip(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.RegexpSlice("re", nil, "patterns")
+	require.NoError(t, f.Parse([]string{"--re=a{1,3}", "--re=b"}))
+
+	args := f.ToArgs(pflag.ArgsOptions{})
+	require.Equal(t, []string{"--re=a{1,3}", "--re=b"}, args)
+
+	g := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	res := g.RegexpSlice("re", nil, "patterns")
+	require.NoError(t, g.Parse(args))
+	require.Len(t, *res, 2)
+	require.Equal(t, "a{1,3}", (*res)[0].String())
+	require.Equal(t, "b", (*res)[1].String())
+
+	h := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	res = h.RegexpSlice("re", nil, "patterns")
+	require.NoError(t, h.ParseConfig(strings.NewReader(`{"re": "a{1,3}"}`), pflag.ConfigJSON))
+	require.Len(t, *res, 1)
+	require.Equal(t, "a{1,3}", (*res)[0].String())
+
+	require.NoError(t, h.ParseConfig(strings.NewReader(`{"re": ["a{1,3}", "b"]}`), pflag.ConfigJSON))
+	require.Len(t, *res, 2)
+	require.Equal(t, "b", (*res)[1].String())
+
+	// dotenv writes the patterns as CSV and reads them back as such
+	var buf bytes.Buffer
+	require.NoError(t, f.Export(&buf, pflag.ConfigDotenv))
+	require.Equal(t, `RE="\"a{1,3}\",b"`+"\n", buf.String())
+	d := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	res = d.RegexpSlice("re", nil, "patterns")
+	require.NoError(t, d.ParseConfig(&buf, pflag.ConfigDotenv))
+	require.Len(t, *res, 2)
+	require.Equal(t, "a{1,3}", (*res)[0].String())
+	require.Equal(t, "b", (*res)[1].String())
+}
+
+func TestRegexpPOSIX(t *testing.T) {
+	tests := []struct {
+		pattern string
+		opts    pflag.RegexpOption
+		input   string
+		match   bool
+	}{
+		{"^a", pflag.RegexpPOSIX, "b\na", true},
+		{"[^a]", pflag.RegexpPOSIX, "\n", false},
+		{"warn|error", pflag.RegexpPOSIX | pflag.RegexpIgnoreCase, "ERROR", true},
+		{"[a-c]x", pflag.RegexpPOSIX | pflag.RegexpIgnoreCase, "Bx", true},
+		{"[^a-c]x", pflag.RegexpPOSIX | pflag.RegexpIgnoreCase, "Bx", false},
+		{"[[:lower:]]+", pflag.RegexpPOSIX | pflag.RegexpIgnoreCase, "ABC", true},
+		{`\x4a`, pflag.RegexpPOSIX | pflag.RegexpIgnoreCase, "j", true},
+		{"a{1,2}", pflag.RegexpPOSIX | pflag.RegexpIgnoreCase, "A", true},
+		{"a|ab", pflag.RegexpPOSIX | pflag.RegexpAnchored, "ab", true},
+		{"a|ab", pflag.RegexpPOSIX | pflag.RegexpAnchored, "xab", false},
+		{"a|ab", pflag.RegexpPOSIX | pflag.RegexpAnchored, "x\nab", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			re := f.Regexp("re", nil, "pattern", tc.opts)
+			res := f.RegexpSlice("res", nil, "patterns", tc.opts)
+
+			require.NoError(t, f.Parse([]string{"--re", tc.pattern, "--res", tc.pattern}))
+			require.Equal(t, tc.match, (*re).MatchString(tc.input))
+			require.Equal(t, tc.match, (*res)[0].MatchString(tc.input))
+			require.Equal(t, tc.pattern, f.Lookup("re").Value.String())
+			require.Equal(t, []string{tc.pattern}, f.Lookup("res").Value.(pflag.SliceValue).GetSlice())
+		})
+	}
+}
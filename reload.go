@@ -0,0 +1,238 @@
+package pflag
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rsb/failure"
+)
+
+// Reloader re-reads a config file and applies changed values to the
+// reloadable flags of a FlagSet. Flags that were set on the command line are
+// never touched, and a config that contains an invalid value is rejected as
+// a whole. A flag whose key was in the config at the previous reload but is
+// removed from it gets back the value it had when it was marked reloadable.
+//
+// A reload writes the values of the flags on the goroutine that runs it,
+// which is the goroutine of Watch for reloads it triggers. Subscribers run on
+// that goroutine after the values are written and may read them freely,
+// other goroutines must hold RLock while they read a reloadable flag.
+type Reloader struct {
+	// OnError is called when a reload triggered by Watch fails. When nil the
+	// error is written to the Output() of the FlagSet.
+	OnError func(err error)
+
+	flags       *FlagSet
+	path        string
+	format      ConfigFormat
+	mu          sync.Mutex
+	values      sync.RWMutex
+	subscribers []func(changes []FlagChange)
+	loaded      map[*Flag]bool
+	modTime     time.Time
+	size        int64
+}
+
+// NewReloader returns a Reloader for the config file at path. The format of
+// the file is determined from its extension.
+func (f *FlagSet) NewReloader(path string) (*Reloader, error) {
+	format, err := ConfigFormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.NewReloaderFormat(path, format), nil
+}
+
+// NewReloaderFormat is like NewReloader, but uses the given format.
+func (f *FlagSet) NewReloaderFormat(path string, format ConfigFormat) *Reloader {
+	return &Reloader{
+		flags:  f,
+		path:   path,
+		format: format,
+	}
+}
+
+// Subscribe registers fn to be called after every reload that changed at
+// least one flag.
+func (r *Reloader) Subscribe(fn func(changes []FlagChange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// RLock blocks reloads from writing flag values until RUnlock is called.
+// Goroutines other than the one running Watch hold it while they read the
+// value of a reloadable flag. It must not be held while calling Reload.
+func (r *Reloader) RLock() {
+	r.values.RLock()
+}
+
+// RUnlock undoes a single RLock call.
+func (r *Reloader) RUnlock() {
+	r.values.RUnlock()
+}
+
+// Reload reads the config file and applies it to the reloadable flags. The
+// flags that changed are returned and passed to every subscriber. The
+// subscribers are called after the reload completed, so they may call
+// Subscribe or Reload themselves.
+func (r *Reloader) Reload() ([]FlagChange, error) {
+	changes, subscribers, err := r.apply()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) > 0 {
+		for _, fn := range subscribers {
+			fn(changes)
+		}
+	}
+	return changes, nil
+}
+
+// apply applies the config file and returns the changes along with the
+// subscribers to notify.
+func (r *Reloader) apply() ([]FlagChange, []func(changes []FlagChange), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if info, err := os.Stat(r.path); err == nil {
+		r.modTime, r.size = info.ModTime(), info.Size()
+	}
+
+	file, err := os.Open(r.path)
+	if err != nil {
+		return nil, nil, failure.ToConfig(err, "unable to open config file (%s)", r.path)
+	}
+	defer func() { _ = file.Close() }()
+
+	doc, err := r.flags.readConfig(file, r.format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.values.Lock()
+	defer r.values.Unlock()
+
+	reloadable := func(flag *Flag) bool {
+		return flag.Reloadable && !flag.Changed
+	}
+	// applyConfig rolls back its own values, the snapshots roll back the
+	// whole reload when reverting the removed keys fails
+	var saved []valueSnapshot
+	r.flags.VisitAll(func(flag *Flag) {
+		if reloadable(flag) {
+			saved = append(saved, snapshotValue(flag))
+		}
+	})
+	changes, err := r.flags.applyConfig(doc, reloadable)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loaded := make(map[*Flag]bool, len(doc))
+	for k := range doc {
+		if flag := r.flags.Lookup(k); flag != nil {
+			loaded[flag] = true
+		}
+	}
+	reverted, err := r.revertRemoved(loaded, reloadable)
+	if err != nil {
+		if rerr := restoreSnapshots(saved); rerr != nil {
+			return nil, nil, failure.InvalidState("%v, and unable to roll back: %v", err, rerr)
+		}
+		return nil, nil, err
+	}
+	r.loaded = loaded
+
+	if len(reverted) > 0 {
+		changes = append(changes, reverted...)
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	}
+	return changes, append([]func(changes []FlagChange){}, r.subscribers...), nil
+}
+
+// revertRemoved puts back the value of the flags that were in the config at
+// the previous reload but are not anymore.
+func (r *Reloader) revertRemoved(loaded map[*Flag]bool, fn func(flag *Flag) bool) ([]FlagChange, error) {
+	var changes []FlagChange
+	for flag := range r.loaded {
+		if loaded[flag] || !fn(flag) || flag.reloadDefault == nil {
+			continue
+		}
+
+		old := flag.Value.String()
+		secret, isSecret := flag.Value.(*secretValue)
+		oldSecret := ""
+		if isSecret {
+			oldSecret = *secret.value
+		}
+		if err := flag.reloadDefault.restore(); err != nil {
+			return nil, failure.InvalidParam("unable to reset %q flag: %v", flag.Name, err)
+		}
+
+		current := flag.Value.String()
+		if current != old || isSecret && *secret.value != oldSecret {
+			changes = append(changes, FlagChange{Name: flag.Name, Old: old, New: current})
+		}
+	}
+	return changes, nil
+}
+
+// Watch reloads the config whenever the process receives SIGHUP and, when
+// interval is greater than zero, whenever the modification time or size of
+// the file changes between polls. It blocks until ctx is done. Values are
+// written on the goroutine of Watch, see RLock for reading them from others.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-hup:
+			r.reload()
+		case <-tick:
+			if r.modified() {
+				r.reload()
+			}
+		}
+	}
+}
+
+func (r *Reloader) reload() {
+	if _, err := r.Reload(); err != nil {
+		if r.OnError != nil {
+			r.OnError(err)
+			return
+		}
+		_, _ = fmt.Fprintln(r.flags.Output(), err)
+	}
+}
+
+func (r *Reloader) modified() bool {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !info.ModTime().Equal(r.modTime) || info.Size() != r.size
+}
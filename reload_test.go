@@ -0,0 +1,331 @@
+package pflag_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpReloadFlagSet() *pflag.FlagSet {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.String("log-level", "info", "log level")
+	f.Int("rate-limit", 10, "requests per second")
+	f.StringSlice("peers", []string{}, "peer addresses")
+	f.StringToString("labels", map[string]string{}, "labels")
+	f.Int("port", 80, "listen port")
+	return f
+}
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		format pflag.ConfigFormat
+		input  string
+	}{
+		{pflag.ConfigJSON, `{"log-level": "debug", "rate-limit": 20, "peers": ["a", "b"], "labels": {"env": "prod"}}`},
+		{pflag.ConfigYAML, "log-level: debug\nrate-limit: 20\npeers: [a, b]\nlabels:\n  env: prod\n"},
+		{pflag.ConfigDotenv, "# comment\nLOG_LEVEL=debug\nexport RATE_LIMIT=20\nPEERS=\"a,b\"\nLABELS=env=prod\n"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			f := setUpReloadFlagSet()
+			require.NoError(t, f.ParseConfig(strings.NewReader(tt.input), tt.format))
+
+			level, err := f.GetString("log-level")
+			require.NoError(t, err)
+			require.Equal(t, "debug", level)
+
+			limit, err := f.GetInt("rate-limit")
+			require.NoError(t, err)
+			require.Equal(t, 20, limit)
+
+			peers, err := f.GetStringSlice("peers")
+			require.NoError(t, err)
+			require.Equal(t, []string{"a", "b"}, peers)
+
+			labels, err := f.GetStringToString("labels")
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"env": "prod"}, labels)
+
+			require.False(t, f.Changed("log-level"))
+		})
+	}
+}
+
+func TestParseConfig_CommandLineWins(t *testing.T) {
+	f := setUpReloadFlagSet()
+	require.NoError(t, f.Parse([]string{"--log-level=warn"}))
+	require.NoError(t, f.ParseConfig(strings.NewReader(`{"log-level": "debug"}`), pflag.ConfigJSON))
+
+	level, err := f.GetString("log-level")
+	require.NoError(t, err)
+	require.Equal(t, "warn", level)
+}
+
+func TestParseConfig_Atomic(t *testing.T) {
+	f := setUpReloadFlagSet()
+	err := f.ParseConfig(strings.NewReader(`{"log-level": "debug", "peers": ["a"], "rate-limit": "fast"}`), pflag.ConfigJSON)
+	require.Error(t, err)
+
+	level, err := f.GetString("log-level")
+	require.NoError(t, err)
+	require.Equal(t, "info", level)
+
+	peers, err := f.GetStringSlice("peers")
+	require.NoError(t, err)
+	require.Empty(t, peers)
+}
+
+func TestParseConfig_AtomicFiles(t *testing.T) {
+	dir := t.TempDir()
+	existing := writeConfig(t, dir, "existing", "")
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	out := f.File("out", existing, "output file", pflag.PathMustNotExist())
+	f.Int("z", 0, "a number")
+
+	err := f.ParseConfig(strings.NewReader(`{"out": "`+filepath.Join(dir, "new")+`", "z": "bad"}`), pflag.ConfigJSON)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"z" flag`)
+	require.Equal(t, existing, *out)
+}
+
+// emptyRejectingValue rejects an empty value, so it can not be put back to
+// its default by Set.
+type emptyRejectingValue struct{ value string }
+
+func (v *emptyRejectingValue) Set(s string) error {
+	if s == "" {
+		return errors.New("empty value")
+	}
+	v.value = s
+	return nil
+}
+
+func (v *emptyRejectingValue) String() string { return v.value }
+func (v *emptyRejectingValue) Type() string   { return "strict" }
+
+func TestReloader_RevertFails(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "app.yaml", "log-level: debug\nstrict: a\n")
+
+	f := setUpReloadFlagSet()
+	f.Var(&emptyRejectingValue{}, "strict", "rejects empty values")
+	require.NoError(t, f.MarkReloadable("log-level"))
+	require.NoError(t, f.MarkReloadable("strict"))
+
+	r, err := f.NewReloader(path)
+	require.NoError(t, err)
+	_, err = r.Reload()
+	require.NoError(t, err)
+
+	writeConfig(t, dir, "app.yaml", "log-level: trace\n")
+	_, err = r.Reload()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unable to reset")
+
+	level, err := f.GetString("log-level")
+	require.NoError(t, err)
+	require.Equal(t, "debug", level)
+	require.Equal(t, "a", f.Lookup("strict").Value.String())
+}
+
+func TestParseConfig_UnknownFlag(t *testing.T) {
+	f := setUpReloadFlagSet()
+	require.Error(t, f.ParseConfig(strings.NewReader(`{"nope": 1}`), pflag.ConfigJSON))
+
+	f.ParseErrorsWhitelist.UnknownFlags = true
+	require.NoError(t, f.ParseConfig(strings.NewReader(`{"nope": 1}`), pflag.ConfigJSON))
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "app.yaml", "log-level: info\nrate-limit: 10\nport: 80\n")
+
+	f := setUpReloadFlagSet()
+	require.NoError(t, f.MarkReloadable("log-level"))
+	require.NoError(t, f.MarkReloadable("rate-limit"))
+	require.NoError(t, f.Parse([]string{"--rate-limit=5"}))
+
+	r, err := f.NewReloader(path)
+	require.NoError(t, err)
+
+	var notified []pflag.FlagChange
+	r.Subscribe(func(changes []pflag.FlagChange) {
+		notified = append(notified, changes...)
+	})
+
+	writeConfig(t, dir, "app.yaml", "log-level: debug\nrate-limit: 50\nport: 8080\n")
+	changes, err := r.Reload()
+	require.NoError(t, err)
+	require.Equal(t, []pflag.FlagChange{{Name: "log-level", Old: "info", New: "debug"}}, changes)
+	require.Equal(t, changes, notified)
+
+	limit, err := f.GetInt("rate-limit")
+	require.NoError(t, err)
+	require.Equal(t, 5, limit, "flags set on the command line are not reloaded")
+
+	port, err := f.GetInt("port")
+	require.NoError(t, err)
+	require.Equal(t, 80, port, "flags that are not reloadable are not reloaded")
+}
+
+func TestReloader_RejectsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "app.json", `{"log-level": "debug", "rate-limit": "fast"}`)
+
+	f := setUpReloadFlagSet()
+	require.NoError(t, f.MarkReloadable("log-level"))
+	require.NoError(t, f.MarkReloadable("rate-limit"))
+
+	r, err := f.NewReloader(path)
+	require.NoError(t, err)
+
+	_, err = r.Reload()
+	require.Error(t, err)
+
+	level, err := f.GetString("log-level")
+	require.NoError(t, err)
+	require.Equal(t, "info", level)
+}
+
+func TestReloader_RemovedKey(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "app.yaml", "log-level: debug\nrate-limit: 50\n")
+
+	f := setUpReloadFlagSet()
+	require.NoError(t, f.MarkReloadable("log-level"))
+	require.NoError(t, f.MarkReloadable("rate-limit"))
+
+	r, err := f.NewReloader(path)
+	require.NoError(t, err)
+	_, err = r.Reload()
+	require.NoError(t, err)
+
+	writeConfig(t, dir, "app.yaml", "rate-limit: 50\n")
+	changes, err := r.Reload()
+	require.NoError(t, err)
+	require.Equal(t, []pflag.FlagChange{{Name: "log-level", Old: "debug", New: "info"}}, changes)
+
+	level, err := f.GetString("log-level")
+	require.NoError(t, err)
+	require.Equal(t, "info", level)
+}
+
+func TestReloader_SubscriberReenters(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "app.yaml", "log-level: debug\n")
+
+	f := setUpReloadFlagSet()
+	require.NoError(t, f.MarkReloadable("log-level"))
+
+	r, err := f.NewReloader(path)
+	require.NoError(t, err)
+
+	var reloaded []pflag.FlagChange
+	r.Subscribe(func(changes []pflag.FlagChange) {
+		r.Subscribe(func([]pflag.FlagChange) {})
+		reloaded, err = r.Reload()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = r.Reload()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a subscriber calling the Reloader deadlocks")
+	}
+	require.NoError(t, err)
+	require.Empty(t, reloaded)
+}
+
+func TestReloader_WatchPolls(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "app.yaml", "log-level: info\n")
+
+	f := setUpReloadFlagSet()
+	require.NoError(t, f.MarkReloadable("log-level"))
+
+	r, err := f.NewReloader(path)
+	require.NoError(t, err)
+	_, err = r.Reload()
+	require.NoError(t, err)
+
+	notified := make(chan []pflag.FlagChange, 1)
+	r.Subscribe(func(changes []pflag.FlagChange) {
+		notified <- changes
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = r.Watch(ctx, 10*time.Millisecond) }()
+
+	writeConfig(t, dir, "app.yaml", "log-level: warning\n")
+	select {
+	case changes := <-notified:
+		require.Equal(t, []pflag.FlagChange{{Name: "log-level", Old: "info", New: "warning"}}, changes)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the modified config was not reloaded")
+	}
+}
+
+func TestReloader_WatchConcurrentReads(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "app.yaml", "log-level: info\n")
+
+	f := setUpReloadFlagSet()
+	require.NoError(t, f.MarkReloadable("log-level"))
+
+	r, err := f.NewReloader(path)
+	require.NoError(t, err)
+	_, err = r.Reload()
+	require.NoError(t, err)
+
+	notified := make(chan struct{})
+	r.Subscribe(func(changes []pflag.FlagChange) {
+		close(notified)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = r.Watch(ctx, time.Millisecond) }()
+
+	read := func() string {
+		r.RLock()
+		defer r.RUnlock()
+		level, err := f.GetString("log-level")
+		require.NoError(t, err)
+		return level
+	}
+
+	writeConfig(t, dir, "app.yaml", "log-level: warning\n")
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case <-notified:
+			require.Equal(t, "warning", read())
+			return
+		case <-deadline:
+			t.Fatal("the modified config was not reloaded")
+		default:
+			require.Contains(t, []string{"info", "warning"}, read())
+		}
+	}
+}
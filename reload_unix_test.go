@@ -0,0 +1,55 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package pflag_test
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloader_WatchSIGHUP(t *testing.T) {
+	// keep SIGHUP from terminating the test before Watch listens for it
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "app.yaml", "log-level: info\n")
+
+	f := setUpReloadFlagSet()
+	require.NoError(t, f.MarkReloadable("log-level"))
+
+	r, err := f.NewReloader(path)
+	require.NoError(t, err)
+
+	notified := make(chan []pflag.FlagChange, 1)
+	r.Subscribe(func(changes []pflag.FlagChange) {
+		notified <- changes
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = r.Watch(ctx, 0) }()
+
+	writeConfig(t, dir, "app.yaml", "log-level: debug\n")
+	deadline := time.After(5 * time.Second)
+	for {
+		require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+		select {
+		case changes := <-notified:
+			require.Equal(t, []pflag.FlagChange{{Name: "log-level", Old: "info", New: "debug"}}, changes)
+			return
+		case <-time.After(20 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("SIGHUP did not reload the config")
+		}
+	}
+}
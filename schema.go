@@ -0,0 +1,268 @@
+package pflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/rsb/failure"
+)
+
+// Schema is a machine readable description of a FlagSet.
+type Schema struct {
+	Name  string       `json:"name"`
+	Flags []FlagSchema `json:"flags"`
+}
+
+// FlagSchema is a machine readable description of a flag.
+type FlagSchema struct {
+	Name                string              `json:"name"`
+	Shorthand           string              `json:"shorthand,omitempty"`
+	Type                string              `json:"type"`
+	Default             string              `json:"default"`
+	NoOptDefVal         string              `json:"noOptDefVal,omitempty"`
+	Usage               string              `json:"usage"`
+	Hidden              bool                `json:"hidden,omitempty"`
+	Deprecated          string              `json:"deprecated,omitempty"`
+	DeprecatedSince     string              `json:"deprecatedSince,omitempty"`
+	RemovedIn           string              `json:"removedIn,omitempty"`
+	Replacement         string              `json:"replacement,omitempty"`
+	ShorthandDeprecated string              `json:"shorthandDeprecated,omitempty"`
+	Sensitive           bool                `json:"sensitive,omitempty"`
+	Reloadable          bool                `json:"reloadable,omitempty"`
+	EnvVar              string              `json:"envVar,omitempty"`
+	Group               string              `json:"group,omitempty"`
+	Annotations         map[string][]string `json:"annotations,omitempty"`
+	Constraints         *FlagConstraints    `json:"constraints,omitempty"`
+}
+
+// FlagConstraints restricts the values a flag accepts. For list and map
+// flags they apply to every element.
+type FlagConstraints struct {
+	Enum    []string `json:"enum,omitempty"`
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+}
+
+// ConstrainedValue is implemented by Values that only accept part of what
+// their type can hold, so the restriction shows up in the Schema.
+type ConstrainedValue interface {
+	Constraints() FlagConstraints
+}
+
+// Schema returns a description of every flag in the FlagSet, hidden and
+// deprecated ones included. The defaults of sensitive flags are replaced with
+// Redacted.
+func (f *FlagSet) Schema() Schema {
+	s := Schema{Name: f.completionProgram(), Flags: []FlagSchema{}}
+	f.VisitAll(func(flag *Flag) {
+		fs := FlagSchema{
+			Name:                flag.Name,
+			Shorthand:           flag.Short,
+			Type:                flag.Value.Type(),
+			Default:             flag.Default,
+			NoOptDefVal:         flag.NoOptDefVal,
+			Usage:               flag.Usage,
+			Hidden:              flag.Hidden,
+			Deprecated:          flag.Deprecated,
+			ShorthandDeprecated: flag.ShortDeprecated,
+			Sensitive:           flag.Sensitive,
+			Reloadable:          flag.Reloadable,
+			EnvVar:              flag.EnvVar,
+			Group:               flag.Group,
+		}
+		if d := flag.Deprecation; d != nil {
+			fs.DeprecatedSince = d.Since
+			fs.RemovedIn = d.RemovedIn
+			fs.Replacement = d.Replacement
+		}
+		if flag.Sensitive {
+			fs.Default = Redacted
+		}
+		if len(flag.Annotations) > 0 {
+			fs.Annotations = make(map[string][]string, len(flag.Annotations))
+			for k, v := range flag.Annotations {
+				fs.Annotations[k] = v
+			}
+		}
+		if c := flagConstraints(flag); c != nil {
+			fs.Constraints = c
+		}
+		s.Flags = append(s.Flags, fs)
+	})
+	return s
+}
+
+// flagConstraints collects the constraints of a flag from its Value, the
+// range of its type and its completion values.
+func flagConstraints(flag *Flag) *FlagConstraints {
+	var c FlagConstraints
+	if cv, ok := flag.Value.(ConstrainedValue); ok {
+		c = cv.Constraints()
+	}
+
+	if c.Minimum == nil && c.Maximum == nil {
+		if lower, upper, ok := integerRange(schemaElemType(flag.Value)); ok {
+			c.Minimum = lower
+			c.Maximum = upper
+		}
+	}
+	if values := flag.Annotations[CompletionValues]; len(c.Enum) == 0 && len(values) > 0 {
+		c.Enum = values
+	}
+
+	if len(c.Enum) == 0 && c.Minimum == nil && c.Maximum == nil && c.Pattern == "" {
+		return nil
+	}
+	return &c
+}
+
+// integerRange returns the bounds of a sized integer type. Bounds that do
+// not fit a float64 exactly are left out.
+func integerRange(typ string) (lower, upper *float64, ok bool) {
+	bound := func(n float64) *float64 { return &n }
+	switch typ {
+	case "int8":
+		return bound(-1 << 7), bound(1<<7 - 1), true
+	case "int16":
+		return bound(-1 << 15), bound(1<<15 - 1), true
+	case "int32":
+		return bound(-1 << 31), bound(1<<31 - 1), true
+	case "uint8":
+		return bound(0), bound(1<<8 - 1), true
+	case "uint16":
+		return bound(0), bound(1<<16 - 1), true
+	case "uint32":
+		return bound(0), bound(1<<32 - 1), true
+	case "uint", "uint64", "count":
+		return bound(0), nil, true
+	}
+	return nil, nil, false
+}
+
+// schemaElemType returns the type of a scalar value, or of the elements of
+// a list or map value.
+func schemaElemType(value Value) string {
+	typ := value.Type()
+	switch value.(type) {
+	case SliceValue:
+		return strings.TrimSuffix(typ, "Slice")
+	case MapValue:
+		return strings.ToLower(strings.TrimPrefix(typ, "stringTo"))
+	}
+	return typ
+}
+
+// GenJSONSchema writes a JSON Schema to w that validates config files for
+// the FlagSet, as read by ParseConfig. Unknown keys are rejected unless the
+// FlagSet allows unknown flags.
+func (f *FlagSet) GenJSONSchema(w io.Writer) error {
+	properties := map[string]interface{}{}
+	f.VisitAll(func(flag *Flag) {
+		elem := map[string]interface{}{}
+		switch schemaElemType(flag.Value) {
+		case "bool":
+			elem["type"] = "boolean"
+		case "optionalBool":
+			// null and "auto" both leave the value unset
+			elem["type"] = []string{"boolean", "string", "null"}
+			elem["enum"] = []interface{}{true, false, "auto", nil}
+		case "optionalInt":
+			elem["type"] = []string{"integer", "null"}
+		case "optionalDuration":
+			elem["type"] = []string{"string", "null"}
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "count":
+			elem["type"] = "integer"
+		case "float32", "float64":
+			elem["type"] = "number"
+		default:
+			elem["type"] = "string"
+		}
+		if c := flagConstraints(flag); c != nil {
+			if len(c.Enum) > 0 {
+				elem["enum"] = c.Enum
+			}
+			if c.Minimum != nil {
+				elem["minimum"] = *c.Minimum
+			}
+			if c.Maximum != nil {
+				elem["maximum"] = *c.Maximum
+			}
+			if c.Pattern != "" {
+				elem["pattern"] = c.Pattern
+			}
+		}
+
+		property := elem
+		switch flag.Value.(type) {
+		case SliceValue:
+			property = map[string]interface{}{"type": "array", "items": elem}
+		case MapValue:
+			property = map[string]interface{}{"type": "object", "additionalProperties": elem}
+		}
+		if _, usage := UnquoteUsage(flag); usage != "" {
+			property["description"] = usage
+		}
+		if flag.Deprecated != "" {
+			property["deprecated"] = true
+		}
+		if flag.Sensitive {
+			property["writeOnly"] = true
+		} else if value := schemaDefault(flag); value != nil {
+			property["default"] = value
+		}
+		properties[flag.Name] = property
+	})
+
+	schema := map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                f.completionProgram(),
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": f.ParseErrorsWhitelist.UnknownFlags,
+	}
+	return writeJSON(w, schema)
+}
+
+// schemaDefault returns the default of a flag as a native JSON value, or nil
+// when the default cannot be represented.
+func schemaDefault(flag *Flag) interface{} {
+	switch flag.Value.(type) {
+	case SliceValue, MapValue:
+		// the default is only known in its string form, which cannot be
+		// split reliably
+		return nil
+	}
+	return exportScalar(flag.Value.Type(), flag.Default)
+}
+
+// writeJSON writes v to w as indented JSON, without escaping HTML characters.
+func writeJSON(w io.Writer, v interface{}) error {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// SetJSONHelp makes --help=json write the Schema of the FlagSet as JSON to
+// the output of the FlagSet, after which parsing stops with ErrHelp. It has no
+// effect when the FlagSet defines its own help flag.
+func (f *FlagSet) SetJSONHelp(enabled bool) {
+	f.jsonHelp = enabled
+}
+
+// helpJSON answers --help=json.
+func (f *FlagSet) helpJSON() error {
+	if err := writeJSON(f.Output(), f.Schema()); err != nil {
+		return failure.ToSystem(err, "unable to write help")
+	}
+	return ErrHelp
+}
@@ -0,0 +1,80 @@
+package pflag_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpSchemaFlagSet() *pflag.FlagSet {
+	f := setUpCompletionFlagSet()
+	f.Uint8("level", 3, "compression level")
+	f.StringToInt("limits", map[string]int{}, "limits per user")
+	f.String("token", "s3cr3t", "api token")
+	_ = f.MarkSensitive("token")
+	_ = f.MarkReloadable("level")
+	return f
+}
+
+func TestSchema(t *testing.T) {
+	s := setUpSchemaFlagSet().Schema()
+	require.Equal(t, "my-app", s.Name)
+
+	flags := map[string]pflag.FlagSchema{}
+	for _, fs := range s.Flags {
+		flags[fs.Name] = fs
+	}
+	require.Len(t, flags, 14)
+
+	require.Equal(t, pflag.FlagSchema{
+		Name:        "config",
+		Shorthand:   "c",
+		Type:        "string",
+		Usage:       "config `file` to load",
+		Annotations: map[string][]string{pflag.CompletionFilenameExt: {"yaml", ".json"}},
+	}, flags["config"])
+	require.Equal(t, []string{"json", "yaml"}, flags["format"].Constraints.Enum)
+	require.Equal(t, float64(255), *flags["level"].Constraints.Maximum)
+	require.True(t, flags["level"].Reloadable)
+	require.Equal(t, "always", flags["color"].NoOptDefVal)
+	require.True(t, flags["secret"].Hidden)
+	require.Equal(t, "use --force", flags["old"].Deprecated)
+	require.Equal(t, "use --user", flags["user"].ShorthandDeprecated)
+	require.Equal(t, pflag.Redacted, flags["token"].Default)
+	require.Nil(t, flags["name"].Constraints)
+}
+
+func TestGenJSONSchema(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, setUpSchemaFlagSet().GenJSONSchema(&buf))
+	assertGolden(t, "schema/my-app.schema.json.golden", buf.Bytes())
+}
+
+func TestJSONHelp(t *testing.T) {
+	f := setUpSchemaFlagSet()
+	var buf bytes.Buffer
+	f.SetOutput(&buf)
+
+	f.SetJSONHelp(true)
+	require.ErrorIs(t, f.Parse([]string{"--help=json"}), pflag.ErrHelp)
+
+	var s pflag.Schema
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &s))
+	require.Equal(t, f.Schema(), s)
+
+	buf.Reset()
+	require.ErrorIs(t, f.Parse([]string{"--help"}), pflag.ErrHelp)
+	require.Contains(t, buf.String(), "Usage of /usr/local/bin/my-app:")
+}
+
+func TestJSONHelpDisabled(t *testing.T) {
+	f := setUpSchemaFlagSet()
+	var buf bytes.Buffer
+	f.SetOutput(&buf)
+
+	require.ErrorIs(t, f.Parse([]string{"--help=json"}), pflag.ErrHelp)
+	require.Contains(t, buf.String(), "Usage of /usr/local/bin/my-app:")
+}
@@ -0,0 +1,245 @@
+package pflag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rsb/failure"
+)
+
+// SecretOption changes how Secret flags are defined.
+type SecretOption func(*secretOptions)
+
+type secretOptions struct {
+	fileFlag bool
+}
+
+// SecretFileFlag defines a companion <name>-file flag along with the Secret
+// flag, which reads the secret from the named file.
+func SecretFileFlag() SecretOption {
+	return func(o *secretOptions) {
+		o.fileFlag = true
+	}
+}
+
+// resolveSecret returns the secret ref refers to: the contents of the file
+// for @path, the data read from the file descriptor for fd:N and the
+// environment variable for env:NAME. Any other ref is the secret itself.
+func resolveSecret(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, "@"):
+		return readSecretFile(ref[1:])
+	case strings.HasPrefix(ref, "fd:"):
+		fd, err := strconv.Atoi(ref[3:])
+		if err != nil || fd < 0 {
+			return "", fmt.Errorf("invalid file descriptor %q", ref[3:])
+		}
+		return readSecretFD(fd)
+	case strings.HasPrefix(ref, "env:"):
+		value, ok := os.LookupEnv(ref[4:])
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", ref[4:])
+		}
+		return value, nil
+	}
+	return ref, nil
+}
+
+// readSecretFile returns the contents of the file at path without the
+// trailing line break.
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return trimSecret(data), nil
+}
+
+// readSecretFD returns the data read from the file descriptor fd. The
+// standard streams are read through os.Stdin, os.Stdout and os.Stderr, as a
+// second *os.File for them would close them once it is garbage collected.
+// Any other descriptor is closed once read.
+func readSecretFD(fd int) (string, error) {
+	var file *os.File
+	switch fd {
+	case 0:
+		file = os.Stdin
+	case 1:
+		file = os.Stdout
+	case 2:
+		file = os.Stderr
+	default:
+		file = os.NewFile(uintptr(fd), "fd:"+strconv.Itoa(fd))
+		if file == nil {
+			return "", fmt.Errorf("invalid file descriptor %d", fd)
+		}
+		defer func() { _ = file.Close() }()
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read file descriptor %d: %v", fd, unwrapPathError(err))
+	}
+	return trimSecret(data), nil
+}
+
+func trimSecret(data []byte) string {
+	return strings.TrimRight(string(data), "\r\n")
+}
+
+// -- secret Value
+type secretValue struct {
+	value *string
+}
+
+func newSecretValue(val string, p *string) *secretValue {
+	*p = val
+	return &secretValue{value: p}
+}
+
+// Set stores the secret val refers to, see SecretVar for the references.
+// Redacted is rejected, it is what String returns and no secret.
+func (s *secretValue) Set(val string) error {
+	if val == Redacted {
+		return fmt.Errorf("%s is the placeholder of a secret, not a secret", Redacted)
+	}
+	secret, err := resolveSecret(val)
+	if err != nil {
+		return err
+	}
+	*s.value = secret
+	return nil
+}
+
+func (s *secretValue) Type() string {
+	return "secret"
+}
+
+func (s *secretValue) saveState() func() {
+	secret := *s.value
+	return func() { *s.value = secret }
+}
+
+// String returns Redacted, or an empty string when no secret is set, so the
+// secret does not show up in help output, dumps or errors.
+func (s *secretValue) String() string {
+	if *s.value == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// -- secretFile Value, the companion flag defined by SecretFileFlag
+type secretFileValue struct {
+	secret *secretValue
+	path   string
+}
+
+func (s *secretFileValue) Set(val string) error {
+	secret, err := readSecretFile(val)
+	if err != nil {
+		return err
+	}
+	*s.secret.value = secret
+	s.path = val
+	return nil
+}
+
+func (s *secretFileValue) Type() string {
+	return "file"
+}
+
+func (s *secretFileValue) String() string { return s.path }
+
+func (s *secretFileValue) saveState() func() {
+	path, secret := s.path, *s.secret.value
+	return func() { s.path, *s.secret.value = path, secret }
+}
+
+// GetSecret returns the secret value of a flag with the given name
+func (f *FlagSet) GetSecret(name string) (string, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return "", failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	s, ok := flag.Value.(*secretValue)
+	if !ok {
+		return "", failure.InvalidState("trying to get secret value of flag of type %s", flag.Value.Type())
+	}
+	return *s.value, nil
+}
+
+func (f *FlagSet) varSecret(p *string, name, shorthand string, value string, usage string, opts []SecretOption) {
+	var o secretOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	secret := newSecretValue(value, p)
+	f.VarP(secret, name, shorthand, usage)
+	_ = f.MarkSensitive(name)
+
+	if o.fileFlag {
+		fileName := name + "-file"
+		f.VarP(&secretFileValue{secret: secret}, fileName, "", fmt.Sprintf("read the %s from a file", name))
+		_ = f.MarkFilename(fileName)
+	}
+}
+
+// SecretVar defines a sensitive string flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// Values of the form @path, fd:N and env:NAME are replaced with the contents of the file, the data
+// read from the file descriptor and the environment variable, the default value is used as given.
+// The value is never shown, in help output, exports and errors it is replaced with Redacted.
+func (f *FlagSet) SecretVar(p *string, name string, value string, usage string, opts ...SecretOption) {
+	f.varSecret(p, name, "", value, usage, opts)
+}
+
+// SecretVarP is like SecretVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) SecretVarP(p *string, name, shorthand string, value string, usage string, opts ...SecretOption) {
+	f.varSecret(p, name, shorthand, value, usage, opts)
+}
+
+// SecretVar defines a sensitive string flag with specified name, default value, and usage string.
+// The argument p points to a string variable in which to store the value of the flag.
+// Values of the form @path, fd:N and env:NAME are replaced with the contents of the file, the data
+// read from the file descriptor and the environment variable, the default value is used as given.
+// The value is never shown, in help output, exports and errors it is replaced with Redacted.
+func SecretVar(p *string, name string, value string, usage string, opts ...SecretOption) {
+	CommandLine.varSecret(p, name, "", value, usage, opts)
+}
+
+// SecretVarP is like SecretVar, but accepts a shorthand letter that can be used after a single dash.
+func SecretVarP(p *string, name, shorthand string, value string, usage string, opts ...SecretOption) {
+	CommandLine.varSecret(p, name, shorthand, value, usage, opts)
+}
+
+// Secret defines a sensitive string flag with specified name, default value, and usage string.
+// The return value is the address of a string variable that stores the value of the flag.
+func (f *FlagSet) Secret(name string, value string, usage string, opts ...SecretOption) *string {
+	p := new(string)
+	f.SecretVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// SecretP is like Secret, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) SecretP(name, shorthand string, value string, usage string, opts ...SecretOption) *string {
+	p := new(string)
+	f.SecretVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// Secret defines a sensitive string flag with specified name, default value, and usage string.
+// The return value is the address of a string variable that stores the value of the flag.
+func Secret(name string, value string, usage string, opts ...SecretOption) *string {
+	return CommandLine.SecretP(name, "", value, usage, opts...)
+}
+
+// SecretP is like Secret, but accepts a shorthand letter that can be used after a single dash.
+func SecretP(name, shorthand string, value string, usage string, opts ...SecretOption) *string {
+	return CommandLine.SecretP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,197 @@
+package pflag_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	password := f.SecretP("password", "p", "hunter2", "database password")
+	token := f.Secret("token", "", "api token")
+
+	require.True(t, f.Lookup("password").Sensitive)
+	require.Equal(t, pflag.Redacted, f.Lookup("password").Value.String())
+	require.Equal(t, "", f.Lookup("token").Value.String())
+
+	usages := f.FlagUsages()
+	require.NotContains(t, usages, "hunter2")
+	require.Contains(t, usages, "database password (default <redacted>)\n")
+	require.Contains(t, usages, "api token\n")
+
+	require.NoError(t, f.Parse([]string{"-p", "s3cret"}))
+	require.Equal(t, "s3cret", *password)
+	require.Equal(t, "", *token)
+
+	got, err := f.GetSecret("password")
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", got)
+	_, err = f.GetString("password")
+	require.Error(t, err)
+
+	var dump []string
+	f.Visit(func(flag *pflag.Flag) {
+		dump = append(dump, flag.Name+"="+flag.Value.String())
+	})
+	require.Equal(t, []string{"password=<redacted>"}, dump)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, f.Export(buf, pflag.ConfigJSON))
+	require.NotContains(t, buf.String(), "s3cret")
+}
+
+func TestSecretReferences(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+	t.Setenv("DB_PASSWORD", "from-env")
+
+	tests := []struct {
+		value    string
+		expected string
+		err      string
+	}{
+		{"@" + path, "from-file", ""},
+		{"env:DB_PASSWORD", "from-env", ""},
+		{"plain", "plain", ""},
+		{"@" + filepath.Join(dir, "missing"), "", "missing"},
+		{"env:DB_MISSING", "", "DB_MISSING"},
+		{"fd:x", "", `"x"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			password := f.Secret("password", "", "database password")
+
+			err := f.Parse([]string{"--password", tc.value})
+			if tc.err != "" {
+				// the error of a sensitive flag never quotes the Value
+				require.Error(t, err)
+				require.NotContains(t, err.Error(), tc.err)
+				require.Contains(t, err.Error(), `invalid argument "<redacted>" for "--password" flag: invalid value`)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, *password)
+		})
+	}
+}
+
+func TestSecretFileFlag(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	password := f.Secret("password", "", "database password", pflag.SecretFileFlag())
+
+	require.NoError(t, f.Parse([]string{"--password-file", path}))
+	require.Equal(t, "from-file", *password)
+	require.Equal(t, path, f.Lookup("password-file").Value.String())
+	require.Contains(t, f.Lookup("password-file").Annotations, pflag.CompletionFilenameExt)
+	require.Contains(t, f.FlagUsages(), "--password-file file")
+
+	got, err := f.GetFile("password-file")
+	require.NoError(t, err)
+	require.Equal(t, path, got)
+}
+
+func TestSecretToArgs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Secret("password", "", "database password", pflag.SecretFileFlag())
+	f.Secret("token", "", "api token")
+	f.String("name", "", "name")
+	require.NoError(t, f.Parse([]string{"--password-file", path, "--token", "s3cret", "--name=app"}))
+
+	args := f.ToArgs(pflag.ArgsOptions{})
+	require.Equal(t, []string{"--name=app", "--password-file=" + path}, args)
+
+	err := f.Parse([]string{"--token", pflag.Redacted})
+	require.Error(t, err)
+	got, err := f.GetSecret("token")
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", got)
+}
+
+func TestSecretEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+	t.Setenv("APP_PASSWORD_FILE", path)
+	t.Setenv("APP_USER_FILE", path)
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	password := f.Secret("password", "", "database password")
+	user := f.String("user", "", "database user")
+	require.NoError(t, f.BindEnv("password", "APP_PASSWORD"))
+	require.NoError(t, f.BindEnv("user", "APP_USER"))
+
+	require.NoError(t, f.ParseEnv())
+	require.Equal(t, "from-file", *password)
+	require.Equal(t, "", *user)
+
+	t.Setenv("APP_PASSWORD", "from-env")
+	require.NoError(t, f.ParseEnv())
+	require.Equal(t, "from-env", *password)
+}
+
+func TestSensitiveInvalidArgument(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.SetOutput(new(bytes.Buffer))
+	f.Int("pin", 0, "pin code")
+	require.NoError(t, f.MarkSensitive("pin"))
+	f.Int("old-pin", 0, "pin code")
+	require.NoError(t, f.MarkSensitive("old-pin"))
+	require.NoError(t, f.MarkDeprecatedWith("old-pin", pflag.Deprecation{
+		Message:     "use --pin",
+		Replacement: "pin",
+		Transform: func(value string) (string, error) {
+			return "", fmt.Errorf("unable to convert %s", value)
+		},
+	}))
+
+	err := f.Parse([]string{"--pin", "12ab"})
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "12ab")
+	require.Contains(t, err.Error(), `invalid argument "<redacted>" for "--pin" flag: invalid value`)
+
+	err = f.Parse([]string{"--old-pin", "9876"})
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "9876")
+	require.NotContains(t, err.Error(), "unable to convert")
+	require.Contains(t, err.Error(), `invalid argument "<redacted>" for "--old-pin" flag: invalid value`)
+
+	// the error of the Value is left out, so a short secret does not mangle
+	// it and a secret quoted differently does not leak
+	f.Int("code", 0, "one digit code")
+	require.NoError(t, f.MarkSensitive("code"))
+	err = f.Parse([]string{"--code", "a"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid argument "<redacted>" for "--code" flag: invalid value`)
+	require.NotContains(t, err.Error(), "parse")
+}
+
+func TestSecretConfigRollback(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	password := f.Secret("password", "@not-a-file", "database password")
+	f.Int("port", 0, "port")
+
+	require.NoError(t, f.ParseConfig(strings.NewReader(`{"password": "s3cret"}`), pflag.ConfigJSON))
+	require.Equal(t, "s3cret", *password)
+
+	err := f.ParseConfig(strings.NewReader(`{"password": "other", "port": "x"}`), pflag.ConfigJSON)
+	require.Error(t, err)
+	require.Equal(t, "s3cret", *password)
+}
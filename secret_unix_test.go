@@ -0,0 +1,60 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package pflag_test
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretFileDescriptor(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer func() { _ = r.Close() }()
+	_, err = w.Write([]byte("from-fd\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	// the flag closes the descriptor it reads, hand it a copy
+	fd, err := syscall.Dup(int(r.Fd()))
+	require.NoError(t, err)
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	password := f.Secret("password", "", "database password")
+
+	require.NoError(t, f.Parse([]string{"--password", fmt.Sprintf("fd:%d", fd)}))
+	require.Equal(t, "from-fd", *password)
+}
+
+func TestSecretStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer func() { _ = r.Close() }()
+	_, err = w.Write([]byte("from-stdin\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	password := f.Secret("password", "", "database password")
+
+	require.NoError(t, f.Parse([]string{"--password", "fd:0"}))
+	require.Equal(t, "from-stdin", *password)
+
+	// reading fd:0 must not leave a file behind that closes stdin once collected
+	runtime.GC()
+	runtime.GC()
+	var st syscall.Stat_t
+	require.NoError(t, syscall.Fstat(0, &st))
+	require.NoError(t, syscall.Fstat(int(r.Fd()), &st))
+}
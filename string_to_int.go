@@ -65,6 +65,27 @@ func (s *stringToIntValue) String() string {
 	return "[" + buf.String() + "]"
 }
 
+func (s *stringToIntValue) ReplaceMap(val map[string]string) error {
+	out := make(map[string]int, len(val))
+	for k, v := range val {
+		var err error
+		out[k], err = strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *stringToIntValue) GetMap() map[string]string {
+	out := make(map[string]string, len(*s.value))
+	for k, v := range *s.value {
+		out[k] = strconv.Itoa(v)
+	}
+	return out
+}
+
 func stringToIntConv(val string) (interface{}, error) {
 	val = strings.Trim(val, "[]")
 	// An empty string would cause an empty map
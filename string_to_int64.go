@@ -65,6 +65,27 @@ func (s *stringToInt64Value) String() string {
 	return "[" + buf.String() + "]"
 }
 
+func (s *stringToInt64Value) ReplaceMap(val map[string]string) error {
+	out := make(map[string]int64, len(val))
+	for k, v := range val {
+		var err error
+		out[k], err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *stringToInt64Value) GetMap() map[string]string {
+	out := make(map[string]string, len(*s.value))
+	for k, v := range *s.value {
+		out[k] = strconv.FormatInt(v, 10)
+	}
+	return out
+}
+
 func stringToInt64Conv(val string) (interface{}, error) {
 	val = strings.Trim(val, "[]")
 	// An empty string would cause an empty map
@@ -76,6 +76,23 @@ func (s *stringToStringValue) String() string {
 	return "[" + strings.TrimSpace(buf.String()) + "]"
 }
 
+func (s *stringToStringValue) ReplaceMap(val map[string]string) error {
+	out := make(map[string]string, len(val))
+	for k, v := range val {
+		out[k] = v
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *stringToStringValue) GetMap() map[string]string {
+	out := make(map[string]string, len(*s.value))
+	for k, v := range *s.value {
+		out[k] = v
+	}
+	return out
+}
+
 func stringToStringConv(val string) (interface{}, error) {
 	val = strings.Trim(val, "[]")
 	// An empty string would cause an empty map
@@ -0,0 +1,12 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package pflag
+
+import "io"
+
+// terminalWidth reports that w is not a terminal, as terminals cannot be
+// detected on this platform.
+func terminalWidth(w io.Writer) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,255 @@
+package pflag
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rsb/failure"
+)
+
+// DateLayout is the default layout of Date flags.
+const DateLayout = "2006-01-02"
+
+// DefaultTimeOfDayLayouts are the default layouts of TimeOfDay flags.
+var DefaultTimeOfDayLayouts = []string{"15:04:05", "15:04", time.Kitchen}
+
+type timeKind int
+
+const (
+	kindTime timeKind = iota
+	kindDate
+	kindTimeOfDay
+)
+
+// timeParser parses the values of Time, Date and TimeOfDay flags.
+type timeParser struct {
+	kind    timeKind
+	loc     *time.Location
+	layouts []string
+	now     func() time.Time
+}
+
+func newTimeParser(kind timeKind, loc *time.Location, layouts []string) timeParser {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if len(layouts) == 0 {
+		switch kind {
+		case kindDate:
+			layouts = []string{DateLayout}
+		case kindTimeOfDay:
+			layouts = DefaultTimeOfDayLayouts
+		default:
+			layouts = []string{time.RFC3339}
+		}
+	}
+	return timeParser{kind: kind, loc: loc, layouts: layouts, now: time.Now}
+}
+
+// parse parses s with the first layout that matches, or as a relative
+// expression: now, today, yesterday or tomorrow, optionally followed by a
+// signed duration such as now-2h, now-7d or today+9h30m. Values without a zone
+// offset are in the location of the parser. An empty string is the zero
+// time.
+func (p timeParser) parse(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if t, ok, err := p.parseRelative(s); ok {
+		if err != nil {
+			return time.Time{}, err
+		}
+		return p.normalize(t), nil
+	}
+
+	for _, layout := range p.layouts {
+		if t, err := time.ParseInLocation(layout, s, p.loc); err == nil {
+			return p.normalize(t), nil
+		}
+	}
+	if p.kind == kindTime {
+		// the notation of format when the first layout loses precision
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q does not match %s", s, strings.Join(p.layouts, " or "))
+}
+
+func (p timeParser) parseRelative(s string) (time.Time, bool, error) {
+	now := p.now().In(p.loc)
+	y, m, d := now.Date()
+	bases := []struct {
+		name string
+		t    time.Time
+	}{
+		{"now", now},
+		{"today", time.Date(y, m, d, 0, 0, 0, 0, p.loc)},
+		{"yesterday", time.Date(y, m, d-1, 0, 0, 0, 0, p.loc)},
+		{"tomorrow", time.Date(y, m, d+1, 0, 0, 0, 0, p.loc)},
+	}
+
+	lower := strings.ToLower(s)
+	for _, base := range bases {
+		if !strings.HasPrefix(lower, base.name) {
+			continue
+		}
+		rest := strings.TrimSpace(s[len(base.name):])
+		if rest == "" {
+			return base.t, true, nil
+		}
+		if rest[0] != '+' && rest[0] != '-' {
+			return time.Time{}, false, nil
+		}
+		offset, err := ParseExtendedDuration(rest[:1] + strings.TrimSpace(rest[1:]))
+		if err != nil {
+			return time.Time{}, true, fmt.Errorf("invalid offset in %q: %v", s, err)
+		}
+		return base.t.Add(offset), true, nil
+	}
+	return time.Time{}, false, nil
+}
+
+// normalize drops the time of dates and the date of times of day.
+func (p timeParser) normalize(t time.Time) time.Time {
+	switch p.kind {
+	case kindDate:
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	case kindTimeOfDay:
+		return time.Date(0, time.January, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	}
+	return t
+}
+
+// format formats t with the first layout, the zero time is empty. Times the
+// first layout cannot represent exactly, such as times with fractional
+// seconds or in another zone, are formatted as RFC 3339 with nanoseconds so
+// that parse returns them unchanged.
+func (p timeParser) format(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	s := t.Format(p.layouts[0])
+	if p.kind == kindTime {
+		parsed, err := time.ParseInLocation(p.layouts[0], s, p.loc)
+		_, offset := t.Zone()
+		_, parsedOffset := parsed.Zone()
+		if err != nil || !parsed.Equal(t) || parsedOffset != offset {
+			return t.Format(time.RFC3339Nano)
+		}
+	}
+	return s
+}
+
+func (p timeParser) typeName() string {
+	switch p.kind {
+	case kindDate:
+		return "date"
+	case kindTimeOfDay:
+		return "timeOfDay"
+	}
+	return "time"
+}
+
+// -- time.Time Value, also used by Date and TimeOfDay flags
+type timeValue struct {
+	value  *time.Time
+	parser timeParser
+}
+
+func newTimeValue(kind timeKind, val time.Time, p *time.Time, loc *time.Location, layouts []string) *timeValue {
+	*p = val
+	return &timeValue{value: p, parser: newTimeParser(kind, loc, layouts)}
+}
+
+func (t *timeValue) Set(s string) error {
+	v, err := t.parser.parse(s)
+	if err != nil {
+		return err
+	}
+	*t.value = v
+	return nil
+}
+
+func (t *timeValue) Type() string {
+	return t.parser.typeName()
+}
+
+func (t *timeValue) String() string { return t.parser.format(*t.value) }
+
+// getTime returns the value of the named Time, Date or TimeOfDay flag.
+func (f *FlagSet) getTime(name string, ftype string) (time.Time, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return time.Time{}, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	v, ok := flag.Value.(*timeValue)
+	if !ok || v.Type() != ftype {
+		return time.Time{}, failure.InvalidState("trying to get %s value of flag of type %s", ftype, flag.Value.Type())
+	}
+	return *v.value, nil
+}
+
+// GetTime return the time.Time value of a flag with the given name
+func (f *FlagSet) GetTime(name string) (time.Time, error) {
+	return f.getTime(name, "time")
+}
+
+// TimeVar defines a time.Time flag with specified name, default value, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+// Values are parsed with the first matching layout, RFC3339 when none are given, or as
+// relative expressions such as now, now-2h or yesterday. Values without a zone offset
+// and relative expressions are in loc, UTC when loc is nil.
+func (f *FlagSet) TimeVar(p *time.Time, name string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeValue(kindTime, value, p, loc, layouts), name, "", usage)
+}
+
+// TimeVarP is like TimeVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) TimeVarP(p *time.Time, name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeValue(kindTime, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// TimeVar defines a time.Time flag with specified name, default value, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+// Values are parsed with the first matching layout, RFC3339 when none are given, or as
+// relative expressions such as now, now-2h or yesterday. Values without a zone offset
+// and relative expressions are in loc, UTC when loc is nil.
+func TimeVar(p *time.Time, name string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeValue(kindTime, value, p, loc, layouts), name, "", usage)
+}
+
+// TimeVarP is like TimeVar, but accepts a shorthand letter that can be used after a single dash.
+func TimeVarP(p *time.Time, name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeValue(kindTime, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// Time defines a time.Time flag with specified name, default value, and usage string.
+// The return value is the address of a time.Time variable that stores the value of the flag.
+func (f *FlagSet) Time(name string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	p := new(time.Time)
+	f.TimeVarP(p, name, "", value, usage, loc, layouts...)
+	return p
+}
+
+// TimeP is like Time, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) TimeP(name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	p := new(time.Time)
+	f.TimeVarP(p, name, shorthand, value, usage, loc, layouts...)
+	return p
+}
+
+// Time defines a time.Time flag with specified name, default value, and usage string.
+// The return value is the address of a time.Time variable that stores the value of the flag.
+func Time(name string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	return CommandLine.TimeP(name, "", value, usage, loc, layouts...)
+}
+
+// TimeP is like Time, but accepts a shorthand letter that can be used after a single dash.
+func TimeP(name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	return CommandLine.TimeP(name, shorthand, value, usage, loc, layouts...)
+}
@@ -0,0 +1,112 @@
+package pflag
+
+import "time"
+
+// GetTimeOfDay return the time of day value of a flag with the given name
+func (f *FlagSet) GetTimeOfDay(name string) (time.Time, error) {
+	return f.getTime(name, "timeOfDay")
+}
+
+// TimeOfDayVar defines a time of day flag with specified name, default value, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+// Values are parsed with the first matching layout, DefaultTimeOfDayLayouts when none
+// are given, or as now. The time.Time is on January 1 of year 0 in loc, UTC when loc
+// is nil.
+func (f *FlagSet) TimeOfDayVar(p *time.Time, name string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeValue(kindTimeOfDay, value, p, loc, layouts), name, "", usage)
+}
+
+// TimeOfDayVarP is like TimeOfDayVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) TimeOfDayVarP(p *time.Time, name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeValue(kindTimeOfDay, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// TimeOfDayVar defines a time of day flag with specified name, default value, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+// Values are parsed with the first matching layout, DefaultTimeOfDayLayouts when none
+// are given, or as now. The time.Time is on January 1 of year 0 in loc, UTC when loc
+// is nil.
+func TimeOfDayVar(p *time.Time, name string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeValue(kindTimeOfDay, value, p, loc, layouts), name, "", usage)
+}
+
+// TimeOfDayVarP is like TimeOfDayVar, but accepts a shorthand letter that can be used after a single dash.
+func TimeOfDayVarP(p *time.Time, name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeValue(kindTimeOfDay, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// TimeOfDay defines a time of day flag with specified name, default value, and usage string.
+// The return value is the address of a time.Time variable that stores the value of the flag.
+func (f *FlagSet) TimeOfDay(name string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	p := new(time.Time)
+	f.TimeOfDayVarP(p, name, "", value, usage, loc, layouts...)
+	return p
+}
+
+// TimeOfDayP is like TimeOfDay, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) TimeOfDayP(name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	p := new(time.Time)
+	f.TimeOfDayVarP(p, name, shorthand, value, usage, loc, layouts...)
+	return p
+}
+
+// TimeOfDay defines a time of day flag with specified name, default value, and usage string.
+// The return value is the address of a time.Time variable that stores the value of the flag.
+func TimeOfDay(name string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	return CommandLine.TimeOfDayP(name, "", value, usage, loc, layouts...)
+}
+
+// TimeOfDayP is like TimeOfDay, but accepts a shorthand letter that can be used after a single dash.
+func TimeOfDayP(name, shorthand string, value time.Time, usage string, loc *time.Location, layouts ...string) *time.Time {
+	return CommandLine.TimeOfDayP(name, shorthand, value, usage, loc, layouts...)
+}
+
+// TimeOfDaySliceVar defines a timeOfDaySlice flag with specified name, default value, and usage string.
+// The argument p points to a []time.Time variable in which to store the value of the flag.
+// Each time is parsed like the value of a TimeOfDay flag.
+func (f *FlagSet) TimeOfDaySliceVar(p *[]time.Time, name string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeSliceValue(kindTimeOfDay, value, p, loc, layouts), name, "", usage)
+}
+
+// TimeOfDaySliceVarP is like TimeOfDaySliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) TimeOfDaySliceVarP(p *[]time.Time, name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeSliceValue(kindTimeOfDay, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// TimeOfDaySliceVar defines a timeOfDaySlice flag with specified name, default value, and usage string.
+// The argument p points to a []time.Time variable in which to store the value of the flag.
+// Each time is parsed like the value of a TimeOfDay flag.
+func TimeOfDaySliceVar(p *[]time.Time, name string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeSliceValue(kindTimeOfDay, value, p, loc, layouts), name, "", usage)
+}
+
+// TimeOfDaySliceVarP is like TimeOfDaySliceVar, but accepts a shorthand letter that can be used after a single dash.
+func TimeOfDaySliceVarP(p *[]time.Time, name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeSliceValue(kindTimeOfDay, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// TimeOfDaySlice defines a timeOfDaySlice flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Time variable that stores the value of the flag.
+func (f *FlagSet) TimeOfDaySlice(name string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	p := []time.Time{}
+	f.TimeOfDaySliceVarP(&p, name, "", value, usage, loc, layouts...)
+	return &p
+}
+
+// TimeOfDaySliceP is like TimeOfDaySlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) TimeOfDaySliceP(name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	p := []time.Time{}
+	f.TimeOfDaySliceVarP(&p, name, shorthand, value, usage, loc, layouts...)
+	return &p
+}
+
+// TimeOfDaySlice defines a timeOfDaySlice flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Time variable that stores the value of the flag.
+func TimeOfDaySlice(name string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	return CommandLine.TimeOfDaySliceP(name, "", value, usage, loc, layouts...)
+}
+
+// TimeOfDaySliceP is like TimeOfDaySlice, but accepts a shorthand letter that can be used after a single dash.
+func TimeOfDaySliceP(name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	return CommandLine.TimeOfDaySliceP(name, shorthand, value, usage, loc, layouts...)
+}
@@ -0,0 +1,170 @@
+package pflag
+
+import (
+	"time"
+
+	"github.com/rsb/failure"
+)
+
+// -- timeSlice Value, also used by DateSlice and TimeOfDaySlice flags
+type timeSliceValue struct {
+	value   *[]time.Time
+	parser  timeParser
+	changed bool
+}
+
+func newTimeSliceValue(kind timeKind, val []time.Time, p *[]time.Time, loc *time.Location, layouts []string) *timeSliceValue {
+	tsv := &timeSliceValue{value: p, parser: newTimeParser(kind, loc, layouts)}
+	*tsv.value = val
+	return tsv
+}
+
+// Set parses the comma-separated times in val. Times that contain a comma, as
+// in the RFC1123 layout, have to be quoted as in "Mon, 02 Jan 2006 15:04:05 MST".
+func (s *timeSliceValue) Set(val string) error {
+	ss, err := readAsCSV(val)
+	if err != nil {
+		return err
+	}
+	out := make([]time.Time, len(ss))
+	for i, d := range ss {
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+	s.changed = true
+	return nil
+}
+
+func (s *timeSliceValue) Type() string {
+	return s.parser.typeName() + "Slice"
+}
+
+func (s *timeSliceValue) String() string {
+	str, _ := writeAsCSV(s.GetSlice())
+	return "[" + str + "]"
+}
+
+func (s *timeSliceValue) fromString(val string) (time.Time, error) {
+	return s.parser.parse(val)
+}
+
+func (s *timeSliceValue) toString(val time.Time) string {
+	return s.parser.format(val)
+}
+
+func (s *timeSliceValue) Append(val string) error {
+	t, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, t)
+	return nil
+}
+
+func (s *timeSliceValue) Replace(val []string) error {
+	out := make([]time.Time, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *timeSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+// getTimeSlice returns the value of the named TimeSlice, DateSlice or
+// TimeOfDaySlice flag.
+func (f *FlagSet) getTimeSlice(name string, ftype string) ([]time.Time, error) {
+	flag := f.Lookup(name)
+	if flag == nil {
+		return []time.Time{}, failure.InvalidState("flag accessed but not defined: %s", name)
+	}
+
+	v, ok := flag.Value.(*timeSliceValue)
+	if !ok || v.Type() != ftype {
+		return []time.Time{}, failure.InvalidState("trying to get %s value of flag of type %s", ftype, flag.Value.Type())
+	}
+	return append([]time.Time{}, *v.value...), nil
+}
+
+// GetTimeSlice returns the []time.Time value of a flag with the given name
+func (f *FlagSet) GetTimeSlice(name string) ([]time.Time, error) {
+	return f.getTimeSlice(name, "timeSlice")
+}
+
+// GetDateSlice returns the []time.Time value of a dateSlice flag with the given name
+func (f *FlagSet) GetDateSlice(name string) ([]time.Time, error) {
+	return f.getTimeSlice(name, "dateSlice")
+}
+
+// GetTimeOfDaySlice returns the []time.Time value of a timeOfDaySlice flag with the given name
+func (f *FlagSet) GetTimeOfDaySlice(name string) ([]time.Time, error) {
+	return f.getTimeSlice(name, "timeOfDaySlice")
+}
+
+// TimeSliceVar defines a timeSlice flag with specified name, default value, and usage string.
+// The argument p points to a []time.Time variable in which to store the value of the flag.
+// Each time is parsed like the value of a Time flag.
+func (f *FlagSet) TimeSliceVar(p *[]time.Time, name string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeSliceValue(kindTime, value, p, loc, layouts), name, "", usage)
+}
+
+// TimeSliceVarP is like TimeSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) TimeSliceVarP(p *[]time.Time, name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	f.VarP(newTimeSliceValue(kindTime, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// TimeSliceVar defines a timeSlice flag with specified name, default value, and usage string.
+// The argument p points to a []time.Time variable in which to store the value of the flag.
+// Each time is parsed like the value of a Time flag.
+func TimeSliceVar(p *[]time.Time, name string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeSliceValue(kindTime, value, p, loc, layouts), name, "", usage)
+}
+
+// TimeSliceVarP is like TimeSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func TimeSliceVarP(p *[]time.Time, name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) {
+	CommandLine.VarP(newTimeSliceValue(kindTime, value, p, loc, layouts), name, shorthand, usage)
+}
+
+// TimeSlice defines a timeSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Time variable that stores the value of the flag.
+func (f *FlagSet) TimeSlice(name string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	p := []time.Time{}
+	f.TimeSliceVarP(&p, name, "", value, usage, loc, layouts...)
+	return &p
+}
+
+// TimeSliceP is like TimeSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) TimeSliceP(name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	p := []time.Time{}
+	f.TimeSliceVarP(&p, name, shorthand, value, usage, loc, layouts...)
+	return &p
+}
+
+// TimeSlice defines a timeSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Time variable that stores the value of the flag.
+func TimeSlice(name string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	return CommandLine.TimeSliceP(name, "", value, usage, loc, layouts...)
+}
+
+// TimeSliceP is like TimeSlice, but accepts a shorthand letter that can be used after a single dash.
+func TimeSliceP(name, shorthand string, value []time.Time, usage string, loc *time.Location, layouts ...string) *[]time.Time {
+	return CommandLine.TimeSliceP(name, shorthand, value, usage, loc, layouts...)
+}
@@ -0,0 +1,215 @@
+package pflag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	since := f.Time("since", time.Time{}, "start of the range", nil)
+	until := f.TimeP("until", "u", time.Time{}, "end of the range", berlin, time.RFC3339, "2006-01-02 15:04")
+
+	err = f.Parse([]string{"--since=2024-03-01T10:00:00+01:00", "-u", "2024-03-02 08:30"})
+	require.NoError(t, err)
+	require.True(t, since.Equal(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)))
+	require.Equal(t, time.Date(2024, 3, 2, 8, 30, 0, 0, berlin), *until)
+	require.Equal(t, "2024-03-02T08:30:00+01:00", f.Lookup("until").Value.String())
+
+	got, err := f.GetTime("until")
+	require.NoError(t, err)
+	require.Equal(t, *until, got)
+
+	require.Error(t, f.Parse([]string{"--since=2024-03-01"}))
+	require.Error(t, f.Parse([]string{"--since=nowish"}))
+
+	_, err = f.GetDate("since")
+	require.Error(t, err)
+}
+
+func TestTimeRoundTrip(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	setUp := func() *pflag.FlagSet {
+		f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		f.Time("since", time.Time{}, "start of the range", nil)
+		f.Time("until", time.Time{}, "end of the range", berlin, "2006-01-02 15:04")
+		return f
+	}
+
+	src := setUp()
+	require.NoError(t, src.Parse([]string{"--since=2024-03-01T10:00:00.123456789+05:30", "--until=2024-03-02 08:30"}))
+	require.Equal(t, "2024-03-01T10:00:00.123456789+05:30", src.Lookup("since").Value.String())
+	require.Equal(t, "2024-03-02 08:30", src.Lookup("until").Value.String())
+
+	// the layout of until has no seconds
+	require.NoError(t, src.Set("until", "2024-03-02T08:30:15+01:00"))
+	require.Equal(t, "2024-03-02T08:30:15+01:00", src.Lookup("until").Value.String())
+
+	dst := setUp()
+	require.NoError(t, dst.Parse(src.ToArgs(pflag.ArgsOptions{})))
+	for _, name := range []string{"since", "until"} {
+		want, err := src.GetTime(name)
+		require.NoError(t, err)
+		got, err := dst.GetTime(name)
+		require.NoError(t, err)
+		require.True(t, want.Equal(got), name)
+		require.Equal(t, src.Lookup(name).Value.String(), dst.Lookup(name).Value.String(), name)
+	}
+}
+
+func TestTimeRelative(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	since := f.Time("since", time.Time{}, "start of the range", time.UTC)
+
+	before := time.Now()
+	require.NoError(t, f.Parse([]string{"--since=now-2h"}))
+	after := time.Now()
+	require.False(t, since.Before(before.Add(-2*time.Hour)))
+	require.False(t, since.After(after.Add(-2*time.Hour)))
+
+	require.NoError(t, f.Parse([]string{"--since=yesterday"}))
+	y, m, d := time.Now().UTC().Date()
+	require.Equal(t, time.Date(y, m, d-1, 0, 0, 0, 0, time.UTC), *since)
+
+	require.NoError(t, f.Parse([]string{"--since", "Today + 9h30m"}))
+	require.Equal(t, time.Date(y, m, d, 9, 30, 0, 0, time.UTC), *since)
+
+	require.NoError(t, f.Parse([]string{"--since=today-1w"}))
+	require.Equal(t, time.Date(y, m, d-7, 0, 0, 0, 0, time.UTC), *since)
+
+	require.Error(t, f.Parse([]string{"--since=now-2x"}))
+}
+
+func TestDate(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	day := f.Date("day", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), "day to backfill", nil)
+
+	require.NoError(t, f.Parse([]string{}))
+	require.Equal(t, "2024-01-31", f.Lookup("day").Value.String())
+	require.Contains(t, f.FlagUsages(), "day to backfill (default 2024-01-31)")
+
+	require.NoError(t, f.Parse([]string{"--day=2024-02-29"}))
+	got, err := f.GetDate("day")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), got)
+
+	require.NoError(t, f.Parse([]string{"--day=now-24h"}))
+	require.Zero(t, day.Hour())
+	require.Zero(t, day.Minute())
+
+	require.Error(t, f.Parse([]string{"--day=2024-02-30"}))
+}
+
+func TestTimeOfDay(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	at := f.TimeOfDay("at", time.Time{}, "time to run at", time.UTC)
+
+	require.NoError(t, f.Parse([]string{"--at=09:30"}))
+	require.Equal(t, time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC), *at)
+	require.Equal(t, "09:30:00", f.Lookup("at").Value.String())
+
+	require.NoError(t, f.Parse([]string{"--at=3:04PM"}))
+	got, err := f.GetTimeOfDay("at")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(0, 1, 1, 15, 4, 0, 0, time.UTC), got)
+
+	require.Error(t, f.Parse([]string{"--at=25:00"}))
+}
+
+func TestTimeSlices(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	times := f.TimeSlice("times", nil, "times", time.UTC)
+	days := f.DateSlice("days", []time.Time{}, "days", nil)
+	f.TimeOfDaySlice("at", nil, "times of day", nil)
+
+	err := f.Parse([]string{
+		"--times=2024-03-01T10:00:00Z,2024-03-02T10:00:00Z",
+		"--days=2024-03-01", "--days=2024-03-05",
+		"--at=09:00,17:30",
+	})
+	require.NoError(t, err)
+	require.Len(t, *times, 2)
+	require.Equal(t, "[2024-03-01,2024-03-05]", f.Lookup("days").Value.String())
+
+	got, err := f.GetDateSlice("days")
+	require.NoError(t, err)
+	require.Equal(t, *days, got)
+
+	gotTimes, err := f.GetTimeSlice("times")
+	require.NoError(t, err)
+	require.Equal(t, *times, gotTimes)
+
+	at, err := f.GetTimeOfDaySlice("at")
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{
+		time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(0, 1, 1, 17, 30, 0, 0, time.UTC),
+	}, at)
+
+	sv, ok := f.Lookup("days").Value.(pflag.SliceValue)
+	require.True(t, ok)
+	require.NoError(t, sv.Replace([]string{"2024-12-24"}))
+	require.Equal(t, []string{"2024-12-24"}, sv.GetSlice())
+	require.Error(t, sv.Append("24.12.2024"))
+}
+
+func TestTimeSliceCommaLayout(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	times := f.TimeSlice("times", nil, "times", time.UTC, time.RFC1123)
+
+	require.NoError(t, f.Parse([]string{`--times="Mon, 04 Mar 2024 10:00:00 UTC","Tue, 05 Mar 2024 11:30:00 UTC"`}))
+	require.Equal(t, []time.Time{
+		time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 5, 11, 30, 0, 0, time.UTC),
+	}, *times)
+	require.Equal(t, `["Mon, 04 Mar 2024 10:00:00 UTC","Tue, 05 Mar 2024 11:30:00 UTC"]`, f.Lookup("times").Value.String())
+
+	args := f.ToArgs(pflag.ArgsOptions{})
+	g := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	again := g.TimeSlice("times", nil, "times", time.UTC, time.RFC1123)
+	require.NoError(t, g.Parse(args))
+	require.Equal(t, *times, *again)
+
+	require.Error(t, f.Parse([]string{"--times=Mon, 04 Mar 2024 10:00:00 UTC"}))
+}
+
+func TestLocation(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	tz := f.Location("tz", time.UTC, "time zone")
+
+	require.Contains(t, f.FlagUsages(), "time zone (default UTC)")
+
+	require.NoError(t, f.Parse([]string{"--tz=Europe/Berlin"}))
+	require.Equal(t, "Europe/Berlin", (*tz).String())
+
+	require.NoError(t, f.Parse([]string{"--tz=+05:30"}))
+	_, offset := time.Date(2024, 1, 1, 0, 0, 0, 0, *tz).Zone()
+	require.Equal(t, 5*3600+30*60, offset)
+
+	got, err := f.GetLocation("tz")
+	require.NoError(t, err)
+	require.Equal(t, *tz, got)
+
+	require.Error(t, f.Parse([]string{"--tz=Mars/Olympus"}))
+	require.Error(t, f.Parse([]string{"--tz=+5h"}))
+}
+
+func TestTimeZeroDefaults(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.Time("since", time.Time{}, "start of the range", nil)
+	f.TimeSlice("times", nil, "times", nil)
+	f.Location("tz", nil, "time zone")
+
+	usages := f.FlagUsages()
+	require.Contains(t, usages, "start of the range\n")
+	require.Contains(t, usages, "times\n")
+	require.Contains(t, usages, "time zone\n")
+}
@@ -0,0 +1,96 @@
+package pflag
+
+import "net/url"
+
+// -- url.URL Value
+type urlValue struct {
+	value *url.URL
+	opts  endpointOptions
+}
+
+func newURLValue(val *url.URL, p *url.URL, opts []EndpointOption) *urlValue {
+	*p = url.URL{}
+	if val != nil {
+		*p = *val
+	}
+	return &urlValue{value: p, opts: newEndpointOptions(opts)}
+}
+
+// Set parses s as a URL. An empty string sets the empty URL.
+func (v *urlValue) Set(s string) error {
+	u, err := parseURL(s, v.opts)
+	if err != nil {
+		return err
+	}
+	*v.value = *u
+	return nil
+}
+
+func (v *urlValue) Type() string {
+	return "url"
+}
+
+func (v *urlValue) String() string { return v.value.String() }
+
+func urlConv(sval string) (interface{}, error) {
+	return parseURL(sval, endpointOptions{})
+}
+
+// GetURL returns the *url.URL value of a flag with the given name
+func (f *FlagSet) GetURL(name string) (*url.URL, error) {
+	val, err := f.getFlagType(name, "url", urlConv)
+	if err != nil {
+		return nil, err
+	}
+	return val.(*url.URL), nil
+}
+
+// URLVar defines a URL flag with specified name, default value, and usage string.
+// The argument p points to a url.URL variable in which to store the value of the flag.
+// The options validate and complete the values.
+func (f *FlagSet) URLVar(p *url.URL, name string, value *url.URL, usage string, opts ...EndpointOption) {
+	f.VarP(newURLValue(value, p, opts), name, "", usage)
+}
+
+// URLVarP is like URLVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) URLVarP(p *url.URL, name, shorthand string, value *url.URL, usage string, opts ...EndpointOption) {
+	f.VarP(newURLValue(value, p, opts), name, shorthand, usage)
+}
+
+// URLVar defines a URL flag with specified name, default value, and usage string.
+// The argument p points to a url.URL variable in which to store the value of the flag.
+// The options validate and complete the values.
+func URLVar(p *url.URL, name string, value *url.URL, usage string, opts ...EndpointOption) {
+	CommandLine.VarP(newURLValue(value, p, opts), name, "", usage)
+}
+
+// URLVarP is like URLVar, but accepts a shorthand letter that can be used after a single dash.
+func URLVarP(p *url.URL, name, shorthand string, value *url.URL, usage string, opts ...EndpointOption) {
+	CommandLine.VarP(newURLValue(value, p, opts), name, shorthand, usage)
+}
+
+// URL defines a URL flag with specified name, default value, and usage string.
+// The return value is the address of a url.URL variable that stores the value of the flag.
+func (f *FlagSet) URL(name string, value *url.URL, usage string, opts ...EndpointOption) *url.URL {
+	p := new(url.URL)
+	f.URLVarP(p, name, "", value, usage, opts...)
+	return p
+}
+
+// URLP is like URL, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) URLP(name, shorthand string, value *url.URL, usage string, opts ...EndpointOption) *url.URL {
+	p := new(url.URL)
+	f.URLVarP(p, name, shorthand, value, usage, opts...)
+	return p
+}
+
+// URL defines a URL flag with specified name, default value, and usage string.
+// The return value is the address of a url.URL variable that stores the value of the flag.
+func URL(name string, value *url.URL, usage string, opts ...EndpointOption) *url.URL {
+	return CommandLine.URLP(name, "", value, usage, opts...)
+}
+
+// URLP is like URL, but accepts a shorthand letter that can be used after a single dash.
+func URLP(name, shorthand string, value *url.URL, usage string, opts ...EndpointOption) *url.URL {
+	return CommandLine.URLP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,182 @@
+package pflag
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// -- urlSlice Value
+type urlSliceValue struct {
+	value   *[]*url.URL
+	opts    endpointOptions
+	changed bool
+}
+
+func newURLSliceValue(val []*url.URL, p *[]*url.URL, opts []EndpointOption) *urlSliceValue {
+	v := &urlSliceValue{value: p, opts: newEndpointOptions(opts)}
+	*v.value = val
+	return v
+}
+
+// Set converts, and assigns, the comma-separated argument string representation as the []*url.URL value of this flag.
+// Values containing a comma can be quoted as in CSV.
+// If Set is called on a flag that already has a []*url.URL assigned, the newly converted values will be appended.
+func (s *urlSliceValue) Set(val string) error {
+	strSlice, err := readAsCSV(val)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	out := make([]*url.URL, 0, len(strSlice))
+	for _, str := range strSlice {
+		v, err := s.fromString(str)
+		if err != nil {
+			return err
+		}
+		out = append(out, v)
+	}
+
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+
+	s.changed = true
+
+	return nil
+}
+
+// Type returns a string that uniquely represents this flag's type.
+func (s *urlSliceValue) Type() string {
+	return "urlSlice"
+}
+
+// String defines a "native" format for this *url.URL slice flag value.
+func (s *urlSliceValue) String() string {
+	out, _ := writeAsCSV(s.GetSlice())
+	return "[" + out + "]"
+}
+
+func (s *urlSliceValue) fromString(val string) (*url.URL, error) {
+	if strings.TrimSpace(val) == "" {
+		return nil, fmt.Errorf("empty string being converted to URL")
+	}
+	return parseURL(val, s.opts)
+}
+
+func (s *urlSliceValue) toString(val *url.URL) string {
+	return val.String()
+}
+
+func (s *urlSliceValue) Append(val string) error {
+	v, err := s.fromString(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, v)
+	return nil
+}
+
+func (s *urlSliceValue) Replace(val []string) error {
+	out := make([]*url.URL, len(val))
+	for i, d := range val {
+		var err error
+		out[i], err = s.fromString(d)
+		if err != nil {
+			return err
+		}
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *urlSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for i, d := range *s.value {
+		out[i] = s.toString(d)
+	}
+	return out
+}
+
+func urlSliceConv(val string) (interface{}, error) {
+	val = strings.TrimSuffix(strings.TrimPrefix(val, "["), "]")
+	// Empty string would cause a slice with one (empty) entry
+	if len(val) == 0 {
+		return []*url.URL{}, nil
+	}
+	ss, err := readAsCSV(val)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*url.URL, len(ss))
+	for i, sval := range ss {
+		v, err := parseURL(sval, endpointOptions{})
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// GetURLSlice returns the []*url.URL value of a flag with the given name
+func (f *FlagSet) GetURLSlice(name string) ([]*url.URL, error) {
+	val, err := f.getFlagType(name, "urlSlice", urlSliceConv)
+	if err != nil {
+		return []*url.URL{}, err
+	}
+	return val.([]*url.URL), nil
+}
+
+// URLSliceVar defines a urlSlice flag with specified name, default value, and usage string.
+// The argument p points to a []*url.URL variable in which to store the value of the flag.
+// The options validate and complete the values.
+func (f *FlagSet) URLSliceVar(p *[]*url.URL, name string, value []*url.URL, usage string, opts ...EndpointOption) {
+	f.VarP(newURLSliceValue(value, p, opts), name, "", usage)
+}
+
+// URLSliceVarP is like URLSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) URLSliceVarP(p *[]*url.URL, name, shorthand string, value []*url.URL, usage string, opts ...EndpointOption) {
+	f.VarP(newURLSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// URLSliceVar defines a urlSlice flag with specified name, default value, and usage string.
+// The argument p points to a []*url.URL variable in which to store the value of the flag.
+// The options validate and complete the values.
+func URLSliceVar(p *[]*url.URL, name string, value []*url.URL, usage string, opts ...EndpointOption) {
+	CommandLine.VarP(newURLSliceValue(value, p, opts), name, "", usage)
+}
+
+// URLSliceVarP is like URLSliceVar, but accepts a shorthand letter that can be used after a single dash.
+func URLSliceVarP(p *[]*url.URL, name, shorthand string, value []*url.URL, usage string, opts ...EndpointOption) {
+	CommandLine.VarP(newURLSliceValue(value, p, opts), name, shorthand, usage)
+}
+
+// URLSlice defines a urlSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []*url.URL variable that stores the value of that flag.
+func (f *FlagSet) URLSlice(name string, value []*url.URL, usage string, opts ...EndpointOption) *[]*url.URL {
+	p := []*url.URL{}
+	f.URLSliceVarP(&p, name, "", value, usage, opts...)
+	return &p
+}
+
+// URLSliceP is like URLSlice, but accepts a shorthand letter that can be used after a single dash.
+func (f *FlagSet) URLSliceP(name, shorthand string, value []*url.URL, usage string, opts ...EndpointOption) *[]*url.URL {
+	p := []*url.URL{}
+	f.URLSliceVarP(&p, name, shorthand, value, usage, opts...)
+	return &p
+}
+
+// URLSlice defines a urlSlice flag with specified name, default value, and usage string.
+// The return value is the address of a []*url.URL variable that stores the value of the flag.
+func URLSlice(name string, value []*url.URL, usage string, opts ...EndpointOption) *[]*url.URL {
+	return CommandLine.URLSliceP(name, "", value, usage, opts...)
+}
+
+// URLSliceP is like URLSlice, but accepts a shorthand letter that can be used after a single dash.
+func URLSliceP(name, shorthand string, value []*url.URL, usage string, opts ...EndpointOption) *[]*url.URL {
+	return CommandLine.URLSliceP(name, shorthand, value, usage, opts...)
+}
@@ -0,0 +1,295 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/rsb/failure"
+)
+
+// DefaultUsageTemplate is the template of the usage message printed when
+// parsing fails or help is requested, unless a UsageRenderer is set.
+const DefaultUsageTemplate = `{{message "usage" .Name}}
+{{flagUsages .}}`
+
+// UsageRenderer writes the usage message of a FlagSet.
+type UsageRenderer interface {
+	RenderUsage(w io.Writer, f *FlagSet) error
+}
+
+// UsageRendererFunc is an ordinary function used as a UsageRenderer.
+type UsageRendererFunc func(w io.Writer, f *FlagSet) error
+
+// RenderUsage calls fn(w, f).
+func (fn UsageRendererFunc) RenderUsage(w io.Writer, f *FlagSet) error {
+	return fn(w, f)
+}
+
+// SetUsageRenderer sets the renderer of the default usage message. A nil
+// renderer restores DefaultUsageTemplate. It has no effect when Usage is set.
+func (f *FlagSet) SetUsageRenderer(r UsageRenderer) {
+	f.usageRenderer = r
+}
+
+// ColorMode controls whether help output is styled with ANSI escape
+// sequences.
+type ColorMode int
+
+const (
+	// ColorAuto styles help output written to a terminal, unless the
+	// NO_COLOR environment variable is set to a non-empty value or TERM is dumb
+	ColorAuto ColorMode = iota
+	// ColorAlways always styles help output
+	ColorAlways
+	// ColorNever never styles help output
+	ColorNever
+)
+
+// SetColor sets when help output is styled, ColorAuto by default.
+func (f *FlagSet) SetColor(mode ColorMode) {
+	f.color = mode
+}
+
+// colorEnabled returns whether help output written to w is styled.
+func (f *FlagSet) colorEnabled(w io.Writer) bool {
+	switch f.color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	_, ok := terminalWidth(w)
+	return ok
+}
+
+const (
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[2m"
+	ansiUnderline = "\x1b[4m"
+	ansiReset     = "\x1b[0m"
+)
+
+// UsageSection is a titled list of flags in help output.
+type UsageSection struct {
+	Title string // empty when no flag has a group
+	Flags []UsageFlag
+}
+
+// UsageData is what usage templates are executed with.
+type UsageData struct {
+	Name     string
+	Width    int  // columns to wrap to, 0 for no wrapping
+	Color    bool // whether ANSI styles are applied
+	Sections []UsageSection
+}
+
+// UsageSections splits the flags shown in help output into sections, as
+// described in FlagUsagesWrapped. Without groups there is a single section
+// without a title.
+func (f *FlagSet) UsageSections() []UsageSection {
+	var order []string
+	grouped := map[string][]UsageFlag{}
+	var other []UsageFlag
+	f.VisitAll(func(flag *Flag) {
+		if flag.Hidden {
+			return
+		}
+		if flag.Group == "" {
+			other = append(other, f.newFlagUsage(flag))
+			return
+		}
+		if _, ok := grouped[flag.Group]; !ok {
+			order = append(order, flag.Group)
+		}
+		grouped[flag.Group] = append(grouped[flag.Group], f.newFlagUsage(flag))
+	})
+	if len(grouped) == 0 {
+		return []UsageSection{{Flags: other}}
+	}
+
+	var sections []UsageSection
+	for _, group := range append(append([]string{}, f.groupOrder...), order...) {
+		if flags, ok := grouped[group]; ok {
+			sections = append(sections, UsageSection{Title: group, Flags: flags})
+			delete(grouped, group)
+		}
+	}
+	if len(other) > 0 {
+		sections = append(sections, UsageSection{Title: f.message(MsgOtherFlags), Flags: other})
+	}
+	return sections
+}
+
+// TemplateRenderer renders the usage message with a text/template, which is
+// executed with UsageData. Besides the builtin functions the template can use:
+//
+//	message id args  the message id of the catalog formatted with args
+//	flagUsages .     the flag table of every section, like PrintDefaults
+//	flagTable .Flags the aligned flag table of one section
+//	bold, dim, underline
+//	                 style text when .Color is set
+//	wrap indent text wrap text to .Width, indenting continuation lines
+//	width text       the display width of text
+//	pad n text       pad text with spaces to a display width of n
+type TemplateRenderer struct {
+	tmpl *template.Template
+}
+
+// NewTemplateRenderer parses text into a TemplateRenderer.
+func NewTemplateRenderer(text string) (*TemplateRenderer, error) {
+	tmpl, err := template.New("usage").Funcs(usageFuncs(UsageData{}, English)).Parse(text)
+	if err != nil {
+		return nil, failure.ToInvalidParam(err, "unable to parse usage template")
+	}
+	return &TemplateRenderer{tmpl: tmpl}, nil
+}
+
+// RenderUsage executes the template for f and writes the result to w. When w
+// is a terminal the text is wrapped to its width.
+func (r *TemplateRenderer) RenderUsage(w io.Writer, f *FlagSet) error {
+	data := UsageData{
+		Name:     f.name,
+		Color:    f.colorEnabled(w),
+		Sections: f.UsageSections(),
+	}
+	data.Width, _ = terminalWidth(w)
+
+	tmpl, err := r.tmpl.Clone()
+	if err != nil {
+		return failure.ToSystem(err, "unable to clone usage template")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Funcs(usageFuncs(data, f.messageCatalog())).Execute(buf, data); err != nil {
+		return failure.ToSystem(err, "unable to render usage template")
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+var defaultUsageRenderer, _ = NewTemplateRenderer(DefaultUsageTemplate)
+
+// usageFuncs returns the functions available to usage templates.
+func usageFuncs(data UsageData, catalog MessageCatalog) template.FuncMap {
+	style := func(code string) func(string) string {
+		return func(s string) string {
+			if !data.Color || s == "" {
+				return s
+			}
+			return code + s + ansiReset
+		}
+	}
+
+	return template.FuncMap{
+		"flagUsages": func(d UsageData) string {
+			buf := new(bytes.Buffer)
+			writeUsageSections(buf, d.Sections, d.Width, d.Color, catalog)
+			return buf.String()
+		},
+		"flagTable": func(flags []UsageFlag) string {
+			buf := new(bytes.Buffer)
+			writeFlagUsages(buf, flags, data.Width, data.Color, catalog)
+			return buf.String()
+		},
+		"message": func(id string, args ...interface{}) string {
+			return catalog.Message(MessageID(id), args...)
+		},
+		"bold":      style(ansiBold),
+		"dim":       style(ansiDim),
+		"underline": style(ansiUnderline),
+		"wrap": func(indent int, s string) string {
+			return wrap(indent, data.Width, s)
+		},
+		"width": displayWidth,
+		"pad": func(n int, s string) string {
+			if w := displayWidth(s); w < n {
+				return s + strings.Repeat(" ", n-w)
+			}
+			return s
+		},
+	}
+}
+
+// writeUsageSections writes the flag table of every section to buf, each
+// group under its title.
+func writeUsageSections(buf *bytes.Buffer, sections []UsageSection, cols int, color bool, catalog MessageCatalog) {
+	for i, section := range sections {
+		if section.Title != "" {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			title := section.Title + ":"
+			if color {
+				title = ansiBold + title + ansiReset
+			}
+			buf.WriteString(title + "\n")
+		}
+		writeFlagUsages(buf, section.Flags, cols, color, catalog)
+	}
+}
+
+// writeFlagUsages writes a line for each flag to buf, with the usage texts
+// aligned in a column. Alignment and wrapping go by display width, so wide
+// characters take two columns.
+func writeFlagUsages(buf *bytes.Buffer, flags []UsageFlag, cols int, color bool, catalog MessageCatalog) {
+	bold := func(s string) string {
+		if !color {
+			return s
+		}
+		return ansiBold + s + ansiReset
+	}
+
+	type usageLine struct {
+		left   string // the flag column, which determines the alignment
+		styled string // the flag column as written
+		usage  string
+	}
+	lines := make([]usageLine, 0, len(flags))
+
+	maxlen := 0
+	for _, u := range flags {
+		var line usageLine
+		if u.Short != "" {
+			line.left = fmt.Sprintf("  -%s, --%s", u.Short, u.Name)
+			line.styled = fmt.Sprintf("  %s, %s", bold("-"+u.Short), bold("--"+u.Name))
+		} else {
+			line.left = fmt.Sprintf("      --%s", u.Name)
+			line.styled = "      " + bold("--"+u.Name)
+		}
+		if u.Varname != "" {
+			line.left += " " + u.Varname
+			line.styled += " " + u.Varname
+		}
+		line.left += u.NoOptDefVal
+		line.styled += u.NoOptDefVal
+
+		if w := displayWidth(line.left); w > maxlen {
+			maxlen = w
+		}
+
+		line.usage = u.Usage
+		if u.Default != "" {
+			line.usage += " " + catalog.Message(MsgDefault, u.Default)
+		}
+		if u.Deprecated != "" {
+			line.usage += " " + catalog.Message(MsgDeprecated, u.Deprecated)
+		}
+
+		lines = append(lines, line)
+	}
+
+	for _, line := range lines {
+		spacing := strings.Repeat(" ", maxlen-displayWidth(line.left)+1)
+		// maxlen + 3 is the width of the flag column and the spacing
+		// around it
+		_, _ = fmt.Fprintln(buf, line.styled, spacing, wrap(maxlen+3, cols, line.usage))
+	}
+}
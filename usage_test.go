@@ -0,0 +1,180 @@
+package pflag_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/rsb/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpUsageFlagSet() *pflag.FlagSet {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.StringP("config", "c", "", "config `file` to load")
+	f.String("name", "app", "name of the app")
+	f.Int("workers", 4, "number of workers")
+	f.BoolP("force", "f", false, "skip confirmation")
+	f.CountP("verbose", "v", "increase verbosity")
+	f.Duration("timeout", time.Second, "request timeout")
+	f.StringSlice("tags", []string{"a", "b"}, "tags to apply")
+	f.String("color", "auto", "colorize output")
+	f.Lookup("color").NoOptDefVal = "always"
+	f.Bool("old", false, "deprecated flag")
+	_ = f.MarkDeprecated("old", "use --force")
+	f.Int("legacy", 0, "legacy flag")
+	f.Lookup("legacy").Deprecated = "use --workers"
+	return f
+}
+
+func TestFlagUsages(t *testing.T) {
+	f := setUpUsageFlagSet()
+	require.Equal(t, `      --color string[="always"]   colorize output (default "auto")
+  -c, --config file               config file to load
+  -f, --force                     skip confirmation
+      --legacy int                legacy flag (DEPRECATED: use --workers)
+      --name string               name of the app (default "app")
+      --tags strings              tags to apply (default [a,b])
+      --timeout duration          request timeout (default 1s)
+  -v, --verbose count             increase verbosity
+      --workers int               number of workers (default 4)
+`, f.FlagUsages())
+}
+
+func TestFlagUsagesWrapped(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.String("name", "", "a long usage text that has to be wrapped because it does not fit")
+	require.Equal(t, `      --name string   a long usage text that
+                      has to be wrapped
+                      because it does not fit
+`, f.FlagUsagesWrapped(50))
+}
+
+func TestFlagUsagesGroups(t *testing.T) {
+	f := setUpUsageFlagSet()
+	require.NoError(t, f.SetGroup("workers", "Performance"))
+	require.NoError(t, f.SetGroup("timeout", "Networking"))
+	require.NoError(t, f.SetGroup("config", "Networking"))
+	require.NoError(t, f.SetGroup("verbose", "Output"))
+	require.NoError(t, f.SetGroup("color", "Output"))
+	f.SetGroupOrder("Output", "Missing")
+
+	require.Equal(t, `Output:
+      --color string[="always"]   colorize output (default "auto")
+  -v, --verbose count             increase verbosity
+
+Networking:
+  -c, --config file        config file to load
+      --timeout duration   request timeout (default 1s)
+
+Performance:
+      --workers int   number of workers (default 4)
+
+Other Flags:
+  -f, --force          skip confirmation
+      --legacy int     legacy flag (DEPRECATED: use --workers)
+      --name string    name of the app (default "app")
+      --tags strings   tags to apply (default [a,b])
+`, f.FlagUsages())
+}
+
+func TestSetGroupErrors(t *testing.T) {
+	f := setUpUsageFlagSet()
+	require.Error(t, f.SetGroup("missing", "Networking"))
+	require.Error(t, f.SetGroup("name", ""))
+}
+
+func TestFlagUsagesWideCharacters(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.String("名前", "", "アプリの名前")
+	f.String("name", "", "name of the app")
+	require.Equal(t, `      --name string   name of the app
+      --名前 string   アプリの名前
+`, f.FlagUsages())
+
+	f = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.String("name", "", "アプリケーションの名前を設定します。名前は一意でなければなりません。")
+	require.Equal(t, `      --name string   アプリケーションの名前を設
+                      定します。名前は一意でなけ
+                      ればなりません。
+`, f.FlagUsagesWrapped(54))
+}
+
+func TestTemplateRenderer(t *testing.T) {
+	f := setUpUsageFlagSet()
+	require.NoError(t, f.SetGroup("config", "Files"))
+	r, err := pflag.NewTemplateRenderer(`{{bold "Usage:"}} {{.Name}} [flags]
+{{range .Sections}}{{if .Title}}{{underline .Title}}
+{{end}}{{range .Flags}}  {{pad 10 .Name}}{{wrap 12 .Usage}}
+{{end}}{{end}}`)
+	require.NoError(t, err)
+	f.SetUsageRenderer(r)
+
+	var buf bytes.Buffer
+	f.SetOutput(&buf)
+	require.ErrorIs(t, f.Parse([]string{"--help"}), pflag.ErrHelp)
+	require.Equal(t, `Usage: test [flags]
+Files
+  config    config file to load
+Other Flags
+  color     colorize output
+  force     skip confirmation
+  legacy    legacy flag
+  name      name of the app
+  tags      tags to apply
+  timeout   request timeout
+  verbose   increase verbosity
+  workers   number of workers
+`, buf.String())
+
+	_, err = pflag.NewTemplateRenderer("{{.Name")
+	require.Error(t, err)
+}
+
+func TestUsageColor(t *testing.T) {
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.StringP("name", "n", "", "name of the app")
+	f.Int("workers", 4, "number of workers")
+	require.NoError(t, f.SetGroup("workers", "Performance"))
+
+	var buf bytes.Buffer
+	f.SetOutput(&buf)
+	f.PrintDefaults()
+	require.NotContains(t, buf.String(), "\x1b[")
+
+	buf.Reset()
+	f.SetColor(pflag.ColorAlways)
+	f.PrintDefaults()
+	require.Equal(t, "\x1b[1mPerformance:\x1b[0m\n"+
+		"      \x1b[1m--workers\x1b[0m int   number of workers (default 4)\n"+
+		"\n"+
+		"\x1b[1mOther Flags:\x1b[0m\n"+
+		"  \x1b[1m-n\x1b[0m, \x1b[1m--name\x1b[0m string   name of the app\n", buf.String())
+}
+
+func TestDefaultUsage(t *testing.T) {
+	f := setUpUsageFlagSet()
+	var buf bytes.Buffer
+	f.SetOutput(&buf)
+	require.ErrorIs(t, f.Parse([]string{"--help"}), pflag.ErrHelp)
+	require.Equal(t, "Usage of test:\n"+f.FlagUsages(), buf.String())
+}
+
+func TestSensitiveDefaults(t *testing.T) {
+	f := pflag.NewFlagSet("app", pflag.ContinueOnError)
+	f.String("token", "s3cr3t", "api token")
+	f.String("name", "app", "name of the app")
+	require.NoError(t, f.MarkSensitive("token"))
+
+	require.Contains(t, f.FlagUsages(), `api token (default <redacted>)`)
+	require.NotContains(t, f.FlagUsages(), "s3cr3t")
+
+	var buf bytes.Buffer
+	require.NoError(t, f.GenManPage(&buf, pflag.ManPageOptions{}))
+	require.Contains(t, buf.String(), "api token (default <redacted>)")
+	require.NotContains(t, buf.String(), "s3cr3t")
+
+	buf.Reset()
+	require.NoError(t, f.GenMarkdown(&buf))
+	require.NotContains(t, buf.String(), "s3cr3t")
+}
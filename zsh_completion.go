@@ -0,0 +1,144 @@
+package pflag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GenZshCompletion writes a zsh completion script for the flags in the
+// FlagSet to w. The script can be put on $fpath as _<program> or sourced.
+func (f *FlagSet) GenZshCompletion(w io.Writer) error {
+	program := f.completionProgram()
+	fn := "_" + completionIdentifier(program)
+
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, "#compdef %s\n\n", program)
+	_, _ = fmt.Fprintf(buf, "%s() {\n  _arguments -s \\\n", fn)
+	for _, cf := range f.completionFlags() {
+		_, _ = fmt.Fprintf(buf, "    %s \\\n", zshFlagSpec(cf))
+	}
+	buf.WriteString("    '*:file:_files'\n}\n\n")
+	_, _ = fmt.Fprintf(buf, "if [ \"$funcstack[1]\" = \"%s\" ]; then\n  %s \"$@\"\nelse\n  compdef %s %s\nfi\n", fn, fn, fn, program)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// zshFlagSpec returns the _arguments specification of a flag.
+func zshFlagSpec(cf completionFlag) string {
+	desc := "[" + zshEscape(cf.usage) + "]"
+
+	var value string
+	switch {
+	case cf.takesValue:
+		value = ":" + zshEscape(cf.placeholder) + ":" + zshEscape(zshAction(cf))
+	case cf.optional:
+		value = "::" + zshEscape(cf.placeholder) + ":" + zshEscape(zshAction(cf))
+	}
+
+	repeat := ""
+	if cf.repeatable {
+		repeat = "*"
+	}
+
+	long := "--" + cf.name
+	if cf.optional {
+		long += "=-"
+	}
+	if cf.short == "" || cf.optional {
+		return "'" + repeat + long + desc + value + "'"
+	}
+
+	short := "-" + cf.short
+	group := ""
+	if !cf.repeatable {
+		group = "'(" + short + " --" + cf.name + ")'"
+	} else {
+		group = "'*'"
+	}
+	return group + "{" + short + "," + long + "}'" + desc + value + "'"
+}
+
+func zshAction(cf completionFlag) string {
+	switch {
+	case len(cf.values) > 0:
+		return "(" + strings.Join(cf.values, " ") + ")"
+	case cf.dirs:
+		return "_files -/"
+	case len(cf.extensions) > 0:
+		return `_files -g "*.(` + strings.Join(cf.extensions, "|") + `)"`
+	case cf.files:
+		return "_files"
+	}
+	return " "
+}
+
+// zshEscape escapes the characters that are special in an _arguments
+// specification written inside single quotes.
+func zshEscape(s string) string {
+	return strings.NewReplacer(
+		"'", `'\''`,
+		"[", `\[`,
+		"]", `\]`,
+		":", `\:`,
+	).Replace(s)
+}
+
+// GenZshDynamicCompletion writes a zsh completion script to w that asks the
+// program itself for candidates through CompleteCommand.
+func (f *FlagSet) GenZshDynamicCompletion(w io.Writer) error {
+	program := f.completionProgram()
+	fn := "_" + completionIdentifier(program)
+
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, "#compdef %s\n\n", program)
+	_, _ = fmt.Fprintf(buf, `%s() {
+  local out directive line
+  local -a lines completions
+
+  out=$(${words[1]} %s "${(@)words[2,CURRENT]}" 2>/dev/null) || return 1
+  lines=("${(@f)out}")
+  directive=${lines[-1]#:}
+  lines=("${(@)lines[1,-2]}")
+
+  (( directive & %d )) && return 1
+
+  if (( directive & (%d | %d) )) || (( ${#lines} == 0 && !(directive & %d) )); then
+    # complete file names after the '=' of --flag=value
+    [[ $PREFIX == -*=* ]] && compset -P '*='
+    if (( directive & %d )); then
+      _files -/
+    elif (( directive & %d )); then
+      _files -g "*.(${(j:|:)lines})"
+    else
+      _files
+    fi
+    return
+  fi
+
+  for line in $lines; do
+    completions+=("${${line%%%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+  done
+  (( ${#completions} > 0 )) || return
+
+  if (( directive & %d )); then
+    _describe -t values 'values' completions -S ''
+  else
+    _describe -t values 'values' completions
+  fi
+}
+
+if [ "$funcstack[1]" = "%s" ]; then
+  %s "$@"
+else
+  compdef %s %s
+fi
+`, fn, CompleteCommand, CompletionError,
+		CompletionFilterDirs, CompletionFilterFileExt, CompletionNoFileComp, CompletionFilterDirs, CompletionFilterFileExt,
+		CompletionNoSpace, fn, fn, fn, program)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}